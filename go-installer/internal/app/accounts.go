@@ -0,0 +1,155 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// generatedPasswordLength is the length of passwords made by --generate
+const generatedPasswordLength = 20
+
+// passwordAlphabet avoids characters that need quoting in YAML or shells
+const passwordAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789-_.+="
+
+// accounts dispatches the BMC account subcommands: list (default), password,
+// create, enable and disable
+func (a *EnhancedApp) accounts(ctx context.Context, args []string) error {
+	subcommand := "list"
+	if len(args) > 0 {
+		subcommand, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("accounts "+subcommand, flag.ContinueOnError)
+	role := fs.String("role", "Administrator", "role of a new account: Administrator, Operator or ReadOnly")
+	generate := fs.Bool("generate", false, "generate a random password and print it")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from the first line of stdin")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	client := a.bmc.Redfish()
+	switch subcommand {
+	case "list":
+		return a.listAccounts(ctx)
+	case "password":
+		if fs.NArg() != 1 {
+			return usagef("usage: accounts password [--generate|--password-stdin] <user>")
+		}
+		user := fs.Arg(0)
+		password, err := readPassword(*generate, *passwordStdin)
+		if err != nil {
+			return err
+		}
+		if err := client.SetAccountPassword(ctx, user, password); err != nil {
+			return err
+		}
+		a.logger.LogSuccess("Password of BMC account %s changed", user)
+		if *generate {
+			fmt.Println(password)
+		}
+		if user == a.config.IDRAC.Username {
+			a.logger.LogWarn("%s is the account the installer logs in with, update idrac.password in the configuration file", user)
+		}
+		return nil
+	case "create":
+		if fs.NArg() != 1 {
+			return usagef("usage: accounts create [--role role] [--generate|--password-stdin] <user>")
+		}
+		user := fs.Arg(0)
+		password, err := readPassword(*generate, *passwordStdin)
+		if err != nil {
+			return err
+		}
+		account, err := client.CreateAccount(ctx, user, password, *role)
+		if err != nil {
+			return err
+		}
+		a.logger.LogSuccess("BMC account %s created with role %s (%s)", user, *role, account.ODataID)
+		if *generate {
+			fmt.Println(password)
+		}
+		a.logger.LogInfo("To install with it, set idrac.username to %s and idrac.password to its password", user)
+		return nil
+	case "enable", "disable":
+		if fs.NArg() != 1 {
+			return usagef("usage: accounts %s <user>", subcommand)
+		}
+		if err := client.SetAccountEnabled(ctx, fs.Arg(0), subcommand == "enable"); err != nil {
+			return err
+		}
+		a.logger.LogSuccess("BMC account %s %sd", fs.Arg(0), subcommand)
+		return nil
+	default:
+		return usagef("unknown accounts subcommand %q (expected list, password, create, enable or disable)", subcommand)
+	}
+}
+
+// listAccounts logs the BMC accounts in use
+func (a *EnhancedApp) listAccounts(ctx context.Context) error {
+	accounts, err := a.bmc.Redfish().GetAccounts(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.logger.LogInfo("BMC accounts:")
+	for _, account := range accounts {
+		if account.UserName == "" {
+			continue
+		}
+		state := "enabled"
+		if !account.Enabled {
+			state = "disabled"
+		}
+		if account.Locked {
+			state += ", locked"
+		}
+		marker := ""
+		if account.UserName == a.config.IDRAC.Username {
+			marker = " (installer)"
+		}
+		a.logger.LogInfo("  %-3s %-16s %-14s %s%s", account.ID, account.UserName, account.RoleID, state, marker)
+	}
+	return nil
+}
+
+// readPassword returns a generated password or the first line of stdin
+func readPassword(generate, fromStdin bool) (string, error) {
+	switch {
+	case generate && fromStdin:
+		return "", fmt.Errorf("--generate and --password-stdin are mutually exclusive")
+	case generate:
+		return generatePassword(generatedPasswordLength)
+	case fromStdin:
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read password from stdin: %w", err)
+		}
+		password := strings.TrimRight(line, "\r\n")
+		if password == "" {
+			return "", fmt.Errorf("empty password on stdin")
+		}
+		return password, nil
+	default:
+		return "", fmt.Errorf("pass --generate or --password-stdin; passwords are not taken as arguments")
+	}
+}
+
+// generatePassword returns a random password of length characters
+func generatePassword(length int) (string, error) {
+	max := big.NewInt(int64(len(passwordAlphabet)))
+	password := make([]byte, length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		password[i] = passwordAlphabet[n.Int64()]
+	}
+	return string(password), nil
+}
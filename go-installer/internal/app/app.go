@@ -2,13 +2,17 @@ package app
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 
+	"openshift-sno-hub-installer/internal/bmc"
 	"openshift-sno-hub-installer/internal/config"
 	"openshift-sno-hub-installer/internal/idrac"
 	"openshift-sno-hub-installer/internal/logger"
 	"openshift-sno-hub-installer/internal/openshift"
+	"openshift-sno-hub-installer/internal/runner"
 	"openshift-sno-hub-installer/internal/ssh"
 )
 
@@ -16,75 +20,70 @@ import (
 type EnhancedApp struct {
 	config     *config.Config
 	logger     *logger.Logger
-	idrac      *idrac.EnhancedClient
+	bmc        bmc.BMC
 	installer  *openshift.Installer
 	sshManager *ssh.Manager
+
+	// options holds the global command line flags
+	options options
+
+	// runner runs external commands and filesystem changes, or prints them
+	// with --dry-run
+	runner *runner.Runner
+
+	// stopEvents ends the BMC event subscription started by startEvents
+	stopEvents func(context.Context) error
 }
 
 // NewEnhancedApp creates a new enhanced application instance
 func NewEnhancedApp(cfg *config.Config, log *logger.Logger) *EnhancedApp {
+	run := runner.New(os.Stdout)
 	return &EnhancedApp{
 		config:     cfg,
 		logger:     log,
-		idrac:      idrac.NewEnhancedClient(&cfg.IDRAC, log),
-		installer:  openshift.NewInstaller(cfg, log),
-		sshManager: ssh.NewManager(cfg, log),
+		runner:     run,
+		installer:  openshift.NewInstaller(cfg, log, run),
+		sshManager: ssh.NewManager(cfg, log, run),
 	}
 }
 
-// Run runs the enhanced application with the specified command
-func (a *EnhancedApp) Run(ctx context.Context) error {
-	// Get command from command line arguments
-	if len(os.Args) < 2 {
-		return a.runInstall(ctx)
+// connectBMC selects the BMC driver for the configured or detected vendor
+func (a *EnhancedApp) connectBMC(ctx context.Context) error {
+	if a.bmc != nil {
+		return nil
 	}
 
-	command := os.Args[1]
-	switch command {
-	case "config":
-		return a.createConfig()
-	case "power-on":
-		return a.powerOn(ctx)
-	case "power-off":
-		return a.powerOff(ctx)
-	case "status":
-		return a.getStatus(ctx)
-	case "info":
-		return a.getSystemInfo(ctx)
-	case "eject-media":
-		return a.ejectMedia(ctx)
-	case "insert-media":
-		if len(os.Args) < 3 {
-			return fmt.Errorf("please provide ISO URL as second argument")
-		}
-		return a.insertMedia(ctx, os.Args[2])
-	case "set-boot-cd":
-		return a.setBootCD(ctx)
-	case "set-boot-cd-enhanced":
-		return a.setVirtualCDBootEnhanced(ctx)
-	case "virtual-media-info":
-		return a.getVirtualMediaInfo(ctx)
-	case "lifecycle-controller":
-		return a.getLifecycleControllerInfo(ctx)
-	case "manage-virtual-boot":
-		if len(os.Args) < 3 {
-			return fmt.Errorf("please provide ISO URL as second argument")
-		}
-		return a.manageVirtualMediaBootProcess(ctx, os.Args[2])
-	case "set-boot-hdd":
-		return a.setBootHDD(ctx)
-	case "restart":
-		return a.restart(ctx)
-	case "cleanup":
-		powerOff := len(os.Args) > 2 && os.Args[2] == "poweroff"
-		return a.cleanup(ctx, powerOff)
-	case "install":
-		return a.runInstall(ctx)
-	case "help":
-		return a.showUsage()
-	default:
-		return a.showUsage()
+	driver, err := bmc.New(ctx, &a.config.IDRAC, &a.config.BMC, a.logger)
+	var mismatch *idrac.FingerprintMismatchError
+	if errors.As(err, &mismatch) {
+		a.logger.LogError("The BMC certificate changed. If it was replaced on purpose, verify %s and run 'certificate trust'", mismatch.Actual)
 	}
+	if err != nil {
+		return fmt.Errorf("failed to initialize BMC driver: %w", err)
+	}
+
+	driver.Redfish().SetResetPolicy(a.resetPolicy())
+	if a.runner.DryRun() {
+		driver.Redfish().SetDryRun(a.runner.Plan)
+	}
+	a.bmc = driver
+	return nil
+}
+
+// requireDell fails unless the BMC is driven by the Dell driver; feature
+// names the command that uses Dell OEM extensions
+func (a *EnhancedApp) requireDell(feature string) error {
+	return bmc.RequireVendor(a.bmc, bmc.VendorDell, feature)
+}
+
+// Close removes the BMC event subscription and ends the Redfish session
+// opened on the BMC, if any
+func (a *EnhancedApp) Close(ctx context.Context) error {
+	if a.bmc == nil {
+		return nil
+	}
+	a.closeEvents(ctx)
+	return a.bmc.Redfish().Logout(ctx)
 }
 
 // createConfig creates a default configuration file
@@ -93,7 +92,12 @@ func (a *EnhancedApp) createConfig() error {
 	
 	// Create a default config without validation
 	defaultConfig := config.DefaultConfig()
-	configFile := "idrac_config.yaml"
+	configFile := valueOr(a.options.ConfigFile, config.DefaultConfigFile)
+	if _, err := os.Stat(configFile); err == nil {
+		if err := a.confirm("Overwrite %s", configFile); err != nil {
+			return err
+		}
+	}
 	if err := defaultConfig.Save(configFile); err != nil {
 		return fmt.Errorf("failed to save config file: %w", err)
 	}
@@ -106,51 +110,89 @@ func (a *EnhancedApp) createConfig() error {
 // powerOn powers on the system
 func (a *EnhancedApp) powerOn(ctx context.Context) error {
 	a.logger.LogInfo("Powering on system...")
-	return a.idrac.PowerOnSystem(ctx)
+	return a.bmc.PowerOnSystem(ctx)
 }
 
-// powerOff powers off the system
-func (a *EnhancedApp) powerOff(ctx context.Context) error {
+// powerOff powers off the system with --type (default: reset.off_type),
+// forcing it off when a graceful shutdown exceeds --timeout
+func (a *EnhancedApp) powerOff(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("power-off", flag.ContinueOnError)
+	resetType, timeout := a.resetFlags(fs, valueOr(a.config.Reset.OffType, "auto"))
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if err := a.confirm("Power off %s", a.config.IDRAC.IP); err != nil {
+		return err
+	}
+
 	a.logger.LogInfo("Powering off system...")
-	return a.idrac.PowerOffSystem(ctx)
+	return a.bmc.Redfish().ShutdownSystem(ctx, a.offType(*resetType), *timeout)
 }
 
 // getStatus gets system status
 func (a *EnhancedApp) getStatus(ctx context.Context) error {
 	a.logger.LogInfo("Getting system status...")
 	
-	powerState, err := a.idrac.GetSystemPowerState(ctx)
+	powerState, err := a.bmc.GetSystemPowerState(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get power state: %w", err)
 	}
 	
-	health, err := a.idrac.GetSystemHealth(ctx)
+	health, err := a.bmc.GetSystemHealth(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get system health: %w", err)
 	}
+
+	if a.documentOutput() {
+		status := systemStatus{PowerState: powerState, Health: health}
+		if status.Power, err = a.powerStatus(ctx); err != nil {
+			a.logger.LogWarn("%v", err)
+		}
+		return a.writeDocument(kindSystemStatus, status)
+	}
 	
 	a.logger.LogInfo("System Status:")
 	a.logger.LogInfo("  Power State: %s", powerState)
 	a.logger.LogInfo("  Health: %s", health)
 	
+	if err := a.showPowerPolicy(ctx); err != nil {
+		a.logger.LogWarn("%v", err)
+	}
+	
 	return nil
 }
 
 // getSystemInfo gets system information including lifecycle controller
 func (a *EnhancedApp) getSystemInfo(ctx context.Context) error {
 	a.logger.LogInfo("Getting system information...")
+
+	if a.documentOutput() {
+		system, err := a.bmc.GetSystemInfo(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get system info: %w", err)
+		}
+		manager, err := a.bmc.GetManagerInfo(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get BMC manager info: %w", err)
+		}
+		return a.writeDocument(kindSystemInfo, systemInfo{
+			System:  newSystemSummary(system),
+			Manager: newManagerInfo(manager),
+		})
+	}
 	
 	// Get system information
-	_, err := a.idrac.GetSystemInfo(ctx)
+	_, err := a.bmc.GetSystemInfo(ctx)
 	if err != nil {
 		a.logger.LogWarn("Failed to get system info: %v", err)
 	}
 	
 	// Get lifecycle controller information
-	a.logger.LogInfo("Getting iDRAC lifecycle controller information...")
-	_, err = a.idrac.GetLifecycleControllerInfo(ctx)
+	a.logger.LogInfo("Getting BMC manager information...")
+	_, err = a.bmc.GetManagerInfo(ctx)
 	if err != nil {
-		a.logger.LogWarn("Failed to get lifecycle controller info: %v", err)
+		a.logger.LogWarn("Failed to get BMC manager info: %v", err)
 	}
 	
 	return nil
@@ -159,51 +201,101 @@ func (a *EnhancedApp) getSystemInfo(ctx context.Context) error {
 // ejectMedia ejects virtual media
 func (a *EnhancedApp) ejectMedia(ctx context.Context) error {
 	a.logger.LogInfo("Ejecting virtual media...")
-	return a.idrac.EjectVirtualMedia(ctx)
+	return a.bmc.EjectVirtualMedia(ctx)
 }
 
 // insertMedia inserts virtual media
 func (a *EnhancedApp) insertMedia(ctx context.Context, isoURL string) error {
 	a.logger.LogInfo("Inserting virtual media: %s", isoURL)
-	return a.idrac.InsertVirtualMedia(ctx, isoURL)
+	return a.bmc.InsertVirtualMedia(ctx, isoURL)
 }
 
 // setBootCD sets boot device to CD
 func (a *EnhancedApp) setBootCD(ctx context.Context) error {
 	a.logger.LogInfo("Setting boot device to CD...")
-	return a.idrac.SetVirtualCDBoot(ctx)
+	return a.bmc.SetVirtualCDBoot(ctx)
+}
+
+// setBoot sets a boot source override from --target, --mode and --persistence flags
+func (a *EnhancedApp) setBoot(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("set-boot", flag.ContinueOnError)
+	target := fs.String("target", "", "boot source override target (default: negotiated virtual CD/DVD)")
+	mode := fs.String("mode", "", "boot mode, UEFI or Legacy (default: unchanged)")
+	persistence := fs.String("persistence", "Once", "how long the override applies, Once or Continuous")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	override := idrac.BootOverride{
+		Target:      *target,
+		Mode:        *mode,
+		Persistence: *persistence,
+	}
+
+	a.logger.LogInfo("Setting boot override (target=%s, mode=%s, persistence=%s)...",
+		valueOr(override.Target, "virtual CD/DVD"), valueOr(override.Mode, "unchanged"), override.Persistence)
+	if err := a.bmc.SetBoot(ctx, override); err != nil {
+		return fmt.Errorf("failed to set boot override: %w", err)
+	}
+
+	a.logger.LogSuccess("Boot override set successfully")
+	return nil
+}
+
+// valueOr returns value, or fallback when value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
 }
 
 // setVirtualCDBootEnhanced sets boot device to virtual CD/DVD with enhanced compatibility
 func (a *EnhancedApp) setVirtualCDBootEnhanced(ctx context.Context) error {
 	a.logger.LogInfo("Setting boot device to Virtual CD/DVD (Enhanced)...")
-	return a.idrac.SetVirtualCDBootEnhanced(ctx)
+	return a.bmc.SetVirtualCDBoot(ctx)
 }
 
 // getVirtualMediaInfo gets virtual media information
 func (a *EnhancedApp) getVirtualMediaInfo(ctx context.Context) error {
 	a.logger.LogInfo("Getting virtual media information...")
-	_, err := a.idrac.GetVirtualMediaInfo(ctx)
-	return err
+	info, err := a.bmc.GetVirtualMediaInfo(ctx)
+	if err != nil || !a.documentOutput() {
+		return err
+	}
+	return a.writeDocument(kindVirtualMedia, newVirtualMedia(info))
 }
 
 // getLifecycleControllerInfo gets iDRAC lifecycle controller information
 func (a *EnhancedApp) getLifecycleControllerInfo(ctx context.Context) error {
-	a.logger.LogInfo("Getting iDRAC lifecycle controller information...")
-	_, err := a.idrac.GetLifecycleControllerInfo(ctx)
-	return err
+	a.logger.LogInfo("Getting BMC manager information...")
+	info, err := a.bmc.GetManagerInfo(ctx)
+	if err != nil || !a.documentOutput() {
+		return err
+	}
+	return a.writeDocument(kindManagerInfo, newManagerInfo(info))
 }
 
 // setBootHDD sets boot device to HDD
 func (a *EnhancedApp) setBootHDD(ctx context.Context) error {
 	a.logger.LogInfo("Setting boot device to HDD...")
-	return a.idrac.SetHDDBoot(ctx)
+	return a.bmc.SetHDDBoot(ctx)
 }
 
-// restart restarts the system
-func (a *EnhancedApp) restart(ctx context.Context) error {
+// restart restarts the system with --type (default: reset.restart_type)
+func (a *EnhancedApp) restart(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("restart", flag.ContinueOnError)
+	resetType, timeout := a.resetFlags(fs, valueOr(a.config.Reset.RestartType, idrac.ResetForceRestart))
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if err := a.confirm("Restart %s", a.config.IDRAC.IP); err != nil {
+		return err
+	}
+
 	a.logger.LogInfo("Restarting system...")
-	return a.idrac.RestartSystem(ctx)
+	return a.bmc.Redfish().RebootSystem(ctx, *resetType, *timeout)
 }
 
 // cleanup performs cleanup operations
@@ -211,18 +303,20 @@ func (a *EnhancedApp) cleanup(ctx context.Context, powerOff bool) error {
 	a.logger.LogInfo("Performing cleanup...")
 	
 	// Eject virtual media
-	if err := a.idrac.EjectVirtualMedia(ctx); err != nil {
+	if err := a.bmc.EjectVirtualMedia(ctx); err != nil {
 		a.logger.LogWarn("Failed to eject virtual media: %v", err)
 	}
 	
-	// Set boot back to HDD
-	if err := a.idrac.SetHDDBoot(ctx); err != nil {
+	// Set boot back to HDD, unless the persistent boot order already starts with the disk
+	if a.config.Boot.DiskFirst {
+		a.logger.LogInfo("Persistent boot order is disk-first, skipping one-time HDD boot override")
+	} else if err := a.bmc.SetHDDBoot(ctx); err != nil {
 		a.logger.LogWarn("Failed to set boot to HDD: %v", err)
 	}
 	
 	// Power off if requested
 	if powerOff {
-		if err := a.idrac.PowerOffSystem(ctx); err != nil {
+		if err := a.bmc.PowerOffSystem(ctx); err != nil {
 			a.logger.LogWarn("Failed to power off system: %v", err)
 		}
 	}
@@ -234,75 +328,7 @@ func (a *EnhancedApp) cleanup(ctx context.Context, powerOff bool) error {
 // manageVirtualMediaBootProcess manages the complete virtual media boot process
 func (a *EnhancedApp) manageVirtualMediaBootProcess(ctx context.Context, isoURL string) error {
 	a.logger.LogInfo("Managing virtual media boot process...")
-	return a.idrac.ManageVirtualMediaBootProcess(ctx, isoURL)
-}
-
-// runInstall runs the full installation process
-func (a *EnhancedApp) runInstall(ctx context.Context) error {
-	a.logger.LogInfo("Starting OpenShift SNO Hub Installation with Enhanced iDRAC8 Management")
-	
-	// Check iDRAC connectivity
-	if err := a.idrac.CheckConnectivity(ctx); err != nil {
-		return fmt.Errorf("iDRAC connectivity check failed: %w", err)
-	}
-	
-	// Get system information
-	if _, err := a.idrac.GetSystemInfo(ctx); err != nil {
-		a.logger.LogWarn("Failed to get system info: %v", err)
-	}
-	
-	// Get system health
-	if _, err := a.idrac.GetSystemHealth(ctx); err != nil {
-		a.logger.LogWarn("Failed to get system health: %v", err)
-	}
-	
-	// Check and setup SSH key
-	if err := a.sshManager.CheckSSHKey(ctx); err != nil {
-		return fmt.Errorf("failed to check SSH key: %w", err)
-	}
-	
-	if err := a.sshManager.SetupSSHKey(ctx); err != nil {
-		return fmt.Errorf("failed to setup SSH key: %w", err)
-	}
-	
-	// Extract OpenShift installer
-	if err := a.installer.ExtractInstaller(ctx); err != nil {
-		return fmt.Errorf("failed to extract installer: %w", err)
-	}
-	
-	// Prepare work directory
-	if err := a.installer.PrepareWorkDir(ctx); err != nil {
-		return fmt.Errorf("failed to prepare work directory: %w", err)
-	}
-	
-	// Create agent image
-	if err := a.installer.CreateAgentImage(ctx); err != nil {
-		return fmt.Errorf("failed to create agent image: %w", err)
-	}
-	
-	// Copy ISO to remote host
-	isoPath := a.installer.GetISOFilePath()
-	if err := a.sshManager.CopyISOToRemote(ctx, isoPath); err != nil {
-		return fmt.Errorf("failed to copy ISO to remote: %w", err)
-	}
-	
-	// Manage virtual media boot process
-	if err := a.manageVirtualMediaBootProcess(ctx, a.config.Remote.ISOURL); err != nil {
-		return fmt.Errorf("failed to manage virtual media boot process: %w", err)
-	}
-	
-	// Monitor installation
-	if err := a.monitorInstallation(ctx); err != nil {
-		return fmt.Errorf("failed to monitor installation: %w", err)
-	}
-	
-	// Cleanup
-	if err := a.cleanup(ctx, false); err != nil {
-		a.logger.LogWarn("Cleanup failed: %v", err)
-	}
-	
-	a.logger.LogSuccess("OpenShift SNO Hub installation completed successfully!")
-	return nil
+	return a.bmc.ManageVirtualMediaBootProcess(ctx, isoURL)
 }
 
 // monitorInstallation monitors the installation progress
@@ -310,7 +336,7 @@ func (a *EnhancedApp) monitorInstallation(ctx context.Context) error {
 	a.logger.LogInfo("Monitoring installation progress...")
 	
 	// Check power status
-	powerState, err := a.idrac.GetSystemPowerState(ctx)
+	powerState, err := a.bmc.GetSystemPowerState(ctx)
 	if err != nil {
 		a.logger.LogWarn("Failed to get power state: %v", err)
 		return nil
@@ -318,6 +344,10 @@ func (a *EnhancedApp) monitorInstallation(ctx context.Context) error {
 	
 	if powerState == "On" {
 		a.logger.LogSuccess("Server is powered ON. Running wait-for install-complete...")
+		if a.config.Metrics.Trends && !a.runner.DryRun() {
+			stopTrends := a.startTrends(ctx)
+			defer stopTrends()
+		}
 		return a.installer.WaitForInstallComplete(ctx)
 	} else {
 		a.logger.LogWarn("Server power state is: %s", powerState)
@@ -326,27 +356,3 @@ func (a *EnhancedApp) monitorInstallation(ctx context.Context) error {
 	
 	return nil
 }
-
-// showUsage shows the usage information
-func (a *EnhancedApp) showUsage() error {
-	fmt.Println("Usage: openshift-sno-hub-installer [command]")
-	fmt.Println("")
-	fmt.Println("Commands:")
-	fmt.Println("  config         - Create configuration file")
-	fmt.Println("  power-on       - Power on the system via iDRAC")
-	fmt.Println("  power-off      - Power off the system via iDRAC")
-	fmt.Println("  status         - Get system power and health status")
-	fmt.Println("  info           - Get system information")
-	fmt.Println("  eject-media    - Eject virtual media")
-	fmt.Println("  insert-media   - Insert virtual media (requires ISO URL)")
-	fmt.Println("  set-boot-cd    - Set boot device to Virtual CD/DVD")
-	fmt.Println("  set-boot-cd-enhanced - Set boot device to Virtual CD/DVD (Enhanced)")
-	fmt.Println("  virtual-media-info - Get virtual media information")
-	fmt.Println("  lifecycle-controller - Get iDRAC lifecycle controller information")
-	fmt.Println("  manage-virtual-boot - Manage complete virtual media boot process (requires ISO URL)")
-	fmt.Println("  set-boot-hdd   - Set boot device to HDD")
-	fmt.Println("  restart        - Restart the system")
-	fmt.Println("  cleanup        - Perform cleanup (optionally power off)")
-	fmt.Println("  install        - Run full OpenShift SNO hub installation (default)")
-	return nil
-}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+
+	"openshift-sno-hub-installer/internal/idrac"
+)
+
+// bios dispatches the bios subcommands: show (default), diff and apply
+func (a *EnhancedApp) bios(ctx context.Context, args []string) error {
+	subcommand := "show"
+	if len(args) > 0 {
+		subcommand, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("bios "+subcommand, flag.ContinueOnError)
+	profilePath := fs.String("profile", a.config.BIOS.Profile, "BIOS profile YAML file")
+	reboot := fs.Bool("reboot", false, "reset the system to apply the changes and wait for the configuration job")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	switch subcommand {
+	case "show":
+		return a.showBIOSAttributes(ctx)
+	case "diff":
+		profile, err := a.loadBIOSProfile(*profilePath)
+		if err != nil {
+			return err
+		}
+		_, err = a.diffBIOSProfile(ctx, profile)
+		return err
+	case "apply":
+		profile, err := a.loadBIOSProfile(*profilePath)
+		if err != nil {
+			return err
+		}
+		return a.applyBIOSChanges(ctx, profile, *reboot)
+	default:
+		return usagef("unknown bios subcommand %q (expected show, diff or apply)", subcommand)
+	}
+}
+
+// loadBIOSProfile loads the BIOS profile from path
+func (a *EnhancedApp) loadBIOSProfile(path string) (*idrac.BIOSProfile, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no BIOS profile given, set bios.profile or pass --profile")
+	}
+	return idrac.LoadBIOSProfile(path)
+}
+
+// showBIOSAttributes logs the current BIOS attributes sorted by name
+func (a *EnhancedApp) showBIOSAttributes(ctx context.Context) error {
+	attributes, err := a.bmc.Redfish().GetBIOSAttributes(ctx)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	a.logger.LogInfo("BIOS attributes:")
+	for _, name := range names {
+		a.logger.LogInfo("  %s: %v", name, attributes[name])
+	}
+	return nil
+}
+
+// diffBIOSProfile logs and returns the differences between the BIOS and the profile
+func (a *EnhancedApp) diffBIOSProfile(ctx context.Context, profile *idrac.BIOSProfile) ([]idrac.BIOSDifference, error) {
+	current, err := a.bmc.Redfish().GetBIOSAttributes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := idrac.DiffBIOSAttributes(current, profile.Attributes)
+	if len(diffs) == 0 {
+		a.logger.LogSuccess("BIOS matches profile %s", profile.Name)
+		return nil, nil
+	}
+
+	a.logger.LogWarn("BIOS differs from profile %s in %d attribute(s):", profile.Name, len(diffs))
+	for _, diff := range diffs {
+		if diff.Missing {
+			a.logger.LogWarn("  %s: not supported by this BIOS (want %v)", diff.Name, diff.Desired)
+		} else {
+			a.logger.LogWarn("  %s: %v -> %v", diff.Name, diff.Current, diff.Desired)
+		}
+	}
+	return diffs, nil
+}
+
+// applyBIOSProfile writes the attributes that differ from the profile. It
+// returns nil when the BIOS already matches.
+func (a *EnhancedApp) applyBIOSProfile(ctx context.Context, profile *idrac.BIOSProfile) (*idrac.PendingChange, error) {
+	diffs, err := a.diffBIOSProfile(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := make(map[string]interface{})
+	for _, diff := range diffs {
+		if diff.Missing {
+			return nil, fmt.Errorf("BIOS attribute %s of profile %s is not supported by this system", diff.Name, profile.Name)
+		}
+		attributes[diff.Name] = diff.Desired
+	}
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+
+	return a.bmc.Redfish().SetBIOSAttributes(ctx, attributes)
+}
+
+// applyBIOSChanges applies profile and, when reboot is set, resets the system
+// and waits for the configuration job
+func (a *EnhancedApp) applyBIOSChanges(ctx context.Context, profile *idrac.BIOSProfile, reboot bool) error {
+	change, err := a.applyBIOSProfile(ctx, profile)
+	if err != nil || change == nil {
+		return err
+	}
+	if reboot {
+		return a.applyPendingChange(ctx, change)
+	}
+	a.logger.LogInfo("BIOS changes are pending and apply on the next reset")
+	return nil
+}
+
+// siteBIOSProfile returns the BIOS settings declared by the site config: the
+// profile file when bios.enforce is set, with SysProfile taken from
+// bios.system_profile
+func (a *EnhancedApp) siteBIOSProfile() (*idrac.BIOSProfile, error) {
+	profile := &idrac.BIOSProfile{Name: "system profile", Attributes: make(map[string]interface{})}
+	if a.config.BIOS.Enforce {
+		loaded, err := a.loadBIOSProfile(a.config.BIOS.Profile)
+		if err != nil {
+			return nil, err
+		}
+		profile = loaded
+		if profile.Attributes == nil {
+			profile.Attributes = make(map[string]interface{})
+		}
+	}
+
+	if a.config.BIOS.SystemProfile != "" {
+		value := idrac.SystemProfileValue(a.config.BIOS.SystemProfile)
+		if existing, ok := profile.Attributes[idrac.SystemProfileAttribute]; ok && fmt.Sprint(existing) != value {
+			a.logger.LogWarn("bios.system_profile %s overrides %s=%v of profile %s",
+				a.config.BIOS.SystemProfile, idrac.SystemProfileAttribute, existing, profile.Name)
+		}
+		profile.Attributes[idrac.SystemProfileAttribute] = value
+	}
+	return profile, nil
+}
+
+// enforceBIOSProfile applies the BIOS settings of the site config and resets
+// the system so they are active before the agent ISO is booted
+func (a *EnhancedApp) enforceBIOSProfile(ctx context.Context) error {
+	profile, err := a.siteBIOSProfile()
+	if err != nil {
+		return err
+	}
+
+	change, err := a.applyBIOSProfile(ctx, profile)
+	if err != nil || change == nil {
+		return err
+	}
+
+	return a.applyPendingChange(ctx, change)
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"strings"
+)
+
+// bmcNetwork dispatches the BMC network subcommands: show (default) and apply
+func (a *EnhancedApp) bmcNetwork(ctx context.Context, args []string) error {
+	subcommand := "show"
+	if len(args) > 0 {
+		subcommand, args = args[0], args[1:]
+	}
+
+	settings := a.config.BMC.Network
+	fs := flag.NewFlagSet("bmc-network "+subcommand, flag.ContinueOnError)
+	ntp := fs.String("ntp", strings.Join(settings.NTPServers, ","), "comma-separated NTP servers")
+	dns := fs.String("dns", strings.Join(settings.DNSServers, ","), "comma-separated static DNS servers")
+	vlan := fs.Int("vlan", -1, "VLAN id of the BMC traffic, 0 disables tagging (default bmc.network.vlan_id)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	switch subcommand {
+	case "show":
+		return a.showBMCNetwork(ctx)
+	case "apply":
+		settings.NTPServers = splitList(*ntp)
+		settings.DNSServers = splitList(*dns)
+		if *vlan >= 0 {
+			if *vlan > 4094 {
+				return usagef("invalid VLAN id %d, expected 0-4094", *vlan)
+			}
+			settings.VLANID = vlan
+		}
+		return a.applyBMCNetwork(ctx, settings.NTPServers, settings.DNSServers, settings.VLANID)
+	default:
+		return usagef("unknown bmc-network subcommand %q (expected show or apply)", subcommand)
+	}
+}
+
+// showBMCNetwork logs the network settings of the BMC
+func (a *EnhancedApp) showBMCNetwork(ctx context.Context) error {
+	network, err := a.bmc.Redfish().GetManagerNetwork(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.logger.LogInfo("BMC network (%s):", network.InterfaceURI)
+	a.logger.LogInfo("  Host name: %s", valueOr(network.HostName, "-"))
+	a.logger.LogInfo("  MAC address: %s", valueOr(network.MACAddress, "-"))
+	a.logger.LogInfo("  IPv4 addresses: %s", valueOr(strings.Join(network.IPv4Addresses, ", "), "-"))
+	a.logger.LogInfo("  DNS servers: %s", valueOr(strings.Join(network.NameServers, ", "), "-"))
+	if network.VLANEnabled {
+		a.logger.LogInfo("  VLAN: %d", network.VLANID)
+	} else {
+		a.logger.LogInfo("  VLAN: disabled")
+	}
+	if network.NTPEnabled {
+		a.logger.LogInfo("  NTP servers: %s", valueOr(strings.Join(network.NTPServers, ", "), "-"))
+	} else {
+		a.logger.LogInfo("  NTP: disabled")
+	}
+	return nil
+}
+
+// applyBMCNetwork sets the NTP servers, DNS servers and VLAN of the BMC that
+// differ from its current settings. The VLAN goes last since changing it may
+// cut off the installer.
+func (a *EnhancedApp) applyBMCNetwork(ctx context.Context, ntp, dns []string, vlan *int) error {
+	client := a.bmc.Redfish()
+	network, err := client.GetManagerNetwork(ctx)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	if len(ntp) > 0 && (!network.NTPEnabled || !sameList(network.NTPServers, ntp)) {
+		if err := client.SetNTPServers(ctx, ntp); err != nil {
+			return err
+		}
+		a.logger.LogSuccess("BMC NTP servers set to %s", strings.Join(ntp, ", "))
+		changed = true
+	}
+	if len(dns) > 0 && !sameList(network.NameServers, dns) {
+		if err := client.SetDNSServers(ctx, dns); err != nil {
+			return err
+		}
+		a.logger.LogSuccess("BMC DNS servers set to %s", strings.Join(dns, ", "))
+		changed = true
+	}
+	if vlan != nil && (network.VLANEnabled != (*vlan > 0) || (*vlan > 0 && network.VLANID != *vlan)) {
+		a.logger.LogWarn("Changing the BMC VLAN; the BMC is unreachable until the switch port carries VLAN %d", *vlan)
+		if err := client.SetVLAN(ctx, *vlan); err != nil {
+			return err
+		}
+		a.logger.LogSuccess("BMC VLAN set to %d", *vlan)
+		changed = true
+	}
+
+	if !changed {
+		a.logger.LogSuccess("BMC network settings are up to date")
+	}
+	return nil
+}
+
+// sameList reports whether a and b hold the same entries in the same order
+func sameList(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
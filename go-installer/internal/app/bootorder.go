@@ -0,0 +1,147 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"openshift-sno-hub-installer/internal/bmc"
+	"openshift-sno-hub-installer/internal/idrac"
+)
+
+// bootOrder dispatches the boot-order subcommands: show (default), set and disk-first
+func (a *EnhancedApp) bootOrder(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] == "show" {
+		return a.showBootOrder(ctx)
+	}
+
+	fs := flag.NewFlagSet("boot-order "+args[0], flag.ContinueOnError)
+	reboot := fs.Bool("reboot", false, "reset the system to apply the new order and wait for the configuration job")
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return err
+	}
+
+	var change *idrac.PendingChange
+	var err error
+	switch args[0] {
+	case "set":
+		if fs.NArg() == 0 {
+			return usagef("please provide the boot option references in the desired order")
+		}
+		change, err = a.setBootOrder(ctx, fs.Args())
+	case "disk-first":
+		change, err = a.setDiskFirst(ctx)
+	default:
+		return usagef("unknown boot-order subcommand %q (expected show, set or disk-first)", args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	if *reboot {
+		return a.applyPendingChange(ctx, change)
+	}
+	if change.JobURI != "" {
+		a.logger.LogInfo("Boot order change is pending in %s and applies on the next reset", change.JobURI)
+	}
+	return nil
+}
+
+// showBootOrder logs the persistent boot order and the available boot options
+func (a *EnhancedApp) showBootOrder(ctx context.Context) error {
+	order, options, err := a.bmc.Redfish().GetBootOrder(ctx)
+	if err != nil {
+		return err
+	}
+
+	byRef := make(map[string]idrac.BootOption, len(options))
+	for _, option := range options {
+		byRef[option.BootOptionReference] = option
+	}
+
+	a.logger.LogInfo("Persistent boot order:")
+	for i, ref := range order {
+		if option, ok := byRef[ref]; ok {
+			a.logger.LogInfo("  %d. %s", i+1, option)
+		} else {
+			a.logger.LogInfo("  %d. %s", i+1, ref)
+		}
+	}
+	return nil
+}
+
+// setBootOrder writes the given boot option references as the persistent boot order
+func (a *EnhancedApp) setBootOrder(ctx context.Context, order []string) (*idrac.PendingChange, error) {
+	change, err := a.bmc.Redfish().SetBootOrder(ctx, order)
+	if err == nil {
+		return change, nil
+	}
+
+	// Older iDRAC firmware does not know Boot.BootOrder. Its BIOS UefiBootSeq
+	// lists device FQDDs rather than boot option references, so it cannot be
+	// written from the same order.
+	var redfishErr *idrac.RedfishError
+	if a.bmc.Vendor() == bmc.VendorDell && errors.As(err, &redfishErr) &&
+		(redfishErr.HasMessage("PropertyUnknown") || idrac.IsUnsupportedValue(err)) {
+		return nil, fmt.Errorf("%w; this iDRAC firmware does not support Boot.BootOrder, update it or set the BIOS UefiBootSeq attribute with bios apply", err)
+	}
+	return nil, err
+}
+
+// setDiskFirst moves the installation disk selected by the rootDeviceHints of
+// the host on this BMC to the front of the persistent boot order
+func (a *EnhancedApp) setDiskFirst(ctx context.Context) (*idrac.PendingChange, error) {
+	var hints []string
+	host, err := a.agentHost(ctx)
+	if err != nil {
+		a.logger.LogWarn("Failed to select the agent-config.yaml host, selecting the first disk: %v", err)
+	} else if host != nil {
+		hints = host.RootDeviceHints.Hints()
+	}
+
+	order, options, err := a.bmc.Redfish().GetBootOrder(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	disk, err := idrac.SelectDiskBootOption(options, hints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select installation disk: %w", err)
+	}
+	a.logger.LogInfo("Installation disk boot option: %s", disk)
+
+	if len(order) > 0 && order[0] == disk.BootOptionReference {
+		a.logger.LogSuccess("Installation disk is already first in the boot order")
+		return &idrac.PendingChange{}, nil
+	}
+
+	return a.setBootOrder(ctx, idrac.MoveToFront(order, disk.BootOptionReference))
+}
+
+// applyPendingChange resets (or powers on) the system so a pending settings
+// change takes effect and waits for its configuration job, if any
+func (a *EnhancedApp) applyPendingChange(ctx context.Context, change *idrac.PendingChange) error {
+	powerState, err := a.bmc.GetSystemPowerState(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get power state: %w", err)
+	}
+
+	if powerState == "On" {
+		err = a.bmc.RestartSystem(ctx)
+	} else {
+		err = a.bmc.PowerOnSystem(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reset system: %w", err)
+	}
+
+	if change.JobURI == "" {
+		return nil
+	}
+	if _, err := a.bmc.Redfish().WaitForTask(ctx, change.JobURI); err != nil {
+		return fmt.Errorf("configuration job failed: %w", err)
+	}
+	a.logger.LogSuccess("Pending settings applied")
+	return nil
+}
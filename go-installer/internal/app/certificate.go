@@ -0,0 +1,196 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"openshift-sno-hub-installer/internal/idrac"
+)
+
+// certificate dispatches the BMC web server certificate subcommands: show
+// (default), csr and install; trust is dispatched by Run before connecting
+func (a *EnhancedApp) certificate(ctx context.Context, args []string) error {
+	subcommand := "show"
+	if len(args) > 0 {
+		subcommand, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("certificate "+subcommand, flag.ContinueOnError)
+	commonName := fs.String("cn", a.config.IDRAC.IP, "common name of the CSR")
+	org := fs.String("org", "", "organization of the CSR")
+	orgUnit := fs.String("ou", "", "organizational unit of the CSR")
+	city := fs.String("city", "", "city of the CSR")
+	state := fs.String("state", "", "state of the CSR")
+	country := fs.String("country", "", "two-letter country code of the CSR")
+	san := fs.String("san", a.config.IDRAC.IP, "comma-separated subject alternative names of the CSR")
+	file := fs.String("file", filepath.Join(a.config.Paths.SourceDir, "bmc.csr"), "file the CSR is written to")
+	resetBMC := fs.Bool("reset-bmc", false, "restart the BMC so its web server loads the new certificate")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	switch subcommand {
+	case "show":
+		return a.showCertificates(ctx)
+	case "csr":
+		csr := idrac.CSRRequest{
+			CommonName:         *commonName,
+			Organization:       *org,
+			OrganizationalUnit: *orgUnit,
+			City:               *city,
+			State:              *state,
+			Country:            *country,
+			AlternativeNames:   splitList(*san),
+		}
+		return a.generateCSR(ctx, csr, *file)
+	case "install":
+		if fs.NArg() != 1 {
+			return usagef("usage: certificate install [--reset-bmc] <cert.pem>")
+		}
+		return a.installCertificate(ctx, fs.Arg(0), *resetBMC)
+	default:
+		return usagef("unknown certificate subcommand %q (expected show, csr, install or trust)", subcommand)
+	}
+}
+
+// showCertificates logs the certificates of the BMC web server
+func (a *EnhancedApp) showCertificates(ctx context.Context) error {
+	certificates, err := a.bmc.Redfish().GetHTTPSCertificates(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.logger.LogInfo("BMC HTTPS certificates:")
+	for _, cert := range certificates {
+		a.logger.LogInfo("  %s", cert.ODataID)
+		a.logger.LogInfo("    Subject: %s", valueOr(cert.Subject.CommonName, "-"))
+		a.logger.LogInfo("    Issuer: %s", valueOr(cert.Issuer.CommonName, "-"))
+		a.logger.LogInfo("    Valid: %s - %s", valueOr(cert.ValidNotBefore, "?"), valueOr(cert.ValidNotAfter, "?"))
+	}
+
+	client := a.bmc.Redfish()
+	served, err := client.FetchFingerprint(ctx)
+	if err != nil {
+		return err
+	}
+	a.logger.LogInfo("  Served fingerprint (SHA-256): %s", served)
+	trusted, err := client.TrustedFingerprint()
+	if err != nil {
+		return err
+	}
+	if trusted != "" {
+		a.logger.LogInfo("  Trusted fingerprint (SHA-256): %s", trusted)
+	}
+
+	if !a.config.IDRAC.VerifySSL && a.config.IDRAC.CABundle == "" && trusted == "" {
+		a.logger.LogWarn("The BMC certificate is not verified; set idrac.ca_bundle, idrac.fingerprint or idrac.trust_on_first_use")
+	}
+	return nil
+}
+
+// generateCSR has the BMC generate a CSR and writes it to path
+func (a *EnhancedApp) generateCSR(ctx context.Context, csr idrac.CSRRequest, path string) error {
+	pem, err := a.bmc.Redfish().GenerateCSR(ctx, csr)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create CSR directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(pem), 0644); err != nil {
+		return fmt.Errorf("failed to write CSR: %w", err)
+	}
+	a.logger.LogSuccess("CSR for %s written to %s; have it signed and run 'certificate install <cert.pem>'", csr.CommonName, path)
+	return nil
+}
+
+// installCertificate installs a signed certificate on the BMC web server and
+// optionally restarts the BMC to load it
+func (a *EnhancedApp) installCertificate(ctx context.Context, path string, resetBMC bool) error {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read certificate: %w", err)
+	}
+
+	client := a.bmc.Redfish()
+	if err := client.ReplaceHTTPSCertificate(ctx, string(pem)); err != nil {
+		return err
+	}
+	a.logger.LogSuccess("BMC HTTPS certificate installed from %s", path)
+
+	if !resetBMC {
+		a.logger.LogInfo("The BMC serves the new certificate after its next restart (certificate install --reset-bmc)")
+	} else {
+		if err := client.ResetManager(ctx); err != nil {
+			return err
+		}
+		a.logger.LogSuccess("BMC restart requested; it is unreachable for a few minutes")
+	}
+
+	// The new certificate no longer matches a pinned fingerprint. The BMC
+	// serves the old one until it restarts, so a fingerprint trusted on first
+	// use is only replaced once the restart was requested.
+	fingerprint, err := idrac.PEMFingerprint(pem)
+	if err != nil {
+		a.logger.LogWarn("Could not compute the fingerprint of %s: %v", path, err)
+	} else if a.config.IDRAC.Fingerprint != "" {
+		a.logger.LogWarn("Set idrac.fingerprint to %s once the BMC serves the new certificate", fingerprint)
+	} else if a.config.IDRAC.TrustOnFirstUse && resetBMC {
+		if err := client.TrustFingerprint(fingerprint); err != nil {
+			return err
+		}
+		a.logger.LogInfo("Trusting the new certificate %s", fingerprint)
+	} else if a.config.IDRAC.TrustOnFirstUse {
+		a.logger.LogInfo("After the restart, run 'certificate trust --fingerprint %s'", fingerprint)
+	}
+
+	if !a.config.IDRAC.VerifySSL && a.config.IDRAC.CABundle == "" {
+		a.logger.LogInfo("Once the BMC serves it, set idrac.verify_ssl: true (or idrac.ca_bundle for a private CA) to verify the BMC certificate")
+	}
+	return nil
+}
+
+// trustCertificate records the certificate the BMC serves as trusted. It runs
+// without a verified connection, so the fingerprint should be checked out of
+// band, e.g. on the BMC console, and passed with --fingerprint.
+func (a *EnhancedApp) trustCertificate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("certificate trust", flag.ContinueOnError)
+	expected := fs.String("fingerprint", "", "SHA-256 fingerprint the served certificate must have")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	client := idrac.NewClient(&a.config.IDRAC, a.logger)
+	served, err := client.FetchFingerprint(ctx)
+	if err != nil {
+		return err
+	}
+	a.logger.LogInfo("%s serves certificate %s", a.config.IDRAC.IP, served)
+
+	if *expected != "" {
+		want, err := idrac.NormalizeFingerprint(*expected)
+		if err != nil {
+			return err
+		}
+		if want != served {
+			return fmt.Errorf("served certificate %s does not match --fingerprint %s", served, want)
+		}
+	}
+
+	switch {
+	case a.config.IDRAC.Fingerprint != "":
+		a.logger.LogWarn("idrac.fingerprint pins the certificate; set it to %s in the configuration file", served)
+	case a.config.IDRAC.TrustOnFirstUse:
+		if err := client.TrustFingerprint(served); err != nil {
+			return err
+		}
+		a.logger.LogSuccess("Certificate %s of %s trusted", served, a.config.IDRAC.IP)
+	default:
+		a.logger.LogWarn("Certificate pinning is off; set idrac.fingerprint: %q or idrac.trust_on_first_use: true", served)
+	}
+	return nil
+}
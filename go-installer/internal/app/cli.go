@@ -0,0 +1,909 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"openshift-sno-hub-installer/internal/config"
+	"openshift-sno-hub-installer/internal/logger"
+)
+
+// programName is the name of the installer binary
+const programName = "openshift-sno-hub-installer"
+
+// Exit codes of Main
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// options holds the global flags
+type options struct {
+	ConfigFile string
+	LogLevel   string
+	Output     string
+	ISOURL     string
+	Yes        bool
+	DryRun     bool
+}
+
+// command is a subcommand of the command line
+type command struct {
+	Name string
+	// Args is the synopsis of the arguments and flags after the name
+	Args    string
+	Summary string
+	// Help describes the subcommands and flags in more detail
+	Help        string
+	Subcommands []string
+	// DryRun marks the commands that accept --dry-run
+	DryRun bool
+	// NoConfig commands run without loading the configuration file
+	NoConfig bool
+	// NoBMC commands do not connect to the BMC before they run, or connect
+	// themselves
+	NoBMC bool
+	// Builtin commands only print, without a configuration, log or BMC
+	Builtin func(opts *options, args []string) error
+	Run     func(a *EnhancedApp, ctx context.Context, args []string) error
+}
+
+// usageError is a command line error, reported with exit code 2
+type usageError struct {
+	err error
+}
+
+// Error implements the error interface
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// usagef returns a usage error
+func usagef(format string, args ...interface{}) error {
+	return &usageError{err: fmt.Errorf(format, args...)}
+}
+
+// parseFlags parses the flags of a subcommand, turning a parse failure into
+// a usage error. Flags may follow the positional arguments, as in
+// "power cap 450 --exception LogEventOnly"; arguments after "--" are all
+// positional. fs.Args returns the positional arguments afterwards.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return &usageError{err: err}
+		}
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			positional = append(positional, rest...)
+			break
+		}
+		if len(rest) == 0 {
+			break
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+
+	// Leave the positional arguments in fs.Args
+	if err := fs.Parse(append([]string{"--"}, positional...)); err != nil {
+		return &usageError{err: err}
+	}
+	return nil
+}
+
+// noArgs adapts a command that takes no arguments
+func noArgs(run func(a *EnhancedApp, ctx context.Context) error) func(*EnhancedApp, context.Context, []string) error {
+	return func(a *EnhancedApp, ctx context.Context, args []string) error {
+		if len(args) > 0 {
+			return usagef("unexpected arguments: %s", strings.Join(args, " "))
+		}
+		return run(a, ctx)
+	}
+}
+
+// commands returns the command tree in the order of the help output
+func commands() []command {
+	return []command{
+		{
+			Name:    "install",
+			Args:    "[--resume | --from-step <step>] [--list-steps]",
+			Summary: "Run the full OpenShift SNO hub installation (default command)",
+			Help: `  --resume            skip the steps a previous install completed
+  --from-step <step>  rerun the install from this step on, e.g. boot
+  --list-steps        list the install steps and their state`,
+			DryRun: true,
+			Run:    (*EnhancedApp).runInstall,
+		},
+		{
+			Name:     "config",
+			Summary:  "Create the configuration file at --config",
+			NoConfig: true,
+			NoBMC:    true,
+			Run: noArgs(func(a *EnhancedApp, _ context.Context) error {
+				return a.createConfig()
+			}),
+		},
+		{
+			Name:    "status",
+			Summary: "Get system power and health status",
+			Run:     noArgs((*EnhancedApp).getStatus),
+		},
+		{
+			Name:    "info",
+			Summary: "Get system information",
+			Run:     noArgs((*EnhancedApp).getSystemInfo),
+		},
+		{
+			Name:    "power-on",
+			Summary: "Power on the system via the BMC",
+			DryRun:  true,
+			Run:     noArgs((*EnhancedApp).powerOn),
+		},
+		{
+			Name:    "power-off",
+			Args:    "[--type <type>] [--timeout <duration>]",
+			Summary: "Power off the system, forcing it off when a graceful shutdown times out",
+			Help: `  --type <type>         GracefulShutdown, PushPowerButton, ForceOff or auto (default: reset.off_type)
+  --timeout <duration>  how long a graceful shutdown may take (default: reset.graceful_timeout)`,
+			DryRun: true,
+			Run:    (*EnhancedApp).powerOff,
+		},
+		{
+			Name:    "restart",
+			Args:    "[--type <type>] [--timeout <duration>]",
+			Summary: "Restart the system",
+			Help: `  --type <type>         ForceRestart, GracefulRestart or PowerCycle (default: reset.restart_type)
+  --timeout <duration>  how long a graceful restart may take (default: reset.graceful_timeout)`,
+			DryRun: true,
+			Run:    (*EnhancedApp).restart,
+		},
+		{
+			Name:    "reset",
+			Args:    "<type>",
+			Summary: "Send any ComputerSystem.Reset type, e.g. Nmi",
+			DryRun:  true,
+			Run:     (*EnhancedApp).reset,
+		},
+		{
+			Name:    "power",
+			Args:    "[show | cap <watts>|off | redundancy <policy> | system-profile <name> | apply]",
+			Summary: "Show or change the power cap, PSU redundancy and system profile",
+			Help: `  show                      show the power cap and PSU redundancy (default)
+  cap [--exception <action>] <watts>|off
+                            set or remove the power cap
+  redundancy <policy>       set the PSU redundancy policy
+  system-profile [--reboot] <name>
+                            set the BIOS system profile, --reboot applies it
+  apply                     apply the power section of the configuration`,
+			Subcommands: []string{"show", "cap", "redundancy", "system-profile", "apply"},
+			DryRun:      true,
+			Run:         (*EnhancedApp).power,
+		},
+		{
+			Name:    "eject-media",
+			Summary: "Eject virtual media",
+			DryRun:  true,
+			Run:     noArgs((*EnhancedApp).ejectMedia),
+		},
+		{
+			Name:    "insert-media",
+			Args:    "[iso-url]",
+			Summary: "Insert virtual media (default: --iso-url or remote.iso_url)",
+			DryRun:  true,
+			Run: func(a *EnhancedApp, ctx context.Context, args []string) error {
+				isoURL, err := a.isoURL(args)
+				if err != nil {
+					return err
+				}
+				return a.insertMedia(ctx, isoURL)
+			},
+		},
+		{
+			Name:    "virtual-media-info",
+			Summary: "Get virtual media information",
+			Run:     noArgs((*EnhancedApp).getVirtualMediaInfo),
+		},
+		{
+			Name:    "manage-virtual-boot",
+			Args:    "[iso-url]",
+			Summary: "Insert the ISO, boot from virtual CD and restart (default: --iso-url or remote.iso_url)",
+			DryRun:  true,
+			Run: func(a *EnhancedApp, ctx context.Context, args []string) error {
+				isoURL, err := a.isoURL(args)
+				if err != nil {
+					return err
+				}
+				if err := a.confirm("Restart %s from %s", a.config.IDRAC.IP, isoURL); err != nil {
+					return err
+				}
+				return a.manageVirtualMediaBootProcess(ctx, isoURL)
+			},
+		},
+		{
+			Name:    "set-boot",
+			Args:    "[--target <target>] [--mode UEFI|Legacy] [--persistence Once|Continuous]",
+			Summary: "Set the boot source override",
+			Help: `  --target <target>   boot source override target (default: negotiated virtual CD/DVD)
+  --mode <mode>       boot mode, UEFI or Legacy (default: unchanged)
+  --persistence <p>   how long the override applies, Once or Continuous (default Once)`,
+			DryRun: true,
+			Run:    (*EnhancedApp).setBoot,
+		},
+		{
+			Name:    "set-boot-cd",
+			Summary: "Set boot device to Virtual CD/DVD",
+			DryRun:  true,
+			Run:     noArgs((*EnhancedApp).setBootCD),
+		},
+		{
+			Name:    "set-boot-cd-enhanced",
+			Summary: "Set boot device to Virtual CD/DVD (Enhanced)",
+			DryRun:  true,
+			Run:     noArgs((*EnhancedApp).setVirtualCDBootEnhanced),
+		},
+		{
+			Name:    "set-boot-hdd",
+			Summary: "Set boot device to HDD",
+			DryRun:  true,
+			Run:     noArgs((*EnhancedApp).setBootHDD),
+		},
+		{
+			Name:    "boot-order",
+			Args:    "[show | set [--reboot] <refs...> | disk-first [--reboot]]",
+			Summary: "Show or change the persistent boot order",
+			Help: `  show                       show the persistent boot order (default)
+  set [--reboot] <refs...>   set the boot order to the given boot option references
+  disk-first [--reboot]      put the installation disk first
+  --reboot                   reset the system to apply the new order`,
+			Subcommands: []string{"show", "set", "disk-first"},
+			DryRun:      true,
+			Run:         (*EnhancedApp).bootOrder,
+		},
+		{
+			Name:    "lifecycle-controller",
+			Summary: "Get BMC manager (iDRAC lifecycle controller) information",
+			Run:     noArgs((*EnhancedApp).getLifecycleControllerInfo),
+		},
+		{
+			Name:    "cleanup",
+			Args:    "[poweroff]",
+			Summary: "Eject virtual media and restore the boot device, optionally powering off",
+			DryRun:  true,
+			Run: func(a *EnhancedApp, ctx context.Context, args []string) error {
+				powerOff := false
+				switch {
+				case len(args) == 1 && args[0] == "poweroff":
+					powerOff = true
+					if err := a.confirm("Power off %s after cleanup", a.config.IDRAC.IP); err != nil {
+						return err
+					}
+				case len(args) > 0:
+					return usagef("usage: cleanup [poweroff]")
+				}
+				return a.cleanup(ctx, powerOff)
+			},
+		},
+		{
+			Name:    "preflight",
+			Summary: "Validate agent-config.yaml and install-config.yaml against the hardware",
+			Run:     noArgs((*EnhancedApp).runPreflight),
+		},
+		{
+			Name:    "inventory",
+			Args:    "[--format json|yaml] [--file <path>]",
+			Summary: "Write a hardware inventory report",
+			Help: `  --format <format>   report format, json or yaml (default json)
+  --file <path>       report file (default: inventory.<format>)`,
+			Run: (*EnhancedApp).inventory,
+		},
+		{
+			Name:    "storage",
+			Args:    "[list | clear-foreign | create [<drives...>] | delete <volume> | non-raid <drives...> | prepare]",
+			Summary: "List controllers and volumes, or prepare the boot disk",
+			Help: `  list                   list controllers, drives and volumes (default)
+  clear-foreign          clear the foreign configuration (Dell iDRAC only)
+  create [--raid RAID0|RAID1] [--name <name>] [<drives...>]
+                         create a volume
+  delete <volume>        delete a volume
+  non-raid <drives...>   convert drives to non-RAID (Dell iDRAC only)
+  prepare                build the boot volume described by the storage section
+  --controller <id>      storage controller ID (default: first controller with drives)`,
+			Subcommands: []string{"list", "clear-foreign", "create", "delete", "non-raid", "prepare"},
+			Run:         (*EnhancedApp).storage,
+		},
+		{
+			Name:    "bios",
+			Args:    "[show | diff | apply [--reboot]] [--profile <file>]",
+			Summary: "Show BIOS attributes, or diff or apply them against a profile",
+			Help: `  show               show the BIOS attributes (default)
+  diff               compare the BIOS attributes with the profile
+  apply [--reboot]   stage the profile, resetting the system to apply it with --reboot
+  --profile <file>   BIOS profile YAML file (default: bios.profile)`,
+			Subcommands: []string{"show", "diff", "apply"},
+			Run:         (*EnhancedApp).bios,
+		},
+		{
+			Name:    "firmware",
+			Args:    "[list | check | update] [--manifest <file>]",
+			Summary: "List firmware, or check or update it against a baseline manifest",
+			Help: `  list                list the installed firmware (default)
+  check               compare the firmware with the baseline
+  update              update the outdated firmware
+  --manifest <file>   firmware baseline manifest YAML file (default: firmware.manifest)`,
+			Subcommands: []string{"list", "check", "update"},
+			Run:         (*EnhancedApp).firmware,
+		},
+		{
+			Name:    "scp",
+			Args:    "export|import [flags]",
+			Summary: "Export or import the iDRAC Server Configuration Profile (Dell iDRAC only)",
+			Help: `  export                export the profile (--format xml|json, --export-use Default|Clone|Replace)
+  import [--preview]    import the profile (--shutdown Graceful|Forced|NoReboot)
+  --file <path>         profile file (default: scp.xml or scp.json in paths.source_dir)
+  --target <list>       components: ALL or a comma-separated list of BIOS, IDRAC, NIC, RAID, ...`,
+			Subcommands: []string{"export", "import"},
+			Run:         (*EnhancedApp).scp,
+		},
+		{
+			Name:    "accounts",
+			Args:    "[list | password <user> | create <user> | enable <user> | disable <user>]",
+			Summary: "List or manage BMC accounts",
+			Help: `  list                 list the accounts (default)
+  password <user>      change the password of an account
+  create [--role Administrator|Operator|ReadOnly] <user>
+                       create an account
+  enable <user>        enable an account
+  disable <user>       disable an account
+  --generate           generate a random password and print it
+  --password-stdin     read the password from the first line of stdin`,
+			Subcommands: []string{"list", "password", "create", "enable", "disable"},
+			Run:         (*EnhancedApp).accounts,
+		},
+		{
+			Name:    "bmc-network",
+			Args:    "[show | apply [--ntp <list>] [--dns <list>] [--vlan <id>]]",
+			Summary: "Show BMC NTP, DNS and VLAN settings, or apply them",
+			Help: `  show              show the settings (default)
+  apply             apply the bmc.network section of the configuration
+  --ntp <list>      comma-separated NTP servers
+  --dns <list>      comma-separated static DNS servers
+  --vlan <id>       VLAN id of the BMC traffic, 0 disables tagging`,
+			Subcommands: []string{"show", "apply"},
+			Run:         (*EnhancedApp).bmcNetwork,
+		},
+		{
+			Name:    "certificate",
+			Args:    "[show | csr [flags] | install [--reset-bmc] <cert.pem> | trust [--fingerprint <sha256>]]",
+			Summary: "Show, request, install or trust the BMC HTTPS certificate",
+			Help: `  show                             show the certificate and its fingerprint (default)
+  csr                              generate a CSR (--cn, --san, --org, --ou, --city, --state, --country, --file)
+  install [--reset-bmc] <cert.pem> install a signed certificate
+  trust [--fingerprint <sha256>]   trust the certificate the BMC serves`,
+			Subcommands: []string{"show", "csr", "install", "trust"},
+			// Trusting a replaced certificate cannot wait for connectBMC,
+			// which fails on the fingerprint mismatch
+			NoBMC: true,
+			Run: func(a *EnhancedApp, ctx context.Context, args []string) error {
+				if len(args) > 0 && args[0] == "trust" {
+					return a.trustCertificate(ctx, args[1:])
+				}
+				if err := a.connectBMC(ctx); err != nil {
+					return err
+				}
+				return a.certificate(ctx, args)
+			},
+		},
+		{
+			Name:    "logs",
+			Args:    "sel|lc [--since <time>] [--until <time>] [--severity <list>] [--message-id <list>] [--limit <n>] [--file <path>]",
+			Summary: "Read the System Event Log or the Lifecycle Controller log (lc: Dell iDRAC only)",
+			Help: `  --since <time>       only entries created after this time (RFC3339, or a duration such as 2h)
+  --until <time>       only entries created before this time
+  --severity <list>    comma-separated severities to keep, e.g. Warning,Critical
+  --message-id <list>  comma-separated message IDs to keep, e.g. PR7,SYS1003
+  --limit <n>          stop after this many matching entries
+  --file <path>        also write the entries to this JSON file`,
+			Subcommands: []string{"sel", "lc"},
+			Run:         (*EnhancedApp).logs,
+		},
+		{
+			Name:    "events",
+			Summary: "Subscribe to BMC events (SSE or HTTPS push) and log them until interrupted",
+			Run:     noArgs((*EnhancedApp).watchEvents),
+		},
+		{
+			Name:    "metrics",
+			Args:    "[--listen <address>] [--interval <duration>]",
+			Summary: "Serve fan, temperature, PSU and power-cap readings as Prometheus metrics",
+			Help: `  --listen <address>     address of the Prometheus endpoint (default: metrics.listen or :9610)
+  --interval <duration>  telemetry poll interval (default: metrics.interval or 30s)`,
+			Run: (*EnhancedApp).serveMetrics,
+		},
+		{
+			Name:    "fleet",
+			Args:    "install [--inventory <file>] [--concurrency <n>] [--sites <list>] [--resume | --from-step <step>]",
+			Summary: "Install many SNO sites from an inventory file",
+			Help: `  install              run install for every site of the inventory and summarize the results
+  --inventory <file>   fleet inventory (default fleet.yaml)
+  --concurrency <n>    sites installed at once (default: concurrency of the inventory, or 4)
+  --sites <list>       comma-separated sites to install (default: all)
+  --resume             resume every site after its last completed step
+  --from-step <step>   rerun every site from this install step on`,
+			Subcommands: []string{"install"},
+			DryRun:      true,
+			// Every site loads its own configuration and connects to its BMC
+			NoConfig: true,
+			NoBMC:    true,
+			Run:      (*EnhancedApp).fleet,
+		},
+		{
+			Name:    "completion",
+			Args:    "bash|zsh|fish",
+			Summary: "Print a shell completion script",
+			Help: `  bash   source <(openshift-sno-hub-installer completion bash)
+  zsh    source <(openshift-sno-hub-installer completion zsh)
+  fish   openshift-sno-hub-installer completion fish | source`,
+			Subcommands: []string{"bash", "zsh", "fish"},
+			Builtin:     printCompletion,
+		},
+		{
+			Name:    "help",
+			Args:    "[command]",
+			Summary: "Show help for the installer or a command",
+			Builtin: printHelp,
+		},
+	}
+}
+
+// findCommand returns the command called name, or nil
+func findCommand(name string) *command {
+	for _, cmd := range commands() {
+		if cmd.Name == name {
+			cmd := cmd
+			return &cmd
+		}
+	}
+	return nil
+}
+
+// globalFlagSet defines the global flags on a new flag set
+func globalFlagSet(opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(programName, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&opts.ConfigFile, "config", config.DefaultConfigFile, "configuration file")
+	fs.StringVar(&opts.LogLevel, "log-level", "info", "log level: debug, info, warn or error")
+	fs.StringVar(&opts.Output, "output", "text", "output format: text, or json or yaml for documents on stdout with the log on stderr")
+	fs.StringVar(&opts.ISOURL, "iso-url", "", "ISO URL, overriding remote.iso_url")
+	fs.BoolVar(&opts.Yes, "yes", false, "do not ask for confirmation before disruptive actions")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "print the changes a command would make without making them")
+	return fs
+}
+
+// parseCommandLine splits the command line into the global flags, the command
+// and its arguments. Global flags may appear before or after the command;
+// without a command, install runs. help is returned for -h and --help.
+func parseCommandLine(args []string) (*options, *command, []string, error) {
+	opts := &options{}
+	fs := globalFlagSet(opts)
+
+	var global, rest []string
+	helpRequested := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		if arg == "-h" || arg == "-help" || arg == "--help" {
+			helpRequested = true
+			continue
+		}
+		name, hasValue := flagName(arg)
+		f := fs.Lookup(name)
+		if f == nil {
+			rest = append(rest, arg)
+			continue
+		}
+		global = append(global, arg)
+		if isBool, ok := f.Value.(interface{ IsBoolFlag() bool }); !hasValue && !(ok && isBool.IsBoolFlag()) && i+1 < len(args) {
+			i++
+			global = append(global, args[i])
+		}
+	}
+
+	if err := fs.Parse(global); err != nil {
+		return nil, nil, nil, usagef("%v", err)
+	}
+	switch opts.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		return nil, nil, nil, usagef("invalid --log-level %q, expected debug, info, warn or error", opts.LogLevel)
+	}
+	switch opts.Output {
+	case "text", "json", "yaml":
+	default:
+		return nil, nil, nil, usagef("invalid --output %q, expected text, json or yaml", opts.Output)
+	}
+
+	if len(rest) > 0 && strings.HasPrefix(rest[0], "-") && rest[0] != "--" {
+		return nil, nil, nil, usagef("unknown flag %s", rest[0])
+	}
+
+	name := "install"
+	if len(rest) > 0 {
+		name, rest = rest[0], rest[1:]
+	}
+	cmd := findCommand(name)
+	if cmd == nil {
+		return nil, nil, nil, usagef("unknown command %q", name)
+	}
+	if helpRequested {
+		if name == "help" {
+			return opts, cmd, nil, nil
+		}
+		return opts, findCommand("help"), []string{name}, nil
+	}
+	if opts.DryRun && !cmd.DryRun {
+		return nil, nil, nil, usagef("--dry-run is not supported by %s", name)
+	}
+	return opts, cmd, rest, nil
+}
+
+// flagName returns the name of a -name, --name or --name=value argument and
+// whether it carries its value
+func flagName(arg string) (string, bool) {
+	if len(arg) < 2 || arg[0] != '-' {
+		return "", false
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+	if i := strings.Index(name, "="); i >= 0 {
+		return name[:i], true
+	}
+	return name, false
+}
+
+// Main runs the command line args, without the program name, and returns the
+// exit code: 0 on success, 1 when the command failed and 2 on bad usage
+func Main(args []string) int {
+	opts, cmd, cmdArgs, err := parseCommandLine(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\nRun '%s help' for usage.\n", err, programName)
+		return exitUsage
+	}
+
+	if cmd.Builtin != nil {
+		if err := cmd.Builtin(opts, cmdArgs); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\nRun '%s help %s' for usage.\n", err, programName, cmd.Name)
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	// Initialize logger
+	log := logger.NewLogger()
+	defer log.Close()
+	if err := configureLogger(log, opts); err != nil {
+		log.LogError("%v", err)
+		return exitUsage
+	}
+
+	// Load configuration, except for the command that creates it
+	cfg := &config.Config{}
+	if !cmd.NoConfig {
+		if cfg, err = config.LoadConfig(opts.ConfigFile); err != nil {
+			log.LogError("Failed to load configuration: %v", err)
+			return exitFailure
+		}
+	}
+	if opts.ISOURL != "" {
+		cfg.Remote.ISOURL = opts.ISOURL
+	}
+
+	application := newApp(cfg, log, opts)
+
+	// Set up signal handling for graceful shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-sigChan
+		log.Info("Received shutdown signal, cleaning up...")
+		cancel()
+	}()
+
+	runErr := application.execute(ctx, cmd, cmdArgs)
+
+	// Always delete the BMC session, including after SIGINT cancelled ctx
+	closeCtx, closeCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer closeCancel()
+	if err := application.Close(closeCtx); err != nil {
+		log.LogWarn("Failed to close BMC session: %v", err)
+	}
+
+	var usage *usageError
+	switch {
+	case errors.As(runErr, &usage):
+		log.LogError("%v", runErr)
+		fmt.Fprintf(os.Stderr, "Run '%s help %s' for usage.\n", programName, cmd.Name)
+		return exitUsage
+	case runErr != nil:
+		log.LogError("Application failed: %v", runErr)
+		return exitFailure
+	}
+
+	log.Info("Application completed successfully")
+	return exitOK
+}
+
+// configureLogger applies --log-level and --output to log
+func configureLogger(log *logger.Logger, opts *options) error {
+	if err := log.SetLevelName(opts.LogLevel); err != nil {
+		return err
+	}
+	// Keep stdout to the documents of --output json|yaml
+	if opts.Output != "text" {
+		log.UseStderr()
+	}
+	if opts.Output == "json" {
+		log.SetJSONFormat()
+	}
+	return nil
+}
+
+// newApp creates the application that runs a command with the global flags
+// in opts
+func newApp(cfg *config.Config, log *logger.Logger, opts *options) *EnhancedApp {
+	application := NewEnhancedApp(cfg, log)
+	application.options = *opts
+	if opts.Output != "text" {
+		application.runner.SetOutput(os.Stderr)
+	}
+	return application
+}
+
+// execute runs cmd, connecting to the BMC first unless the command does not
+// need it
+func (a *EnhancedApp) execute(ctx context.Context, cmd *command, args []string) error {
+	if a.options.DryRun {
+		a.runner.SetDryRun(true)
+		a.logger.LogWarn("Dry run: nothing is changed, the planned changes are printed")
+	}
+
+	if !cmd.NoBMC {
+		if err := a.connectBMC(ctx); err != nil {
+			return err
+		}
+	}
+	return cmd.Run(a, ctx, args)
+}
+
+// isoURL returns the ISO URL given as the only argument, or the configured one
+func (a *EnhancedApp) isoURL(args []string) (string, error) {
+	switch {
+	case len(args) > 1:
+		return "", usagef("unexpected arguments: %s", strings.Join(args[1:], " "))
+	case len(args) == 1:
+		return args[0], nil
+	case a.config.Remote.ISOURL != "":
+		return a.config.Remote.ISOURL, nil
+	default:
+		return "", usagef("please provide the ISO URL as argument, with --iso-url or as remote.iso_url")
+	}
+}
+
+// confirm asks before a disruptive action. It passes without asking with
+// --yes or --dry-run, and fails when there is no terminal to ask on.
+func (a *EnhancedApp) confirm(format string, args ...interface{}) error {
+	if a.options.Yes || a.runner.DryRun() {
+		return nil
+	}
+
+	action := fmt.Sprintf(format, args...)
+	required := usagef("%s: confirmation required, pass --yes to run without a terminal", action)
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return required
+	}
+
+	fmt.Fprintf(os.Stderr, "%s? [y/N] ", action)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Fprintln(os.Stderr)
+		return required
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return fmt.Errorf("%s: not confirmed", action)
+	}
+}
+
+// printHelp prints the help of the installer, or of the command named in args
+func printHelp(_ *options, args []string) error {
+	if len(args) > 1 {
+		return usagef("usage: help [command]")
+	}
+	if len(args) == 1 {
+		cmd := findCommand(args[0])
+		if cmd == nil {
+			return usagef("unknown command %q", args[0])
+		}
+		printCommandHelp(os.Stdout, cmd)
+		return nil
+	}
+
+	out := os.Stdout
+	fmt.Fprintf(out, "Usage: %s [global flags] <command> [arguments]\n", programName)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Commands:")
+	for _, cmd := range commands() {
+		fmt.Fprintf(out, "  %-21s %s\n", cmd.Name, cmd.Summary)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Global flags:")
+	printGlobalFlags(out)
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "Run '%s help <command>' for the arguments and flags of a command.\n", programName)
+	return nil
+}
+
+// printCommandHelp prints the synopsis and flags of cmd
+func printCommandHelp(out io.Writer, cmd *command) {
+	fmt.Fprintf(out, "Usage: %s [global flags] %s", programName, cmd.Name)
+	if cmd.Args != "" {
+		fmt.Fprintf(out, " %s", cmd.Args)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, cmd.Summary)
+	if cmd.Help != "" {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, cmd.Help)
+	}
+	if cmd.DryRun {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Accepts --dry-run to print the planned changes without making them.")
+	}
+}
+
+// printGlobalFlags prints the global flags and their defaults
+func printGlobalFlags(out io.Writer) {
+	fs := globalFlagSet(&options{})
+	fs.SetOutput(out)
+	fs.PrintDefaults()
+	fmt.Fprintln(out, "  -h, --help\n    \tshow help")
+}
+
+// printCompletion prints the completion script of the shell named in args
+func printCompletion(_ *options, args []string) error {
+	if len(args) != 1 {
+		return usagef("usage: completion bash|zsh|fish")
+	}
+
+	var names []string
+	subcommands := make(map[string][]string)
+	for _, cmd := range commands() {
+		names = append(names, cmd.Name)
+		if len(cmd.Subcommands) > 0 {
+			subcommands[cmd.Name] = cmd.Subcommands
+		}
+	}
+	subcommands["help"] = names
+
+	var flags []string
+	globalFlagSet(&options{}).VisitAll(func(f *flag.Flag) {
+		flags = append(flags, f.Name)
+	})
+
+	switch args[0] {
+	case "bash":
+		fmt.Print(bashCompletion(names, subcommands, flags, false))
+	case "zsh":
+		fmt.Print(bashCompletion(names, subcommands, flags, true))
+	case "fish":
+		fmt.Print(fishCompletion(names, subcommands, flags))
+	default:
+		return usagef("unsupported shell %q, expected bash, zsh or fish", args[0])
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// bashCompletion returns a bash completion script; zsh loads it through
+// bashcompinit
+func bashCompletion(names []string, subcommands map[string][]string, flags []string, zsh bool) string {
+	var b strings.Builder
+	if zsh {
+		b.WriteString("autoload -U +X bashcompinit && bashcompinit\n")
+	}
+
+	fn := "_" + strings.ReplaceAll(programName, "-", "_")
+	var long []string
+	for _, name := range flags {
+		long = append(long, "--"+name)
+	}
+
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cur=${COMP_WORDS[COMP_CWORD]} command=\"\" i\n")
+	b.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("        case ${COMP_WORDS[i]} in\n")
+	b.WriteString("            --config|--log-level|--output|--iso-url) ((i++)) ;;\n")
+	b.WriteString("            -*) ;;\n")
+	b.WriteString("            *) command=${COMP_WORDS[i]}; break ;;\n")
+	b.WriteString("        esac\n")
+	b.WriteString("    done\n")
+	b.WriteString("    case ${COMP_WORDS[COMP_CWORD-1]} in\n")
+	b.WriteString("        --log-level) COMPREPLY=($(compgen -W \"debug info warn error\" -- \"$cur\")); return ;;\n")
+	b.WriteString("        --output) COMPREPLY=($(compgen -W \"text json yaml\" -- \"$cur\")); return ;;\n")
+	b.WriteString("        --config) COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n")
+	b.WriteString("    esac\n")
+	b.WriteString("    if [[ $cur == -* ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(long, " "))
+	b.WriteString("        return\n")
+	b.WriteString("    fi\n")
+	b.WriteString("    case $command in\n")
+	fmt.Fprintf(&b, "        \"\") COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")) ;;\n", strings.Join(names, " "))
+	for _, name := range sortedKeys(subcommands) {
+		fmt.Fprintf(&b, "        %s) COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")) ;;\n", name, strings.Join(subcommands[name], " "))
+	}
+	b.WriteString("        *) COMPREPLY=($(compgen -f -- \"$cur\")) ;;\n")
+	b.WriteString("    esac\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -F %s %s\n", fn, programName)
+	return b.String()
+}
+
+// fishCompletion returns a fish completion script
+func fishCompletion(names []string, subcommands map[string][]string, flags []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "complete -c %s -f\n", programName)
+	fmt.Fprintf(&b, "complete -c %s -n __fish_use_subcommand -a \"%s\"\n", programName, strings.Join(names, " "))
+	for _, name := range sortedKeys(subcommands) {
+		fmt.Fprintf(&b, "complete -c %s -n \"__fish_seen_subcommand_from %s\" -a \"%s\"\n",
+			programName, name, strings.Join(subcommands[name], " "))
+	}
+	for _, name := range flags {
+		switch name {
+		case "config":
+			fmt.Fprintf(&b, "complete -c %s -l %s -r -F\n", programName, name)
+		case "log-level":
+			fmt.Fprintf(&b, "complete -c %s -l %s -x -a \"debug info warn error\"\n", programName, name)
+		case "output":
+			fmt.Fprintf(&b, "complete -c %s -l %s -x -a \"text json yaml\"\n", programName, name)
+		case "iso-url":
+			fmt.Fprintf(&b, "complete -c %s -l %s -x\n", programName, name)
+		default:
+			fmt.Fprintf(&b, "complete -c %s -l %s\n", programName, name)
+		}
+	}
+	return b.String()
+}
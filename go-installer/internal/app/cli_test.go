@@ -0,0 +1,174 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+
+	"openshift-sno-hub-installer/internal/config"
+)
+
+func TestParseCommandLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCmd  string
+		wantArgs []string
+		wantOpts options
+		wantErr  bool
+	}{
+		{
+			name:     "install by default",
+			wantCmd:  "install",
+			wantOpts: options{ConfigFile: config.DefaultConfigFile, LogLevel: "info", Output: "text"},
+		},
+		{
+			name:     "global flags before the command",
+			args:     []string{"--yes", "--config", "site.yaml", "status"},
+			wantCmd:  "status",
+			wantOpts: options{ConfigFile: "site.yaml", LogLevel: "info", Output: "text", Yes: true},
+		},
+		{
+			name:     "global flags after the command",
+			args:     []string{"power", "cap", "450", "--output=json", "--exception", "NoAction", "--log-level", "debug"},
+			wantCmd:  "power",
+			wantArgs: []string{"cap", "450", "--exception", "NoAction"},
+			wantOpts: options{ConfigFile: config.DefaultConfigFile, LogLevel: "debug", Output: "json"},
+		},
+		{
+			name:     "arguments after -- are left alone",
+			args:     []string{"insert-media", "--", "--yes"},
+			wantCmd:  "insert-media",
+			wantArgs: []string{"--", "--yes"},
+			wantOpts: options{ConfigFile: config.DefaultConfigFile, LogLevel: "info", Output: "text"},
+		},
+		{
+			name:     "help flag",
+			args:     []string{"power", "--help"},
+			wantCmd:  "help",
+			wantArgs: []string{"power"},
+			wantOpts: options{ConfigFile: config.DefaultConfigFile, LogLevel: "info", Output: "text"},
+		},
+		{
+			name:     "help command",
+			args:     []string{"help", "accounts"},
+			wantCmd:  "help",
+			wantArgs: []string{"accounts"},
+			wantOpts: options{ConfigFile: config.DefaultConfigFile, LogLevel: "info", Output: "text"},
+		},
+		{name: "unknown command", args: []string{"frobnicate"}, wantErr: true},
+		{name: "unknown flag before the command", args: []string{"--frobnicate", "status"}, wantErr: true},
+		{name: "invalid log level", args: []string{"--log-level", "trace", "status"}, wantErr: true},
+		{name: "invalid output", args: []string{"--output", "xml", "status"}, wantErr: true},
+		{name: "dry run of a read-only command", args: []string{"--dry-run", "status"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, cmd, args, err := parseCommandLine(tt.args)
+			if tt.wantErr {
+				var usage *usageError
+				if !errors.As(err, &usage) {
+					t.Fatalf("Expected a usage error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCommandLine failed: %v", err)
+			}
+			if cmd.Name != tt.wantCmd {
+				t.Errorf("Expected command %s, got %s", tt.wantCmd, cmd.Name)
+			}
+			if len(args) != 0 || len(tt.wantArgs) != 0 {
+				if !reflect.DeepEqual(args, tt.wantArgs) {
+					t.Errorf("Expected arguments %q, got %q", tt.wantArgs, args)
+				}
+			}
+			if *opts != tt.wantOpts {
+				t.Errorf("Expected options %+v, got %+v", tt.wantOpts, *opts)
+			}
+		})
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		wantRole       string
+		wantGenerate   bool
+		wantPositional []string
+		wantErr        bool
+	}{
+		{"flags first", []string{"--role", "Operator", "sno"}, "Operator", false, []string{"sno"}, false},
+		{"flags last", []string{"sno", "--role", "Operator", "--generate"}, "Operator", true, []string{"sno"}, false},
+		{"flags between", []string{"a", "--generate", "b"}, "ReadOnly", true, []string{"a", "b"}, false},
+		{"double dash", []string{"a", "--", "--role", "b"}, "ReadOnly", false, []string{"a", "--role", "b"}, false},
+		{"no arguments", nil, "ReadOnly", false, nil, false},
+		{"unknown flag", []string{"sno", "--admin"}, "", false, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("accounts create", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			role := fs.String("role", "ReadOnly", "")
+			generate := fs.Bool("generate", false, "")
+
+			err := parseFlags(fs, tt.args)
+			if tt.wantErr {
+				var usage *usageError
+				if !errors.As(err, &usage) {
+					t.Fatalf("Expected a usage error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFlags failed: %v", err)
+			}
+			if *role != tt.wantRole || *generate != tt.wantGenerate {
+				t.Errorf("Expected --role %s --generate=%v, got %s %v", tt.wantRole, tt.wantGenerate, *role, *generate)
+			}
+			if len(fs.Args()) != 0 || len(tt.wantPositional) != 0 {
+				if !reflect.DeepEqual(fs.Args(), tt.wantPositional) {
+					t.Errorf("Expected positional arguments %q, got %q", tt.wantPositional, fs.Args())
+				}
+			}
+		})
+	}
+}
+
+func TestMainExitCodes(t *testing.T) {
+	// Main writes its log under the working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"help", []string{"help"}, exitOK},
+		{"command help", []string{"help", "install"}, exitOK},
+		{"unknown command", []string{"frobnicate"}, exitUsage},
+		{"unknown help topic", []string{"help", "frobnicate"}, exitUsage},
+		{"invalid global flag", []string{"--output", "xml", "status"}, exitUsage},
+		{"missing configuration", []string{"--config", "missing/idrac_config.yaml", "status"}, exitFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Main(tt.args); got != tt.want {
+				t.Errorf("Expected exit code %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"openshift-sno-hub-installer/internal/events"
+	"openshift-sno-hub-installer/internal/idrac"
+)
+
+// streamRetryInterval is the delay before reconnecting a dropped event stream
+const streamRetryInterval = 5 * time.Second
+
+// watchEvents subscribes to BMC events and logs them until interrupted
+func (a *EnhancedApp) watchEvents(ctx context.Context) error {
+	if err := a.startEvents(ctx); err != nil {
+		return err
+	}
+
+	a.logger.LogInfo("Watching BMC events, press Ctrl-C to stop...")
+	<-ctx.Done()
+	return nil
+}
+
+// startEvents receives BMC events over SSE or through a push subscription to
+// an embedded HTTPS listener. Power and virtual media waits wake up as the
+// events arrive, and every event is logged. Close removes the subscription.
+func (a *EnhancedApp) startEvents(ctx context.Context) error {
+	if a.stopEvents != nil {
+		return nil
+	}
+
+	client := a.bmc.Redfish()
+	service, err := client.GetEventService(ctx)
+	if err != nil {
+		return err
+	}
+
+	hub := idrac.NewEventHub()
+	mode := valueOr(a.config.Events.Mode, "auto")
+	eventsCtx, cancel := context.WithCancel(ctx)
+
+	switch {
+	case mode == "sse" || (mode == "auto" && service.ServerSentEventURI != ""):
+		if service.ServerSentEventURI == "" {
+			cancel()
+			return fmt.Errorf("the BMC does not offer server-sent events")
+		}
+		go a.streamEvents(eventsCtx, service.ServerSentEventURI, hub)
+		a.stopEvents = func(context.Context) error {
+			cancel()
+			return nil
+		}
+
+	default:
+		destination, err := events.Destination(&a.config.Events, a.config.IDRAC.IP)
+		if err != nil {
+			cancel()
+			return err
+		}
+		listener := events.NewListener(&a.config.Events, hub, a.logger)
+		if err := listener.Start(destination); err != nil {
+			cancel()
+			return err
+		}
+		subscription, err := client.Subscribe(ctx, destination, listener.Token())
+		if err != nil {
+			cancel()
+			listener.Close(ctx)
+			return err
+		}
+		a.stopEvents = func(ctx context.Context) error {
+			cancel()
+			defer listener.Close(ctx)
+			a.logger.LogInfo("Removing BMC event subscription %s", subscription)
+			return client.Unsubscribe(ctx, subscription)
+		}
+	}
+
+	client.SetEventHub(hub)
+	go a.logEvents(eventsCtx, hub)
+	return nil
+}
+
+// closeEvents stops receiving BMC events and removes the push subscription
+func (a *EnhancedApp) closeEvents(ctx context.Context) {
+	if a.stopEvents == nil {
+		return
+	}
+	if err := a.stopEvents(ctx); err != nil {
+		a.logger.LogWarn("Failed to remove BMC event subscription: %v", err)
+	}
+	a.stopEvents = nil
+}
+
+// streamEvents publishes the server-sent events of the BMC on hub,
+// reconnecting when the stream drops
+func (a *EnhancedApp) streamEvents(ctx context.Context, uri string, hub *idrac.EventHub) {
+	for {
+		err := a.bmc.Redfish().StreamEvents(ctx, uri, hub.Publish)
+		if ctx.Err() != nil {
+			return
+		}
+		a.logger.LogWarn("BMC event stream interrupted, reconnecting: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(streamRetryInterval):
+		}
+	}
+}
+
+// logEvents logs every BMC event at the level matching its severity
+func (a *EnhancedApp) logEvents(ctx context.Context, hub *idrac.EventHub) {
+	received, unsubscribe := hub.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-received:
+			line := fmt.Sprintf("BMC event [%s] %s: %s", valueOr(event.Level(), event.EventType), event.MessageID, event.Message)
+			switch strings.ToLower(event.Level()) {
+			case "critical":
+				a.logger.LogError("%s", line)
+			case "warning":
+				a.logger.LogWarn("%s", line)
+			default:
+				a.logger.LogInfo("%s", line)
+			}
+		}
+	}
+}
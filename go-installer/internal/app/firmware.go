@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"openshift-sno-hub-installer/internal/idrac"
+)
+
+// firmware dispatches the firmware subcommands: list (default), check and update
+func (a *EnhancedApp) firmware(ctx context.Context, args []string) error {
+	subcommand := "list"
+	if len(args) > 0 {
+		subcommand, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("firmware "+subcommand, flag.ContinueOnError)
+	manifestPath := fs.String("manifest", a.config.Firmware.Manifest, "firmware baseline manifest YAML file")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	switch subcommand {
+	case "list":
+		return a.listFirmware(ctx)
+	case "check":
+		manifest, err := a.loadFirmwareManifest(*manifestPath)
+		if err != nil {
+			return err
+		}
+		_, err = a.checkFirmware(ctx, manifest)
+		return err
+	case "update":
+		if err := a.confirm("Update the firmware of %s to %s", a.config.IDRAC.IP, *manifestPath); err != nil {
+			return err
+		}
+		return a.updateFirmware(ctx, *manifestPath)
+	default:
+		return usagef("unknown firmware subcommand %q (expected list, check or update)", subcommand)
+	}
+}
+
+// loadFirmwareManifest loads the firmware baseline manifest from path
+func (a *EnhancedApp) loadFirmwareManifest(path string) (*idrac.FirmwareManifest, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no firmware manifest given, set firmware.manifest or pass --manifest")
+	}
+	return idrac.LoadFirmwareManifest(path)
+}
+
+// listFirmware logs the installed firmware
+func (a *EnhancedApp) listFirmware(ctx context.Context) error {
+	inventory, err := a.bmc.Redfish().GetFirmwareInventory(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.logger.LogInfo("Installed firmware:")
+	for _, entry := range inventory {
+		a.logger.LogInfo("  %s: %s (%s)", entry.Name, entry.Version, entry.ID)
+	}
+	return nil
+}
+
+// checkFirmware compares the installed firmware with the manifest and
+// returns the components that need an update
+func (a *EnhancedApp) checkFirmware(ctx context.Context, manifest *idrac.FirmwareManifest) ([]idrac.FirmwareComponent, error) {
+	inventory, err := a.bmc.Redfish().GetFirmwareInventory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var outdated []idrac.FirmwareComponent
+	a.logger.LogInfo("Firmware baseline %s:", manifest.Name)
+	for _, status := range idrac.CompareFirmware(inventory, manifest) {
+		installed := strings.Join(status.Installed, ", ")
+		switch {
+		case !status.Found():
+			a.logger.LogWarn("  %s: not found on this server", status.Component.Name)
+		case status.Current():
+			a.logger.LogInfo("  %s: %s (current)", status.Component.Name, installed)
+		default:
+			a.logger.LogWarn("  %s: %s, baseline %s", status.Component.Name, installed, status.Component.Version)
+			outdated = append(outdated, status.Component)
+		}
+	}
+
+	if len(outdated) == 0 {
+		a.logger.LogSuccess("Firmware matches baseline %s", manifest.Name)
+	}
+	return outdated, nil
+}
+
+// updateFirmware brings every outdated component to the baseline. Updates that
+// need a reboot are staged first, the system is reset once, and every job is
+// tracked to completion through the reboots.
+func (a *EnhancedApp) updateFirmware(ctx context.Context, manifestPath string) error {
+	manifest, err := a.loadFirmwareManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	outdated, err := a.checkFirmware(ctx, manifest)
+	if err != nil || len(outdated) == 0 {
+		return err
+	}
+
+	client := a.bmc.Redfish()
+	manifestDir := filepath.Dir(manifestPath)
+
+	var scheduled []*idrac.PendingChange
+	for _, component := range outdated {
+		if component.URL == "" && component.File == "" {
+			return fmt.Errorf("firmware component %s has neither url nor file", component.Name)
+		}
+
+		var change *idrac.PendingChange
+		if a.config.Firmware.Method == "multipart" && component.URL == "" {
+			change, err = client.MultipartUpdate(ctx, filepath.Join(manifestDir, component.File))
+		} else {
+			var imageURL string
+			if imageURL, err = a.firmwareImageURL(ctx, manifestDir, component); err == nil {
+				change, err = client.SimpleUpdate(ctx, imageURL)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %w", component.Name, err)
+		}
+		if change.JobURI == "" {
+			continue
+		}
+
+		task, err := client.WaitForTaskOrReset(ctx, change.JobURI)
+		if err != nil {
+			return fmt.Errorf("firmware update of %s failed: %w", component.Name, err)
+		}
+		if task.Scheduled() {
+			scheduled = append(scheduled, change)
+		}
+	}
+
+	if len(scheduled) > 0 {
+		a.logger.LogInfo("%d firmware update(s) wait for a reboot, resetting the system...", len(scheduled))
+		if err := a.applyPendingChange(ctx, &idrac.PendingChange{}); err != nil {
+			return err
+		}
+		for _, change := range scheduled {
+			if _, err := client.WaitForTask(ctx, change.JobURI); err != nil {
+				return fmt.Errorf("firmware update job failed: %w", err)
+			}
+		}
+	}
+
+	remaining, err := a.checkFirmware(ctx, manifest)
+	if err != nil {
+		return err
+	}
+	if len(remaining) > 0 {
+		return fmt.Errorf("%d firmware component(s) still differ from baseline %s after the update", len(remaining), manifest.Name)
+	}
+	return nil
+}
+
+// firmwareImageURL returns the URL the BMC downloads a component from,
+// uploading the package to the web cache host first when configured
+func (a *EnhancedApp) firmwareImageURL(ctx context.Context, manifestDir string, component idrac.FirmwareComponent) (string, error) {
+	if component.URL != "" {
+		return component.URL, nil
+	}
+
+	if a.config.Firmware.Upload {
+		remoteDir := filepath.Join(a.config.Remote.Path, "firmware")
+		if err := a.sshManager.ExecuteRemoteCommand(ctx, "mkdir -p "+remoteDir); err != nil {
+			return "", fmt.Errorf("failed to create %s on remote host: %w", remoteDir, err)
+		}
+		localPath := filepath.Join(manifestDir, component.File)
+		if err := a.sshManager.CopyFileToRemote(ctx, localPath, filepath.Join(remoteDir, filepath.Base(component.File))); err != nil {
+			return "", err
+		}
+	}
+
+	return a.config.GetFirmwareBaseURL() + filepath.Base(component.File), nil
+}
@@ -0,0 +1,298 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"openshift-sno-hub-installer/internal/config"
+	"openshift-sno-hub-installer/internal/logger"
+	"openshift-sno-hub-installer/internal/openshift"
+	"openshift-sno-hub-installer/internal/ssh"
+)
+
+// defaultFleetConcurrency is the number of sites installed at once when
+// neither --concurrency nor the inventory set it
+const defaultFleetConcurrency = 4
+
+// Outcomes of a site in the fleet summary
+const (
+	siteSucceeded = "succeeded"
+	siteFailed    = "failed"
+)
+
+// fleetSite is a site selected for a fleet install, with its configuration
+type fleetSite struct {
+	site   *config.FleetSite
+	config *config.Config
+}
+
+// fleet runs the fleet subcommands
+func (a *EnhancedApp) fleet(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "install" {
+		return usagef("usage: fleet install [flags]")
+	}
+	return a.fleetInstall(ctx, args[1:])
+}
+
+// fleetInstall runs install for the sites of the fleet inventory, a limited
+// number at once. Every site has its own workdir, installer, install state
+// and logs in its site directory, so a failed site can be resumed on its own
+// with --sites and --resume.
+func (a *EnhancedApp) fleetInstall(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("fleet install", flag.ContinueOnError)
+	inventory := fs.String("inventory", config.DefaultFleetFile, "fleet inventory file")
+	concurrency := fs.Int("concurrency", 0, "sites installed at once (default: concurrency of the inventory, or 4)")
+	only := fs.String("sites", "", "comma-separated sites to install (default: all)")
+	resume := fs.Bool("resume", false, "resume every site after its last completed step")
+	fromStep := fs.String("from-step", "", "rerun every site from this install step on, e.g. boot")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usagef("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	if *concurrency < 0 {
+		return usagef("--concurrency must not be negative")
+	}
+	if *resume && *fromStep != "" {
+		return usagef("--resume and --from-step are mutually exclusive")
+	}
+	if a.options.ISOURL != "" {
+		return usagef("--iso-url does not apply to fleet install, set iso_url for every site")
+	}
+
+	fleet, err := config.LoadFleet(*inventory)
+	if err != nil {
+		return err
+	}
+	fleet.BaseConfig = valueOr(fleet.BaseConfig, a.options.ConfigFile)
+
+	sites, err := selectFleetSites(fleet, splitList(*only))
+	if err != nil {
+		return err
+	}
+
+	// The install flags are passed on to every site
+	var installArgs []string
+	if *resume {
+		installArgs = append(installArgs, "--resume")
+	}
+	if *fromStep != "" {
+		if stepIndex(a.installSteps(), *fromStep) < 0 {
+			return usagef("unknown install step %q, expected one of %s", *fromStep, stepNames(a.installSteps()))
+		}
+		installArgs = append(installArgs, "--from-step", *fromStep)
+	}
+
+	limit := *concurrency
+	if limit == 0 {
+		limit = fleet.Concurrency
+	}
+	if limit == 0 {
+		limit = defaultFleetConcurrency
+	}
+	if limit > len(sites) {
+		limit = len(sites)
+	}
+
+	names := make([]string, 0, len(sites))
+	for _, s := range sites {
+		names = append(names, s.site.Name)
+	}
+	if err := a.confirm("Install OpenShift on %d sites (%s)", len(sites), strings.Join(names, ", ")); err != nil {
+		return err
+	}
+
+	// The sites share the SSH key; create it before they start instead of
+	// letting each of them generate one
+	if err := ssh.NewManager(sites[0].config, a.logger, a.runner).CheckSSHKey(ctx); err != nil {
+		return fmt.Errorf("failed to check SSH key: %w", err)
+	}
+
+	a.logger.LogInfo("Installing %d sites, %d at once; site logs are in %s/<site>/logs", len(sites), limit, fleet.Dir)
+
+	results := make([]siteResult, len(sites))
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := range sites {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				results[i] = siteResult{Name: sites[i].site.Name, ClusterName: sites[i].config.OpenShift.ClusterName,
+					BMC: sites[i].config.IDRAC.IP, Status: siteFailed, Error: "not started: " + ctx.Err().Error()}
+				return
+			}
+			defer func() { <-slots }()
+			results[i] = a.installSite(ctx, fleet, sites[i], installArgs)
+		}(i)
+	}
+	wg.Wait()
+
+	return a.reportFleet(results)
+}
+
+// selectFleetSites builds the configuration of the sites named in only, or
+// of all sites, so a bad site is reported before any install starts
+func selectFleetSites(fleet *config.Fleet, only []string) ([]fleetSite, error) {
+	wanted := make(map[string]bool)
+	for _, name := range only {
+		wanted[name] = true
+	}
+
+	var sites []fleetSite
+	bmcs := make(map[string]string)
+	for i := range fleet.Sites {
+		site := &fleet.Sites[i]
+		if len(wanted) > 0 && !wanted[site.Name] {
+			continue
+		}
+		delete(wanted, site.Name)
+
+		cfg, err := fleet.SiteConfig(site)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.OpenShift.ClusterName, err = siteClusterName(site, cfg); err != nil {
+			return nil, err
+		}
+		if other, ok := bmcs[cfg.IDRAC.IP]; ok {
+			return nil, fmt.Errorf("sites %s and %s have the same BMC %s", other, site.Name, cfg.IDRAC.IP)
+		}
+		bmcs[cfg.IDRAC.IP] = site.Name
+		sites = append(sites, fleetSite{site: site, config: cfg})
+	}
+
+	for _, name := range only {
+		if wanted[name] {
+			return nil, usagef("unknown site %q in --sites", name)
+		}
+	}
+	return sites, nil
+}
+
+// siteClusterName returns the name the install-config.yaml of site gives the
+// cluster, and fails when the inventory expects another one
+func siteClusterName(site *config.FleetSite, cfg *config.Config) (string, error) {
+	installConfig, err := openshift.LoadInstallConfig(filepath.Join(cfg.Paths.SourceDir, "install-config.yaml"))
+	if err != nil {
+		return "", fmt.Errorf("site %s: %w", site.Name, err)
+	}
+	name := installConfig.Metadata.Name
+	if name == "" {
+		return "", fmt.Errorf("site %s: install-config.yaml sets no metadata.name", site.Name)
+	}
+	if site.ClusterName != "" && site.ClusterName != name {
+		return "", fmt.Errorf("site %s: cluster_name is %s but install-config.yaml names the cluster %s", site.Name, site.ClusterName, name)
+	}
+	return name, nil
+}
+
+// installSite runs install for one site with its own logger, runner and BMC
+// session, and returns its outcome
+func (a *EnhancedApp) installSite(ctx context.Context, fleet *config.Fleet, s fleetSite, installArgs []string) siteResult {
+	result := siteResult{
+		Name:        s.site.Name,
+		ClusterName: s.config.OpenShift.ClusterName,
+		BMC:         s.config.IDRAC.IP,
+		LogDir:      filepath.Join(fleet.SiteDir(s.site), "logs"),
+	}
+	start := time.Now()
+
+	log := logger.NewFileLogger(result.LogDir)
+	defer log.Close()
+	log.SetField("site", s.site.Name)
+
+	// The fleet was confirmed as a whole
+	opts := a.options
+	opts.Yes = true
+	site := newApp(s.config, log, &opts)
+	site.runner.SetPrefix(s.site.Name)
+
+	a.logger.LogInfo("Site %s: installing cluster %s through BMC %s", result.Name, result.ClusterName, result.BMC)
+	err := configureLogger(log, &opts)
+	if err == nil {
+		err = site.execute(ctx, findCommand("install"), installArgs)
+	}
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if closeErr := site.Close(closeCtx); closeErr != nil {
+		log.LogWarn("Failed to close BMC session: %v", closeErr)
+	}
+
+	result.Duration = time.Since(start).Round(time.Second).String()
+	if err != nil {
+		result.Status = siteFailed
+		result.Error = err.Error()
+		result.FailedStep = failedStep(site.installStatePath())
+		a.logger.LogError("Site %s: install failed: %v", result.Name, err)
+		return result
+	}
+
+	result.Status = siteSucceeded
+	a.logger.LogSuccess("Site %s: install completed in %s", result.Name, result.Duration)
+	return result
+}
+
+// failedStep returns the step recorded as failed in the install state file,
+// or "" when none is
+func failedStep(path string) string {
+	state, err := loadInstallState(path)
+	if err != nil || state == nil {
+		return ""
+	}
+	for name, record := range state.Steps {
+		if record.Status == stepFailed {
+			return name
+		}
+	}
+	return ""
+}
+
+// reportFleet logs or writes the summary of a fleet install; it fails when
+// a site failed
+func (a *EnhancedApp) reportFleet(results []siteResult) error {
+	summary := fleetSummary{Sites: results}
+	var failed []string
+	for _, result := range results {
+		if result.Status == siteSucceeded {
+			summary.Succeeded++
+			continue
+		}
+		summary.Failed++
+		failed = append(failed, result.Name)
+	}
+
+	if a.documentOutput() {
+		if err := a.writeDocument(kindFleetSummary, summary); err != nil {
+			return err
+		}
+	} else {
+		a.logger.LogInfo("Fleet install summary: %d succeeded, %d failed", summary.Succeeded, summary.Failed)
+		for _, result := range results {
+			outcome := result.Status
+			if result.FailedStep != "" {
+				outcome += " at " + result.FailedStep
+			}
+			a.logger.LogInfo("  %-20s %-24s %-10s %s", result.Name, outcome, result.Duration, result.LogDir)
+			if result.Error != "" {
+				a.logger.LogInfo("  %-20s %s", "", result.Error)
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d sites failed: %s; fix them and rerun with --sites %s",
+			len(failed), len(results), strings.Join(failed, ", "), strings.Join(failed, ","))
+	}
+	a.logger.LogSuccess("All %d sites installed", len(results))
+	return nil
+}
@@ -0,0 +1,204 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"openshift-sno-hub-installer/internal/config"
+)
+
+func TestSelectFleetSites(t *testing.T) {
+	tests := []struct {
+		name      string
+		sites     string
+		only      []string
+		wantSites []string
+		wantErr   string
+		usage     bool
+	}{
+		{
+			name:      "all sites",
+			sites:     "edge-01:10.0.1.10 edge-02:10.0.2.10 edge-03:10.0.3.10",
+			wantSites: []string{"edge-01", "edge-02", "edge-03"},
+		},
+		{
+			name:      "selected sites in inventory order",
+			sites:     "edge-01:10.0.1.10 edge-02:10.0.2.10 edge-03:10.0.3.10",
+			only:      []string{"edge-03", "edge-01"},
+			wantSites: []string{"edge-01", "edge-03"},
+		},
+		{
+			name:    "duplicate BMC",
+			sites:   "edge-01:10.0.1.10 edge-02:10.0.1.10",
+			wantErr: "sites edge-01 and edge-02 have the same BMC 10.0.1.10",
+		},
+		{
+			name:      "duplicate BMC of a site left out",
+			sites:     "edge-01:10.0.1.10 edge-02:10.0.1.10",
+			only:      []string{"edge-02"},
+			wantSites: []string{"edge-02"},
+		},
+		{
+			name:      "cluster name of install-config",
+			sites:     "edge-01:10.0.1.10:edge-01 edge-02:10.0.2.10",
+			wantSites: []string{"edge-01", "edge-02"},
+		},
+		{
+			name:    "cluster name mismatch",
+			sites:   "edge-01:10.0.1.10 edge-02:10.0.2.10:store-42",
+			wantErr: "site edge-02: cluster_name is store-42 but install-config.yaml names the cluster edge-02",
+		},
+		{
+			name:    "unknown site",
+			sites:   "edge-01:10.0.1.10",
+			only:    []string{"edge-01", "edge-09"},
+			wantErr: `unknown site "edge-09" in --sites`,
+			usage:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fleet := loadTestFleet(t, strings.Fields(tt.sites))
+
+			sites, err := selectFleetSites(fleet, tt.only)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				var usage *usageError
+				if errors.As(err, &usage) != tt.usage {
+					t.Errorf("Expected usage error %v, got %v", tt.usage, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectFleetSites failed: %v", err)
+			}
+			var got []string
+			listen := make(map[string]string)
+			for _, s := range sites {
+				got = append(got, s.site.Name)
+				if s.config.OpenShift.ClusterName != s.site.Name {
+					t.Errorf("Expected site %s to install cluster %s, got %s", s.site.Name, s.site.Name, s.config.OpenShift.ClusterName)
+				}
+				if other, ok := listen[s.config.Events.Listen]; ok {
+					t.Errorf("Sites %s and %s both listen for events on %s", other, s.site.Name, s.config.Events.Listen)
+				}
+				listen[s.config.Events.Listen] = s.site.Name
+				if s.config.Paths.StateFile != filepath.Join(fleet.SiteDir(s.site), "install-state.json") {
+					t.Errorf("Expected site %s to keep its install state in its own directory, got %s", s.site.Name, s.config.Paths.StateFile)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantSites) {
+				t.Errorf("Expected sites %v, got %v", tt.wantSites, got)
+			}
+		})
+	}
+}
+
+func TestFailedStep(t *testing.T) {
+	a := newTestApp(t)
+	path := a.installStatePath()
+	if step := failedStep(path); step != "" {
+		t.Errorf("Expected no failed step without a state file, got %s", step)
+	}
+
+	state := newInstallState()
+	state.Steps["connect"] = &stepRecord{Status: stepCompleted}
+	state.Steps["image"] = &stepRecord{Status: stepCompleted}
+	if err := state.save(path, a.runner); err != nil {
+		t.Fatalf("Failed to write install state: %v", err)
+	}
+	if step := failedStep(path); step != "" {
+		t.Errorf("Expected no failed step, got %s", step)
+	}
+
+	state.Steps["boot"] = &stepRecord{Status: stepFailed}
+	if err := state.save(path, a.runner); err != nil {
+		t.Fatalf("Failed to write install state: %v", err)
+	}
+	if step := failedStep(path); step != "boot" {
+		t.Errorf("Expected failed step boot, got %q", step)
+	}
+}
+
+func TestReportFleet(t *testing.T) {
+	a := newTestApp(t)
+
+	succeeded := []siteResult{
+		{Name: "edge-01", Status: siteSucceeded},
+		{Name: "edge-02", Status: siteSucceeded},
+	}
+	if err := a.reportFleet(succeeded); err != nil {
+		t.Errorf("Expected no error when every site succeeded, got %v", err)
+	}
+
+	results := []siteResult{
+		{Name: "edge-01", Status: siteSucceeded},
+		{Name: "edge-02", Status: siteFailed, FailedStep: "boot", Error: "boot failed"},
+		{Name: "edge-03", Status: siteFailed, Error: "not started: context canceled"},
+	}
+	err := a.reportFleet(results)
+	if err == nil {
+		t.Fatal("Expected an error when a site failed")
+	}
+	want := "2 of 3 sites failed: edge-02, edge-03; fix them and rerun with --sites edge-02,edge-03"
+	if err.Error() != want {
+		t.Errorf("Expected error %q, got %q", want, err)
+	}
+}
+
+// loadTestFleet writes a base configuration and an inventory with a site for
+// every name:bmc[:cluster_name] entry to a temporary directory and loads it.
+// The install-config.yaml of every site names the cluster after the site.
+func loadTestFleet(t *testing.T, sites []string) *config.Fleet {
+	t.Helper()
+
+	dir := t.TempDir()
+	base := config.DefaultConfig()
+	base.IDRAC.Password = "calvin"
+	basePath := filepath.Join(dir, "base.yaml")
+	if err := base.Save(basePath); err != nil {
+		t.Fatal(err)
+	}
+
+	var inventory strings.Builder
+	inventory.WriteString("base_config: " + basePath + "\n")
+	inventory.WriteString("dir: " + filepath.Join(dir, "fleet") + "\n")
+	inventory.WriteString("sites:\n")
+	for _, s := range sites {
+		fields := strings.Split(s, ":")
+		name, bmc := fields[0], fields[1]
+		sourceDir := filepath.Join(dir, "abi-"+name)
+		if err := os.MkdirAll(sourceDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		installConfig := "metadata:\n  name: " + name + "\n"
+		if err := os.WriteFile(filepath.Join(sourceDir, "install-config.yaml"), []byte(installConfig), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		inventory.WriteString("  - name: " + name + "\n")
+		if len(fields) > 2 {
+			inventory.WriteString("    cluster_name: " + fields[2] + "\n")
+		}
+		inventory.WriteString("    source_dir: " + sourceDir + "\n")
+		inventory.WriteString("    iso_url: http://10.0.0.5/" + name + "/agent.x86_64.iso\n")
+		inventory.WriteString("    idrac:\n      ip: " + bmc + "\n")
+	}
+
+	path := filepath.Join(dir, config.DefaultFleetFile)
+	if err := os.WriteFile(path, []byte(inventory.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fleet, err := config.LoadFleet(path)
+	if err != nil {
+		t.Fatalf("Failed to load fleet: %v", err)
+	}
+	return fleet
+}
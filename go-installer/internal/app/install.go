@@ -0,0 +1,458 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"openshift-sno-hub-installer/internal/runner"
+)
+
+// installStateVersion is the format version of the install state file
+const installStateVersion = 1
+
+// defaultInstallStateFile is used when paths.state_file is not set
+const defaultInstallStateFile = "install-state.json"
+
+// Step outcomes recorded in the install state file
+const (
+	stepCompleted = "completed"
+	stepFailed    = "failed"
+	stepSkipped   = "skipped"
+)
+
+// installStep is a named step of the install pipeline
+type installStep struct {
+	Name        string
+	Description string
+	// Enabled reports whether the configuration asks for the step; nil
+	// means the step always runs
+	Enabled func() bool
+	// Always marks steps that set up the session, such as the connectivity
+	// check and the event subscription, which run again on resume
+	Always bool
+	Run    func(ctx context.Context) error
+}
+
+// installState is the progress of an install, persisted after every step
+type installState struct {
+	Version   int                    `json:"version"`
+	StartedAt time.Time              `json:"started_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+	Steps     map[string]*stepRecord `json:"steps"`
+}
+
+// stepRecord is the outcome of the last run of a step
+type stepRecord struct {
+	Status     string    `json:"status"`
+	StartedAt  time.Time `json:"started_at,omitempty"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// installSteps returns the install pipeline in order
+func (a *EnhancedApp) installSteps() []installStep {
+	return []installStep{
+		{
+			Name:        "connect",
+			Description: "Check BMC connectivity, subscribe to events, read system info and health",
+			Always:      true,
+			Run:         a.connectStep,
+		},
+		{
+			Name:        "storage",
+			Description: "Build the boot volume (storage.prepare)",
+			Enabled:     func() bool { return a.config.Storage.Prepare },
+			Run: func(ctx context.Context) error {
+				if err := a.prepareStorage(ctx); err != nil {
+					return fmt.Errorf("failed to prepare boot storage: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "preflight",
+			Description: "Validate agent-config.yaml and install-config.yaml against the hardware",
+			Run:         a.runPreflight,
+		},
+		{
+			Name:        "firmware",
+			Description: "Update firmware to the baseline (firmware.enforce)",
+			Enabled:     func() bool { return a.config.Firmware.Enforce },
+			Run: func(ctx context.Context) error {
+				if err := a.updateFirmware(ctx, a.config.Firmware.Manifest); err != nil {
+					return fmt.Errorf("failed to update firmware: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "ssh-key",
+			Description: "Check and set up the SSH key",
+			Run: func(ctx context.Context) error {
+				if err := a.sshManager.CheckSSHKey(ctx); err != nil {
+					return fmt.Errorf("failed to check SSH key: %w", err)
+				}
+				if err := a.sshManager.SetupSSHKey(ctx); err != nil {
+					return fmt.Errorf("failed to setup SSH key: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "extract-installer",
+			Description: "Extract openshift-install from the release",
+			Run: func(ctx context.Context) error {
+				if err := a.installer.ExtractInstaller(ctx); err != nil {
+					return fmt.Errorf("failed to extract installer: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "workdir",
+			Description: "Prepare the work directory, keeping a previous one aside",
+			Run: func(ctx context.Context) error {
+				if err := a.installer.PrepareWorkDir(ctx); err != nil {
+					return fmt.Errorf("failed to prepare work directory: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "image",
+			Description: "Create the agent ISO",
+			Run: func(ctx context.Context) error {
+				if err := a.installer.CreateAgentImage(ctx); err != nil {
+					return fmt.Errorf("failed to create agent image: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "upload",
+			Description: "Copy the ISO to the remote web server",
+			Run: func(ctx context.Context) error {
+				if err := a.sshManager.CopyISOToRemote(ctx, a.installer.GetISOFilePath()); err != nil {
+					return fmt.Errorf("failed to copy ISO to remote: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "power-policy",
+			Description: "Apply the power cap and PSU redundancy policy",
+			Run: func(ctx context.Context) error {
+				if err := a.applyPowerPolicy(ctx); err != nil {
+					return fmt.Errorf("failed to apply power policy: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "bios",
+			Description: "Enforce the BIOS profile and system profile (bios.enforce, bios.system_profile)",
+			Enabled:     func() bool { return a.config.BIOS.Enforce || a.config.BIOS.SystemProfile != "" },
+			Run: func(ctx context.Context) error {
+				if err := a.enforceBIOSProfile(ctx); err != nil {
+					return fmt.Errorf("failed to enforce BIOS profile: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "boot-order",
+			Description: "Put the installation disk first in the boot order (boot.disk_first)",
+			Enabled:     func() bool { return a.config.Boot.DiskFirst },
+			Run: func(ctx context.Context) error {
+				// The pending change is applied by the reset that boots the ISO
+				if _, err := a.setDiskFirst(ctx); err != nil {
+					return fmt.Errorf("failed to set disk-first boot order: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "boot",
+			Description: "Insert the ISO as virtual media and boot it",
+			Run: func(ctx context.Context) error {
+				if err := a.manageVirtualMediaBootProcess(ctx, a.config.Remote.ISOURL); err != nil {
+					return fmt.Errorf("failed to manage virtual media boot process: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "monitor",
+			Description: "Wait for the installation to complete",
+			Run: func(ctx context.Context) error {
+				if err := a.monitorInstallation(ctx); err != nil {
+					return fmt.Errorf("failed to monitor installation: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "cleanup",
+			Description: "Eject virtual media and restore the boot device",
+			Run: func(ctx context.Context) error {
+				if err := a.cleanup(ctx, false); err != nil {
+					a.logger.LogWarn("Cleanup failed: %v", err)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// connectStep checks the BMC, subscribes to its events and logs the system
+// information and health
+func (a *EnhancedApp) connectStep(ctx context.Context) error {
+	a.logger.LogInfo("Using the %s BMC driver", a.bmc.Vendor())
+	if err := a.bmc.CheckConnectivity(ctx); err != nil {
+		return fmt.Errorf("BMC connectivity check failed: %w", err)
+	}
+
+	// Receive BMC events so power and virtual media waits react as changes
+	// happen; a dry run does not wait, so it needs no events
+	if a.config.Events.Enabled && a.stopEvents == nil && !a.runner.DryRun() {
+		if err := a.startEvents(ctx); err != nil {
+			a.logger.LogWarn("Failed to subscribe to BMC events, polling only: %v", err)
+		}
+	}
+
+	if _, err := a.bmc.GetSystemInfo(ctx); err != nil {
+		a.logger.LogWarn("Failed to get system info: %v", err)
+	}
+	if _, err := a.bmc.GetSystemHealth(ctx); err != nil {
+		a.logger.LogWarn("Failed to get system health: %v", err)
+	}
+	return nil
+}
+
+// runInstall runs the install pipeline
+func (a *EnhancedApp) runInstall(ctx context.Context, args []string) error {
+	return a.runInstallSteps(ctx, a.installSteps(), args)
+}
+
+// runInstallSteps runs steps as the install pipeline. Progress is recorded in
+// the install state file after every step, so --resume continues after the
+// last completed step and --from-step reruns the pipeline from a given step on.
+func (a *EnhancedApp) runInstallSteps(ctx context.Context, steps []installStep, args []string) (err error) {
+	fs := flag.NewFlagSet("install", flag.ContinueOnError)
+	resume := fs.Bool("resume", false, "skip the steps a previous install completed")
+	fromStep := fs.String("from-step", "", "rerun the install from this step on, e.g. boot")
+	listSteps := fs.Bool("list-steps", false, "list the install steps and their state")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	path := a.installStatePath()
+	state, err := loadInstallState(path)
+	if err != nil {
+		return err
+	}
+
+	if *listSteps {
+		a.listInstallSteps(steps, state)
+		return nil
+	}
+	if *resume && *fromStep != "" {
+		return usagef("--resume and --from-step are mutually exclusive")
+	}
+
+	start := 0
+	if *fromStep != "" {
+		if start = stepIndex(steps, *fromStep); start < 0 {
+			return usagef("unknown install step %q, expected one of %s", *fromStep, stepNames(steps))
+		}
+	}
+
+	switch {
+	case *resume && state == nil:
+		return fmt.Errorf("no install state in %s to resume, run install without --resume", path)
+	case *resume:
+	case *fromStep != "":
+		if state == nil {
+			state = newInstallState()
+		}
+		for _, step := range steps[:start] {
+			if record := state.Steps[step.Name]; !step.Always && (record == nil || record.Status == stepFailed) {
+				a.logger.LogWarn("Step %s has not completed, the install may miss its result", step.Name)
+			}
+		}
+	default:
+		if state != nil && !state.finished(steps) {
+			a.logger.LogWarn("A previous install stopped before completing (%s); starting over, its artifacts are kept. Use --resume to continue it.", path)
+		}
+		state = newInstallState()
+	}
+
+	if err := a.confirm("Install OpenShift on %s, booting it from %s", a.config.IDRAC.IP, a.config.Remote.ISOURL); err != nil {
+		return err
+	}
+
+	a.logger.LogInfo("Starting OpenShift SNO Hub Installation...")
+
+	// Keep the SEL and Lifecycle Controller log of a failed run for
+	// post-mortem, also when the run failed because ctx was cancelled
+	defer func() {
+		if err != nil {
+			dumpCtx, cancel := context.WithTimeout(context.Background(), failureDumpTimeout)
+			defer cancel()
+			a.dumpBMCLogs(dumpCtx)
+		}
+	}()
+
+	for i, step := range steps {
+		record := state.Steps[step.Name]
+		if !step.Always {
+			if i < start {
+				a.logger.LogInfo("Skipping step %s (before --from-step %s)", step.Name, *fromStep)
+				continue
+			}
+			if *resume && record != nil && record.Status == stepCompleted {
+				a.logger.LogInfo("Skipping step %s, completed %s", step.Name, record.FinishedAt.Format(time.RFC3339))
+				continue
+			}
+		}
+		if step.Enabled != nil && !step.Enabled() {
+			state.Steps[step.Name] = &stepRecord{Status: stepSkipped}
+			continue
+		}
+
+		a.logger.LogInfo("Step %d/%d %s: %s", i+1, len(steps), step.Name, step.Description)
+		record = &stepRecord{StartedAt: time.Now()}
+		state.Steps[step.Name] = record
+		stepErr := step.Run(ctx)
+		record.FinishedAt = time.Now()
+
+		if stepErr != nil {
+			record.Status, record.Error = stepFailed, stepErr.Error()
+			if err := state.save(path, a.runner); err != nil {
+				a.logger.LogWarn("Failed to record install state: %v", err)
+			}
+			a.logger.LogError("Step %s failed; fix the cause and run 'install --resume'", step.Name)
+			return stepErr
+		}
+
+		record.Status = stepCompleted
+		if err := state.save(path, a.runner); err != nil {
+			return err
+		}
+	}
+
+	a.logger.LogSuccess("OpenShift SNO Hub installation completed successfully!")
+	return nil
+}
+
+// installStatePath returns the install state file
+func (a *EnhancedApp) installStatePath() string {
+	return valueOr(a.config.Paths.StateFile, defaultInstallStateFile)
+}
+
+// listInstallSteps logs the install steps with their recorded outcome
+func (a *EnhancedApp) listInstallSteps(steps []installStep, state *installState) {
+	a.logger.LogInfo("Install steps:")
+	for _, step := range steps {
+		status := "pending"
+		if step.Enabled != nil && !step.Enabled() {
+			status = "disabled"
+		}
+		if state != nil {
+			if record := state.Steps[step.Name]; record != nil {
+				status = record.Status
+			}
+		}
+		a.logger.LogInfo("  %-18s %-10s %s", step.Name, status, step.Description)
+	}
+}
+
+// stepIndex returns the position of the step named name, or -1
+func stepIndex(steps []installStep, name string) int {
+	for i, step := range steps {
+		if step.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// stepNames returns the step names as a comma-separated list
+func stepNames(steps []installStep) string {
+	names := make([]string, 0, len(steps))
+	for _, step := range steps {
+		names = append(names, step.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// newInstallState returns the state of an install that has not run a step yet
+func newInstallState() *installState {
+	return &installState{
+		Version:   installStateVersion,
+		StartedAt: time.Now(),
+		Steps:     make(map[string]*stepRecord),
+	}
+}
+
+// loadInstallState reads the install state file; it returns nil when there is none
+func loadInstallState(path string) (*installState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read install state: %w", err)
+	}
+
+	var state installState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse install state %s: %w", path, err)
+	}
+	if state.Version != installStateVersion {
+		return nil, fmt.Errorf("install state %s has version %d, expected %d", path, state.Version, installStateVersion)
+	}
+	if state.Steps == nil {
+		state.Steps = make(map[string]*stepRecord)
+	}
+	return &state, nil
+}
+
+// finished reports whether every step completed or was skipped
+func (s *installState) finished(steps []installStep) bool {
+	for _, step := range steps {
+		record := s.Steps[step.Name]
+		if record == nil || (record.Status != stepCompleted && record.Status != stepSkipped) {
+			return false
+		}
+	}
+	return true
+}
+
+// save writes the state through a temporary file, so an interrupted write
+// leaves the previous state intact
+func (s *installState) save(path string, run *runner.Runner) error {
+	s.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal install state: %w", err)
+	}
+
+	if err := run.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create install state directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := run.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write install state: %w", err)
+	}
+	if err := run.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write install state: %w", err)
+	}
+	return nil
+}
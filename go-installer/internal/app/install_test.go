@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"openshift-sno-hub-installer/internal/config"
+	"openshift-sno-hub-installer/internal/logger"
+)
+
+func TestRunInstallSteps(t *testing.T) {
+	tests := []struct {
+		name string
+		// state is the outcome of each step in the state file before the
+		// run; nil means there is no state file
+		state   map[string]string
+		args    []string
+		failAt  string
+		wantRun []string
+		wantErr bool
+		usage   bool
+		// wantState is the state file after the run; nil means none
+		wantState map[string]string
+	}{
+		{
+			name:      "fresh install",
+			wantRun:   []string{"connect", "image", "boot"},
+			wantState: map[string]string{"connect": stepCompleted, "image": stepCompleted, "bios": stepSkipped, "boot": stepCompleted},
+		},
+		{
+			name:      "failed step is recorded",
+			failAt:    "boot",
+			wantRun:   []string{"connect", "image", "boot"},
+			wantErr:   true,
+			wantState: map[string]string{"connect": stepCompleted, "image": stepCompleted, "bios": stepSkipped, "boot": stepFailed},
+		},
+		{
+			name:      "resume skips completed steps",
+			state:     map[string]string{"connect": stepCompleted, "image": stepCompleted, "bios": stepSkipped, "boot": stepFailed},
+			args:      []string{"--resume"},
+			wantRun:   []string{"connect", "boot"},
+			wantState: map[string]string{"connect": stepCompleted, "image": stepCompleted, "bios": stepSkipped, "boot": stepCompleted},
+		},
+		{
+			name:    "resume without state",
+			args:    []string{"--resume"},
+			wantErr: true,
+		},
+		{
+			name:      "from step reruns later steps",
+			state:     map[string]string{"connect": stepCompleted, "image": stepCompleted, "bios": stepSkipped, "boot": stepCompleted},
+			args:      []string{"--from-step", "image"},
+			wantRun:   []string{"connect", "image", "boot"},
+			wantState: map[string]string{"connect": stepCompleted, "image": stepCompleted, "bios": stepSkipped, "boot": stepCompleted},
+		},
+		{
+			name:      "without resume an unfinished install starts over",
+			state:     map[string]string{"connect": stepCompleted, "image": stepFailed},
+			wantRun:   []string{"connect", "image", "boot"},
+			wantState: map[string]string{"connect": stepCompleted, "image": stepCompleted, "bios": stepSkipped, "boot": stepCompleted},
+		},
+		{
+			name:    "unknown from step",
+			args:    []string{"--from-step", "monitor"},
+			wantErr: true,
+			usage:   true,
+		},
+		{
+			name:      "resume and from step are exclusive",
+			state:     map[string]string{"connect": stepCompleted},
+			args:      []string{"--resume", "--from-step", "boot"},
+			wantErr:   true,
+			usage:     true,
+			wantState: map[string]string{"connect": stepCompleted},
+		},
+		{
+			name:      "list steps runs nothing",
+			state:     map[string]string{"connect": stepCompleted, "image": stepFailed},
+			args:      []string{"--list-steps"},
+			wantState: map[string]string{"connect": stepCompleted, "image": stepFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t)
+			path := a.installStatePath()
+			if tt.state != nil {
+				state := newInstallState()
+				for name, status := range tt.state {
+					state.Steps[name] = &stepRecord{Status: status}
+				}
+				if err := state.save(path, a.runner); err != nil {
+					t.Fatalf("Failed to write install state: %v", err)
+				}
+			}
+
+			var run []string
+			step := func(name string) func(context.Context) error {
+				return func(context.Context) error {
+					run = append(run, name)
+					if name == tt.failAt {
+						return fmt.Errorf("%s failed", name)
+					}
+					return nil
+				}
+			}
+			steps := []installStep{
+				{Name: "connect", Always: true, Run: step("connect")},
+				{Name: "image", Run: step("image")},
+				{Name: "bios", Enabled: func() bool { return false }, Run: step("bios")},
+				{Name: "boot", Run: step("boot")},
+			}
+
+			err := a.runInstallSteps(context.Background(), steps, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			var usage *usageError
+			if errors.As(err, &usage) != tt.usage {
+				t.Errorf("Expected usage error %v, got %v", tt.usage, err)
+			}
+			if !reflect.DeepEqual(run, tt.wantRun) {
+				t.Errorf("Expected steps %v to run, got %v", tt.wantRun, run)
+			}
+
+			state, err := loadInstallState(path)
+			if err != nil {
+				t.Fatalf("Failed to load install state: %v", err)
+			}
+			var got map[string]string
+			if state != nil {
+				got = make(map[string]string)
+				for name, record := range state.Steps {
+					got[name] = record.Status
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantState) {
+				t.Errorf("Expected install state %v, got %v", tt.wantState, got)
+			}
+		})
+	}
+}
+
+func TestLoadInstallState(t *testing.T) {
+	dir := t.TempDir()
+	if state, err := loadInstallState(filepath.Join(dir, "missing.json")); state != nil || err != nil {
+		t.Errorf("Expected no state for a missing file, got %v, %v", state, err)
+	}
+
+	path := filepath.Join(dir, "install-state.json")
+	if err := os.WriteFile(path, []byte(`{"version": 2, "steps": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadInstallState(path); err == nil {
+		t.Error("Expected a state file of another version to be refused")
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state, err := loadInstallState(path)
+	if err != nil || state.Steps == nil {
+		t.Errorf("Expected a state without steps to load, got %+v, %v", state, err)
+	}
+}
+
+// newTestApp returns an app without a BMC that keeps its logs and install
+// state in a temporary directory and needs no confirmation
+func newTestApp(t *testing.T) *EnhancedApp {
+	t.Helper()
+
+	dir := t.TempDir()
+	log := logger.NewFileLogger(filepath.Join(dir, "logs"))
+	t.Cleanup(func() { log.Close() })
+
+	cfg := config.DefaultConfig()
+	cfg.Paths.WorkDir = filepath.Join(dir, "workdir")
+	cfg.Paths.StateFile = filepath.Join(dir, "install-state.json")
+	return newApp(cfg, log, &options{Output: "text", Yes: true})
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// inventory collects the hardware inventory and writes it as a JSON or YAML
+// report file, or as an Inventory document to stdout with --output json|yaml
+func (a *EnhancedApp) inventory(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("inventory", flag.ContinueOnError)
+	format := fs.String("format", "json", "report format, json or yaml")
+	file := fs.String("file", "", "report file (default: inventory.<format>, or stdout with --output json|yaml)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	inv, err := a.bmc.Redfish().GetInventory(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to collect inventory: %w", err)
+	}
+	if a.documentOutput() && *file == "" {
+		return a.writeDocument(kindInventory, inv)
+	}
+
+	var data []byte
+	switch *format {
+	case "json":
+		data, err = json.MarshalIndent(inv, "", "  ")
+	case "yaml":
+		data, err = yaml.Marshal(inv)
+	default:
+		return usagef("unsupported inventory format %q (expected json or yaml)", *format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal inventory: %w", err)
+	}
+
+	path := valueOr(*file, "inventory."+*format)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write inventory report: %w", err)
+	}
+
+	a.logger.LogSuccess("Inventory report written to %s", path)
+	return nil
+}
@@ -0,0 +1,163 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"openshift-sno-hub-installer/internal/bmc"
+	"openshift-sno-hub-installer/internal/idrac"
+)
+
+// failureDumpLimit bounds the entries of each log dumped after a failed install
+const failureDumpLimit = 500
+
+// failureDumpTimeout bounds reading the BMC logs after a failed install
+const failureDumpTimeout = 2 * time.Minute
+
+// logServices maps the logs subcommands to the Redfish log service IDs
+var logServices = map[string]string{
+	"sel": idrac.LogServiceSEL,
+	"lc":  idrac.LogServiceLC,
+}
+
+// logs reads the System Event Log (sel) or the Lifecycle Controller log (lc),
+// filtered by time, severity and message ID
+func (a *EnhancedApp) logs(ctx context.Context, args []string) error {
+	if len(args) == 0 || logServices[args[0]] == "" {
+		return usagef("please choose the log to read: sel or lc")
+	}
+	name, args := args[0], args[1:]
+	if name == "lc" {
+		if err := a.requireDell("logs lc"); err != nil {
+			return err
+		}
+	}
+
+	fs := flag.NewFlagSet("logs "+name, flag.ContinueOnError)
+	since := fs.String("since", "", "only entries created after this time (RFC3339, or a duration such as 2h)")
+	until := fs.String("until", "", "only entries created before this time (RFC3339, or a duration such as 30m)")
+	severity := fs.String("severity", "", "comma-separated severities to keep, e.g. Warning,Critical")
+	messageID := fs.String("message-id", "", "comma-separated message IDs to keep, e.g. PR7,SYS1003")
+	limit := fs.Int("limit", 0, "stop after this many matching entries (default: all)")
+	file := fs.String("file", "", "also write the entries to this JSON file")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	filter := idrac.LogFilter{
+		Severities: splitList(*severity),
+		MessageIDs: splitList(*messageID),
+		Limit:      *limit,
+	}
+	var err error
+	if filter.Since, err = parseLogTime(*since, now); err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	if filter.Until, err = parseLogTime(*until, now); err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+
+	entries, err := a.bmc.Redfish().GetLogEntries(ctx, logServices[name], filter)
+	if err != nil {
+		return err
+	}
+
+	a.logger.LogInfo("%d %s entries:", len(entries), logServices[name])
+	for _, entry := range entries {
+		a.logEntry(entry)
+	}
+
+	if *file != "" {
+		return a.writeLogEntries(*file, entries)
+	}
+	return nil
+}
+
+// logEntry logs a BMC log entry at the level matching its severity
+func (a *EnhancedApp) logEntry(entry idrac.LogEntry) {
+	line := fmt.Sprintf("  %s [%s] %s: %s", entry.Created, entry.Severity, valueOr(entry.MessageID, entry.ID), entry.Message)
+	switch strings.ToLower(entry.Severity) {
+	case "critical":
+		a.logger.LogError("%s", line)
+	case "warning":
+		a.logger.LogWarn("%s", line)
+	default:
+		a.logger.LogInfo("%s", line)
+	}
+}
+
+// writeLogEntries writes log entries to a JSON file
+func (a *EnhancedApp) writeLogEntries(path string, entries []idrac.LogEntry) error {
+	if entries == nil {
+		entries = []idrac.LogEntry{}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal log entries: %w", err)
+	}
+	if err := a.runner.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write log entries: %w", err)
+	}
+
+	a.logger.LogInfo("Log entries written to %s", path)
+	return nil
+}
+
+// dumpBMCLogs saves the most recent SEL and Lifecycle Controller entries to a
+// timestamped directory next to the installer log, for post-mortem of a failed run
+func (a *EnhancedApp) dumpBMCLogs(ctx context.Context) {
+	if a.bmc == nil {
+		return
+	}
+
+	dir := filepath.Join(a.logger.Dir(), "bmc-logs-"+time.Now().Format("20060102-150405"))
+	if err := a.runner.MkdirAll(dir, 0755); err != nil {
+		a.logger.LogWarn("Failed to create BMC log directory: %v", err)
+		return
+	}
+
+	a.logger.LogInfo("Saving BMC logs to %s...", dir)
+	names := []string{"sel"}
+	if a.bmc.Vendor() == bmc.VendorDell {
+		names = append(names, "lc")
+	}
+	for _, name := range names {
+		entries, err := a.bmc.Redfish().GetLogEntries(ctx, logServices[name], idrac.LogFilter{Limit: failureDumpLimit})
+		if err != nil {
+			a.logger.LogWarn("Failed to read %s: %v", logServices[name], err)
+			continue
+		}
+		if err := a.writeLogEntries(filepath.Join(dir, name+".json"), entries); err != nil {
+			a.logger.LogWarn("%v", err)
+		}
+	}
+}
+
+// parseLogTime parses an RFC3339 time, or a duration counted back from now.
+// An empty value yields the zero time.
+func parseLogTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// splitList splits a comma-separated list, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"openshift-sno-hub-installer/internal/idrac"
+	"openshift-sno-hub-installer/internal/metrics"
+)
+
+// serveMetrics polls the chassis thermal, power and sensor readings and serves
+// them as Prometheus metrics until interrupted
+func (a *EnhancedApp) serveMetrics(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("metrics", flag.ContinueOnError)
+	listen := fs.String("listen", valueOr(a.config.Metrics.Listen, ":9610"), "address of the Prometheus endpoint")
+	interval := fs.Duration("interval", secondsOr(a.config.Metrics.Interval, 30), "telemetry poll interval")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	poller := metrics.NewPoller(a.bmc.Redfish(), a.config.IDRAC.IP, *interval, a.logger)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", poller)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	ln, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", *listen, err)
+	}
+	go func() {
+		if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.logger.LogError("Metrics endpoint failed: %v", err)
+		}
+	}()
+
+	a.logger.LogInfo("Serving BMC metrics on http://%s/metrics, polling every %s", ln.Addr(), *interval)
+	poller.Run(ctx, nil)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// trend tracks the range of a telemetry reading
+type trend struct {
+	first, last, min, max float64
+	samples               int
+}
+
+// add records a reading
+func (t *trend) add(value float64) {
+	if t.samples == 0 {
+		t.first, t.min, t.max = value, value, value
+	}
+	t.last = value
+	if value < t.min {
+		t.min = value
+	}
+	if value > t.max {
+		t.max = value
+	}
+	t.samples++
+}
+
+// summary describes the range of the readings
+func (t *trend) summary(unit string) string {
+	return fmt.Sprintf("%.0f%s (min %.0f, max %.0f, %+.0f since start)", t.last, unit, t.min, t.max, t.last-t.first)
+}
+
+// startTrends logs the inlet temperature, power draw and fan speed every
+// metrics.trend_interval. The returned function stops logging and logs the
+// range seen since the start.
+func (a *EnhancedApp) startTrends(ctx context.Context) func() {
+	var inlet, power, fans trend
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	interval := secondsOr(a.config.Metrics.TrendInterval, 300)
+	poller := metrics.NewPoller(a.bmc.Redfish(), a.config.IDRAC.IP, interval, a.logger)
+	go func() {
+		defer close(done)
+		poller.Run(ctx, func(t *idrac.Telemetry) {
+			var readings []string
+			if value, ok := t.InletTemperature(); ok {
+				inlet.add(value)
+				readings = append(readings, fmt.Sprintf("inlet %.0f°C", value))
+			}
+			if value, ok := t.PowerConsumed(); ok {
+				power.add(value)
+				readings = append(readings, fmt.Sprintf("power %.0f W", value))
+			}
+			if value, ok := t.MaxFanSpeed(); ok {
+				fans.add(value)
+				readings = append(readings, fmt.Sprintf("fans up to %.0f RPM", value))
+			}
+			if len(readings) > 0 {
+				a.logger.LogInfo("Telemetry: %s", strings.Join(readings, ", "))
+			}
+		})
+	}()
+
+	return func() {
+		cancel()
+		<-done
+
+		if inlet.samples > 0 {
+			a.logger.LogInfo("Inlet temperature during install: %s", inlet.summary("°C"))
+		}
+		if power.samples > 0 {
+			a.logger.LogInfo("Power draw during install: %s", power.summary(" W"))
+		}
+		if fans.samples > 0 {
+			a.logger.LogInfo("Fastest fan during install: %s", fans.summary(" RPM"))
+		}
+	}
+}
+
+// secondsOr converts seconds to a duration, using fallback seconds when not positive
+func secondsOr(seconds, fallback int) time.Duration {
+	if seconds <= 0 {
+		seconds = fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,220 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"openshift-sno-hub-installer/internal/idrac"
+)
+
+// documentAPIVersion identifies the schema of the documents written with
+// --output json|yaml. Fields are only added within a version; renaming or
+// removing one bumps it.
+const documentAPIVersion = "openshift-sno-hub-installer/v1"
+
+// Kinds of the documents written with --output json|yaml
+const (
+	kindSystemStatus = "SystemStatus"
+	kindSystemInfo   = "SystemInfo"
+	kindInventory    = "Inventory"
+	kindVirtualMedia = "VirtualMedia"
+	kindManagerInfo  = "ManagerInfo"
+	kindFleetSummary = "FleetSummary"
+)
+
+// document is the envelope of a command result written with --output json|yaml
+type document struct {
+	APIVersion  string      `json:"apiVersion" yaml:"apiVersion"`
+	Kind        string      `json:"kind" yaml:"kind"`
+	BMC         string      `json:"bmc" yaml:"bmc"`
+	GeneratedAt time.Time   `json:"generatedAt" yaml:"generatedAt"`
+	Data        interface{} `json:"data" yaml:"data"`
+}
+
+// systemStatus is the data of a SystemStatus document
+type systemStatus struct {
+	PowerState string       `json:"powerState" yaml:"powerState"`
+	Health     string       `json:"health" yaml:"health"`
+	Power      *powerStatus `json:"power,omitempty" yaml:"power,omitempty"`
+}
+
+// powerStatus is the power cap, consumption and PSU redundancy of a system
+type powerStatus struct {
+	// CapWatts is null when power capping is disabled
+	CapWatts      *float64 `json:"capWatts" yaml:"capWatts"`
+	CapException  string   `json:"capException,omitempty" yaml:"capException,omitempty"`
+	ConsumedWatts *float64 `json:"consumedWatts" yaml:"consumedWatts"`
+	CapacityWatts *float64 `json:"capacityWatts,omitempty" yaml:"capacityWatts,omitempty"`
+	PSURedundancy string   `json:"psuRedundancy,omitempty" yaml:"psuRedundancy,omitempty"`
+	SystemProfile string   `json:"systemProfile,omitempty" yaml:"systemProfile,omitempty"`
+}
+
+// systemInfo is the data of a SystemInfo document
+type systemInfo struct {
+	System  systemSummary `json:"system" yaml:"system"`
+	Manager managerInfo   `json:"manager" yaml:"manager"`
+}
+
+// systemSummary describes the computer system
+type systemSummary struct {
+	Manufacturer string `json:"manufacturer" yaml:"manufacturer"`
+	Model        string `json:"model" yaml:"model"`
+	SerialNumber string `json:"serialNumber" yaml:"serialNumber"`
+	BiosVersion  string `json:"biosVersion" yaml:"biosVersion"`
+	PowerState   string `json:"powerState" yaml:"powerState"`
+	Health       string `json:"health" yaml:"health"`
+}
+
+// managerInfo is the data of a ManagerInfo document: the BMC manager, on
+// iDRAC the lifecycle controller
+type managerInfo struct {
+	ID              string `json:"id" yaml:"id"`
+	Name            string `json:"name" yaml:"name"`
+	FirmwareVersion string `json:"firmwareVersion" yaml:"firmwareVersion"`
+	Health          string `json:"health" yaml:"health"`
+	State           string `json:"state" yaml:"state"`
+}
+
+// virtualMedia is the data of a VirtualMedia document
+type virtualMedia struct {
+	Inserted     bool     `json:"inserted" yaml:"inserted"`
+	Image        string   `json:"image" yaml:"image"`
+	ImageName    string   `json:"imageName" yaml:"imageName"`
+	ConnectedVia string   `json:"connectedVia" yaml:"connectedVia"`
+	MediaTypes   []string `json:"mediaTypes" yaml:"mediaTypes"`
+}
+
+// fleetSummary is the data of a FleetSummary document
+type fleetSummary struct {
+	Succeeded int          `json:"succeeded" yaml:"succeeded"`
+	Failed    int          `json:"failed" yaml:"failed"`
+	Sites     []siteResult `json:"sites" yaml:"sites"`
+}
+
+// siteResult is the outcome of the install of a fleet site
+type siteResult struct {
+	Name        string `json:"name" yaml:"name"`
+	ClusterName string `json:"clusterName" yaml:"clusterName"`
+	BMC         string `json:"bmc" yaml:"bmc"`
+	Status      string `json:"status" yaml:"status"`
+	FailedStep  string `json:"failedStep,omitempty" yaml:"failedStep,omitempty"`
+	Error       string `json:"error,omitempty" yaml:"error,omitempty"`
+	Duration    string `json:"duration" yaml:"duration"`
+	LogDir      string `json:"logDir" yaml:"logDir"`
+}
+
+// documentOutput reports whether results are written as documents to stdout
+// instead of being logged
+func (a *EnhancedApp) documentOutput() bool {
+	return a.options.Output == "json" || a.options.Output == "yaml"
+}
+
+// writeDocument writes data as a document of the given kind to stdout, in
+// the --output format
+func (a *EnhancedApp) writeDocument(kind string, data interface{}) error {
+	out, err := marshalDocument(document{
+		APIVersion:  documentAPIVersion,
+		Kind:        kind,
+		BMC:         a.config.IDRAC.IP,
+		GeneratedAt: time.Now().UTC(),
+		Data:        data,
+	}, a.options.Output)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stdout.Write(out); err != nil {
+		return fmt.Errorf("failed to write %s document: %w", kind, err)
+	}
+	return nil
+}
+
+// marshalDocument encodes doc in format, json or yaml
+func marshalDocument(doc document, format string) ([]byte, error) {
+	var out []byte
+	var err error
+	switch format {
+	case "json":
+		out, err = json.MarshalIndent(doc, "", "  ")
+		out = append(out, '\n')
+	case "yaml":
+		out, err = yaml.Marshal(doc)
+	default:
+		return nil, fmt.Errorf("unsupported output format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s document: %w", doc.Kind, err)
+	}
+	return out, nil
+}
+
+// powerStatus reads the power policy and the BIOS system profile
+func (a *EnhancedApp) powerStatus(ctx context.Context) (*powerStatus, error) {
+	policy, err := a.bmc.Redfish().GetPowerPolicy(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get power policy: %w", err)
+	}
+
+	status := &powerStatus{
+		CapWatts:      policy.LimitWatts,
+		ConsumedWatts: policy.ConsumedWatts,
+		CapacityWatts: policy.CapacityWatts,
+		PSURedundancy: policy.PSURedundancy,
+	}
+	if policy.LimitWatts != nil {
+		status.CapException = policy.LimitException
+	}
+
+	attributes, err := a.bmc.Redfish().GetBIOSAttributes(ctx)
+	if err != nil {
+		a.logger.LogWarn("Failed to get BIOS system profile: %v", err)
+		return status, nil
+	}
+	if value, ok := attributes[idrac.SystemProfileAttribute]; ok {
+		status.SystemProfile = fmt.Sprint(value)
+	}
+	return status, nil
+}
+
+// newSystemSummary converts the system information of the BMC
+func newSystemSummary(info *idrac.SystemInfo) systemSummary {
+	return systemSummary{
+		Manufacturer: info.Manufacturer,
+		Model:        info.Model,
+		SerialNumber: info.SerialNumber,
+		BiosVersion:  info.BiosVersion,
+		PowerState:   info.PowerState,
+		Health:       valueOr(info.Health, info.Status.Health),
+	}
+}
+
+// newManagerInfo converts the manager information of the BMC
+func newManagerInfo(info *idrac.LifecycleControllerInfo) managerInfo {
+	return managerInfo{
+		ID:              info.Id,
+		Name:            info.Name,
+		FirmwareVersion: info.FirmwareVersion,
+		Health:          info.Status.Health,
+		State:           info.Status.State,
+	}
+}
+
+// newVirtualMedia converts the virtual media information of the BMC
+func newVirtualMedia(info *idrac.VirtualMediaInfo) virtualMedia {
+	mediaTypes := info.MediaTypes
+	if mediaTypes == nil {
+		mediaTypes = []string{}
+	}
+	return virtualMedia{
+		Inserted:     info.Inserted,
+		Image:        info.Image,
+		ImageName:    info.ImageName,
+		ConnectedVia: info.ConnectedVia,
+		MediaTypes:   mediaTypes,
+	}
+}
@@ -0,0 +1,186 @@
+package app
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"openshift-sno-hub-installer/internal/idrac"
+)
+
+// update rewrites the golden documents in testdata/documents
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// TestDocuments pins the apiVersion, kind and field names of the documents
+// written with --output json|yaml. A golden file may only change by gaining
+// fields; renaming or removing one needs a new documentAPIVersion.
+func TestDocuments(t *testing.T) {
+	watts := func(w float64) *float64 { return &w }
+	generatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		kind string
+		data interface{}
+	}{
+		{kindSystemStatus, systemStatus{
+			PowerState: "On",
+			Health:     "OK",
+			Power: &powerStatus{
+				CapWatts:      watts(450),
+				CapException:  "LogEventOnly",
+				ConsumedWatts: watts(312),
+				CapacityWatts: watts(1100),
+				PSURedundancy: "A/B Grid Redundant",
+				SystemProfile: "PerfOptimized",
+			},
+		}},
+		{kindSystemInfo, systemInfo{
+			System: systemSummary{
+				Manufacturer: "Dell Inc.",
+				Model:        "PowerEdge R640",
+				SerialNumber: "ABC1234",
+				BiosVersion:  "2.15.0",
+				PowerState:   "On",
+				Health:       "OK",
+			},
+			Manager: managerInfo{
+				ID:              "iDRAC.Embedded.1",
+				Name:            "Manager",
+				FirmwareVersion: "6.10.30.00",
+				Health:          "OK",
+				State:           "Enabled",
+			},
+		}},
+		{kindInventory, &idrac.Inventory{
+			CollectedAt: generatedAt,
+			BMC:         "192.168.1.228",
+			System: idrac.InventorySystem{
+				URI:          "/redfish/v1/Systems/System.Embedded.1",
+				Manufacturer: "Dell Inc.",
+				Model:        "PowerEdge R640",
+				SerialNumber: "ABC1234",
+				BiosVersion:  "2.15.0",
+				PowerState:   "On",
+				Health:       "OK",
+			},
+			Processors: []idrac.Processor{{
+				ID: "CPU.Socket.1", Socket: "CPU.Socket.1", Manufacturer: "Intel", Model: "Xeon Gold 6230",
+				TotalCores: 20, TotalThreads: 40, MaxSpeedMHz: 4000,
+				Status: idrac.ResourceStatus{State: "Enabled", Health: "OK"},
+			}},
+			Memory: []idrac.MemoryModule{{
+				ID: "DIMM.Socket.A1", Name: "DIMM A1", CapacityMiB: 32768, MemoryDeviceType: "DDR4",
+				OperatingSpeedMhz: 2933, Manufacturer: "Hynix", PartNumber: "HMA84GR7", SerialNumber: "1234",
+				Status: idrac.ResourceStatus{State: "Enabled", Health: "OK"},
+			}},
+			Storage: []idrac.Storage{{
+				ID:          "RAID.Integrated.1-1",
+				URI:         "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1",
+				Name:        "PERC H730P Mini",
+				Controllers: []idrac.StorageController{{Name: "PERC H730P Mini", Manufacturer: "DELL", Model: "PERC H730P Mini", FirmwareVersion: "25.5.9.0001"}},
+				PCIAddress:  "0000:18:00.0",
+				Drives: []idrac.Drive{{
+					ID:            "Disk.Bay.0:Enclosure.Internal.0-1:RAID.Integrated.1-1",
+					URI:           "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Drives/Disk.Bay.0",
+					Name:          "Physical Disk 0:1:0",
+					Manufacturer:  "SEAGATE",
+					Model:         "ST600MM0099",
+					SerialNumber:  "WAF0A1B2",
+					CapacityBytes: 600127266816,
+					MediaType:     "HDD",
+					Protocol:      "SAS",
+					Identifiers:   []idrac.DriveIdentifier{{DurableName: "5000C500B4C3D2E1", DurableNameFormat: "NAA"}},
+					Status:        idrac.ResourceStatus{State: "Enabled", Health: "OK"},
+					DiskByPath:    "/dev/disk/by-path/pci-0000:18:00.0-scsi-0:0:0:0",
+				}},
+				Volumes: []idrac.Volume{{
+					ID:            "Disk.Virtual.0:RAID.Integrated.1-1",
+					URI:           "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Volumes/Disk.Virtual.0",
+					Name:          "sno-boot",
+					RAIDType:      "RAID1",
+					VolumeType:    "Mirrored",
+					CapacityBytes: 599550590976,
+					Identifiers:   []idrac.DriveIdentifier{{DurableName: "6D0946606E5B4A00", DurableNameFormat: "NAA"}},
+					Status:        idrac.ResourceStatus{State: "Enabled", Health: "OK"},
+					Drives:        []string{"Disk.Bay.0:Enclosure.Internal.0-1:RAID.Integrated.1-1"},
+					DiskByPath:    "/dev/disk/by-path/pci-0000:18:00.0-scsi-0:2:0:0",
+				}},
+			}},
+			EthernetInterfaces: []idrac.EthernetInterface{{
+				ID: "NIC.Integrated.1-1-1", Name: "System Ethernet Interface", MACAddress: "E4:43:4B:00:00:01",
+				PermanentMACAddress: "E4:43:4B:00:00:01", SpeedMbps: 10000, LinkStatus: "LinkUp",
+				Status: idrac.ResourceStatus{State: "Enabled", Health: "OK"},
+			}},
+			NetworkAdapters: []idrac.NetworkAdapter{{
+				ID: "NIC.Integrated.1", Manufacturer: "Intel Corporation", Model: "X710", PartNumber: "06VDPG", SerialNumber: "MY0001",
+				DeviceFunctions: []idrac.NetworkDeviceFunction{{ID: "NIC.Integrated.1-1-1"}},
+			}},
+		}},
+		{kindVirtualMedia, virtualMedia{
+			Inserted:     true,
+			Image:        "http://192.168.1.10/iso/agent.x86_64.iso",
+			ImageName:    "agent.x86_64.iso",
+			ConnectedVia: "URI",
+			MediaTypes:   []string{"CD", "DVD"},
+		}},
+		{kindManagerInfo, managerInfo{
+			ID:              "iDRAC.Embedded.1",
+			Name:            "Manager",
+			FirmwareVersion: "6.10.30.00",
+			Health:          "OK",
+			State:           "Enabled",
+		}},
+		{kindFleetSummary, fleetSummary{
+			Succeeded: 1,
+			Failed:    1,
+			Sites: []siteResult{
+				{Name: "edge-01", ClusterName: "edge-01", BMC: "10.0.1.10", Status: siteSucceeded, Duration: "48m12s", LogDir: "fleet/edge-01/logs"},
+				{Name: "edge-02", ClusterName: "edge-02", BMC: "10.0.2.10", Status: siteFailed, FailedStep: "boot",
+					Error: "failed to manage virtual media boot process", Duration: "6m3s", LogDir: "fleet/edge-02/logs"},
+			},
+		}},
+	}
+
+	for _, tt := range tests {
+		for _, format := range []string{"json", "yaml"} {
+			t.Run(tt.kind+"."+format, func(t *testing.T) {
+				got, err := marshalDocument(document{
+					APIVersion:  documentAPIVersion,
+					Kind:        tt.kind,
+					BMC:         "192.168.1.228",
+					GeneratedAt: generatedAt,
+					Data:        tt.data,
+				}, format)
+				if err != nil {
+					t.Fatalf("marshalDocument failed: %v", err)
+				}
+
+				golden := filepath.Join("testdata", "documents", tt.kind+"."+format)
+				if *update {
+					if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+						t.Fatal(err)
+					}
+					if err := os.WriteFile(golden, got, 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatalf("Failed to read golden file, run go test -update to create it: %v", err)
+				}
+				if !bytes.Equal(got, want) {
+					t.Errorf("%s document differs from %s:\n%s", tt.kind, golden, got)
+				}
+			})
+		}
+	}
+}
+
+func TestMarshalDocumentFormat(t *testing.T) {
+	if _, err := marshalDocument(document{Kind: kindSystemStatus}, "xml"); err == nil {
+		t.Error("Expected an unsupported format to be refused")
+	}
+}
@@ -0,0 +1,180 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"openshift-sno-hub-installer/internal/idrac"
+)
+
+// power dispatches the power subcommands: show (default), cap, redundancy,
+// system-profile and apply
+func (a *EnhancedApp) power(ctx context.Context, args []string) error {
+	subcommand := "show"
+	if len(args) > 0 {
+		subcommand, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("power "+subcommand, flag.ContinueOnError)
+	exception := fs.String("exception", a.config.Power.LimitException, "action when the cap cannot be held: NoAction, HardPowerOff, LogEventOnly or Oem")
+	reboot := fs.Bool("reboot", false, "reset the system to apply a system profile change and wait for the configuration job")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	switch subcommand {
+	case "show":
+		return a.showPowerPolicy(ctx)
+	case "cap":
+		if fs.NArg() != 1 {
+			return usagef("usage: power cap [--exception action] <watts>|off")
+		}
+		var limit *float64
+		if fs.Arg(0) != "off" {
+			watts, err := strconv.ParseFloat(fs.Arg(0), 64)
+			if err != nil || watts <= 0 {
+				return usagef("invalid power cap %q, expected a positive number of watts or off", fs.Arg(0))
+			}
+			limit = &watts
+		}
+		if err := a.bmc.Redfish().SetPowerLimit(ctx, limit, *exception); err != nil {
+			return err
+		}
+		a.logger.LogSuccess("Power cap set to %s", formatWatts(limit, "off"))
+		return nil
+	case "redundancy":
+		if fs.NArg() != 1 {
+			return usagef("usage: power redundancy <policy>")
+		}
+		if err := a.bmc.Redfish().SetPSURedundancy(ctx, fs.Arg(0)); err != nil {
+			return err
+		}
+		a.logger.LogSuccess("PSU redundancy policy set to %s", fs.Arg(0))
+		return nil
+	case "system-profile":
+		if fs.NArg() != 1 {
+			return usagef("usage: power system-profile [--reboot] <name>")
+		}
+		profile := &idrac.BIOSProfile{
+			Name:       "system profile " + fs.Arg(0),
+			Attributes: map[string]interface{}{idrac.SystemProfileAttribute: idrac.SystemProfileValue(fs.Arg(0))},
+		}
+		return a.applyBIOSChanges(ctx, profile, *reboot)
+	case "apply":
+		if err := a.applyPowerPolicy(ctx); err != nil {
+			return err
+		}
+		if a.config.BIOS.SystemProfile == "" {
+			return nil
+		}
+		profile, err := a.siteBIOSProfile()
+		if err != nil {
+			return err
+		}
+		return a.applyBIOSChanges(ctx, profile, *reboot)
+	default:
+		return usagef("unknown power subcommand %q (expected show, cap, redundancy, system-profile or apply)", subcommand)
+	}
+}
+
+// showPowerPolicy logs the power cap, consumption, PSU redundancy policy and
+// BIOS system profile
+func (a *EnhancedApp) showPowerPolicy(ctx context.Context) error {
+	status, err := a.powerStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.logger.LogInfo("Power Policy:")
+	a.logger.LogInfo("  Power Cap: %s", formatWatts(status.CapWatts, "off"))
+	if status.CapException != "" {
+		a.logger.LogInfo("  Cap Exception: %s", status.CapException)
+	}
+	a.logger.LogInfo("  Power Consumed: %s", formatWatts(status.ConsumedWatts, "unknown"))
+	if status.CapacityWatts != nil {
+		a.logger.LogInfo("  Power Capacity: %s", formatWatts(status.CapacityWatts, "unknown"))
+	}
+	a.logger.LogInfo("  PSU Redundancy: %s", valueOr(status.PSURedundancy, "unknown"))
+	if status.SystemProfile != "" {
+		a.logger.LogInfo("  System Profile: %s", status.SystemProfile)
+	}
+	return nil
+}
+
+// applyPowerPolicy brings the power cap and PSU redundancy policy to the
+// configured values. Settings that already match are left alone.
+func (a *EnhancedApp) applyPowerPolicy(ctx context.Context) error {
+	cfg := a.config.Power
+	if cfg.LimitWatts == nil && cfg.PSURedundancy == "" {
+		return nil
+	}
+
+	client := a.bmc.Redfish()
+	current, err := client.GetPowerPolicy(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get power policy: %w", err)
+	}
+
+	var desired *float64
+	if cfg.LimitWatts != nil && *cfg.LimitWatts > 0 {
+		watts := float64(*cfg.LimitWatts)
+		desired = &watts
+	}
+
+	changed := false
+	if cfg.LimitWatts != nil && !powerLimitMatches(current, desired, cfg.LimitException) {
+		a.logger.LogInfo("Setting power cap: %s -> %s", formatWatts(current.LimitWatts, "off"), formatWatts(desired, "off"))
+		if err := client.SetPowerLimit(ctx, desired, cfg.LimitException); err != nil {
+			return err
+		}
+		changed = true
+	}
+	if cfg.PSURedundancy != "" && !strings.EqualFold(current.PSURedundancy, cfg.PSURedundancy) {
+		a.logger.LogInfo("Setting PSU redundancy policy: %s -> %s", valueOr(current.PSURedundancy, "unknown"), cfg.PSURedundancy)
+		if err := client.SetPSURedundancy(ctx, cfg.PSURedundancy); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	if !changed {
+		a.logger.LogSuccess("Power policy matches the configuration")
+		return nil
+	}
+
+	applied, err := client.GetPowerPolicy(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to verify power policy: %w", err)
+	}
+	if cfg.LimitWatts != nil && !powerLimitMatches(applied, desired, cfg.LimitException) {
+		a.logger.LogWarn("Power cap reads back as %s, expected %s", formatWatts(applied.LimitWatts, "off"), formatWatts(desired, "off"))
+	}
+	if cfg.PSURedundancy != "" && !strings.EqualFold(applied.PSURedundancy, cfg.PSURedundancy) {
+		a.logger.LogWarn("PSU redundancy policy reads back as %s, expected %s", valueOr(applied.PSURedundancy, "unknown"), cfg.PSURedundancy)
+	}
+	a.logger.LogSuccess("Power policy applied")
+	return nil
+}
+
+// powerLimitMatches reports whether the power cap of policy is desired, a nil
+// desired limit meaning capping is disabled. An empty exception matches any.
+func powerLimitMatches(policy *idrac.PowerPolicy, desired *float64, exception string) bool {
+	if (policy.LimitWatts == nil) != (desired == nil) {
+		return false
+	}
+	if desired == nil {
+		return true
+	}
+	return *policy.LimitWatts == *desired && (exception == "" || policy.LimitException == exception)
+}
+
+// formatWatts formats a power reading, using fallback when it is nil
+func formatWatts(watts *float64, fallback string) string {
+	if watts == nil {
+		return fallback
+	}
+	return fmt.Sprintf("%.0f W", *watts)
+}
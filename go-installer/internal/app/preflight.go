@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"openshift-sno-hub-installer/internal/openshift"
+	"openshift-sno-hub-installer/internal/preflight"
+)
+
+// runPreflight cross-checks agent-config.yaml and install-config.yaml against
+// the hardware inventory and fails when any check fails
+func (a *EnhancedApp) runPreflight(ctx context.Context) error {
+	a.logger.LogInfo("Running pre-flight validation...")
+
+	agentConfig, err := a.installer.LoadAgentConfig()
+	if err != nil {
+		return err
+	}
+	installConfig, err := a.installer.LoadInstallConfig()
+	if err != nil {
+		return err
+	}
+
+	inventory, err := a.bmc.Redfish().GetInventory(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to collect inventory: %w", err)
+	}
+
+	// Apply the configured PCI address to the boot controller, picked like GetStorage does
+	for i, storage := range inventory.Storage {
+		if storage.ID == a.config.Storage.Controller || (a.config.Storage.Controller == "" && len(storage.Drives) > 0) {
+			applyPCIAddress(&inventory.Storage[i], a.config.Storage.PCIAddress)
+			break
+		}
+	}
+
+	report := preflight.Validate(agentConfig, installConfig, inventory)
+	report.Log(a.logger)
+	if report.Failed() {
+		return fmt.Errorf("pre-flight validation failed, fix agent-config.yaml or install-config.yaml in %s",
+			a.config.Paths.SourceDir)
+	}
+
+	a.logger.LogSuccess("Pre-flight validation passed")
+	return nil
+}
+
+// agentHost returns the agent-config.yaml host whose interfaces are on this
+// BMC, selected like pre-flight validation does; nil when none is declared
+func (a *EnhancedApp) agentHost(ctx context.Context) (*openshift.AgentHost, error) {
+	agentConfig, err := a.installer.LoadAgentConfig()
+	if err != nil {
+		return nil, err
+	}
+	if len(agentConfig.Hosts) == 0 {
+		return nil, nil
+	}
+
+	inventory, err := a.bmc.Redfish().GetInventory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect inventory: %w", err)
+	}
+	return preflight.SelectHost(agentConfig, inventory), nil
+}
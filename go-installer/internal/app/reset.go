@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"strings"
+	"time"
+
+	"openshift-sno-hub-installer/internal/idrac"
+)
+
+// reset sends a ComputerSystem.Reset action of any type, e.g. Nmi to make the
+// operating system dump its state
+func (a *EnhancedApp) reset(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return usagef("usage: reset <%s>", strings.Join(idrac.ResetTypes, "|"))
+	}
+
+	resetType := args[0]
+	valid := false
+	for _, known := range idrac.ResetTypes {
+		valid = valid || known == resetType
+	}
+	if !valid {
+		return usagef("unknown reset type %q (expected one of %s)", resetType, strings.Join(idrac.ResetTypes, ", "))
+	}
+
+	if err := a.confirm("Send %s reset to %s", resetType, a.config.IDRAC.IP); err != nil {
+		return err
+	}
+
+	a.logger.LogInfo("Sending %s reset...", resetType)
+	if err := a.bmc.Redfish().ResetSystem(ctx, resetType); err != nil {
+		return err
+	}
+	a.logger.LogSuccess("%s reset sent", resetType)
+	return nil
+}
+
+// resetPolicy builds the reset policy of the reset section
+func (a *EnhancedApp) resetPolicy() idrac.ResetPolicy {
+	cfg := a.config.Reset
+	return idrac.ResetPolicy{
+		OffType:         a.offType(cfg.OffType),
+		RestartType:     cfg.RestartType,
+		GracefulTimeout: time.Duration(cfg.GracefulTimeout) * time.Second,
+		Wait: idrac.WaitPolicy{
+			Interval:    time.Duration(cfg.WaitInterval) * time.Second,
+			MaxInterval: time.Duration(cfg.WaitMaxInterval) * time.Second,
+			Timeout:     time.Duration(cfg.WaitTimeout) * time.Second,
+		},
+	}
+}
+
+// offType resolves the auto off type. Whether a running host carries a SNO
+// cluster cannot be told from here, and cutting its power risks corrupting
+// etcd, so a system that is on is always shut down gracefully first; it is
+// forced off when it is still on after the graceful timeout.
+func (a *EnhancedApp) offType(offType string) string {
+	if offType != "" && offType != "auto" {
+		return offType
+	}
+	return idrac.ResetGracefulShutdown
+}
+
+// resetFlags registers the --type and --timeout flags of power-off and restart
+func (a *EnhancedApp) resetFlags(fs *flag.FlagSet, defaultType string) (*string, *time.Duration) {
+	resetType := fs.String("type", defaultType, "ResetType to send")
+	timeout := fs.Duration("timeout", secondsOr(a.config.Reset.GracefulTimeout, 300),
+		"how long a graceful reset may take before the system is forced off")
+	return resetType, timeout
+}
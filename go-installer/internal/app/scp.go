@@ -0,0 +1,159 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"openshift-sno-hub-installer/internal/idrac"
+)
+
+// scp dispatches the Server Configuration Profile subcommands: export and import
+func (a *EnhancedApp) scp(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usagef("usage: scp export|import [flags]")
+	}
+	subcommand, args := args[0], args[1:]
+	if err := a.requireDell("scp"); err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("scp "+subcommand, flag.ContinueOnError)
+	file := fs.String("file", "", "profile file (default: scp.xml or scp.json in paths.source_dir)")
+	target := fs.String("target", "ALL", "components to export or import: ALL or a comma-separated list of BIOS, IDRAC, NIC, RAID, ...")
+	format := fs.String("format", "xml", "export format, xml or json")
+	exportUse := fs.String("export-use", "Clone", "export use: Default, Clone (for replacement hardware) or Replace")
+	shutdown := fs.String("shutdown", "Graceful", "how the host is restarted to import: Graceful, Forced or NoReboot")
+	preview := fs.Bool("preview", false, "show what the import would change without applying it")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	switch subcommand {
+	case "export":
+		path := valueOr(*file, filepath.Join(a.config.Paths.SourceDir, "scp."+strings.ToLower(*format)))
+		return a.exportSCP(ctx, path, idrac.SCPExport{Format: *format, Target: *target, ExportUse: *exportUse})
+	case "import":
+		path, err := a.scpFile(*file)
+		if err != nil {
+			return err
+		}
+		profile, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read profile: %w", err)
+		}
+		if err := a.previewSCP(ctx, profile, *target); err != nil || *preview {
+			return err
+		}
+		if err := a.confirm("Import %s into %s", path, a.config.IDRAC.IP); err != nil {
+			return err
+		}
+		return a.importSCP(ctx, profile, idrac.SCPImport{Target: *target, ShutdownType: *shutdown})
+	default:
+		return usagef("unknown scp subcommand %q (expected export or import)", subcommand)
+	}
+}
+
+// scpFile returns the profile to import: file when given, otherwise the XML
+// or JSON profile in the source directory
+func (a *EnhancedApp) scpFile(file string) (string, error) {
+	if file != "" {
+		return file, nil
+	}
+	for _, name := range []string{"scp.xml", "scp.json"} {
+		path := filepath.Join(a.config.Paths.SourceDir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no profile found in %s, run 'scp export' first or pass --file", a.config.Paths.SourceDir)
+}
+
+// exportSCP exports the Server Configuration Profile to path
+func (a *EnhancedApp) exportSCP(ctx context.Context, path string, export idrac.SCPExport) error {
+	profile, err := a.bmc.Redfish().ExportSCP(ctx, export)
+	if err != nil {
+		return fmt.Errorf("failed to export server configuration profile: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	// The profile may hold hashed credentials and keys, keep it private
+	if err := os.WriteFile(path, profile, 0600); err != nil {
+		return fmt.Errorf("failed to write profile: %w", err)
+	}
+
+	attributes, err := idrac.ParseSCP(profile)
+	if err != nil {
+		a.logger.LogWarn("Exported profile could not be parsed: %v", err)
+	}
+	a.logger.LogSuccess("Server configuration profile with %d attribute(s) written to %s", len(attributes), path)
+	return nil
+}
+
+// previewSCP logs the attributes the profile would change and has the BMC
+// validate it with an import preview job
+func (a *EnhancedApp) previewSCP(ctx context.Context, profile []byte, target string) error {
+	desired, err := idrac.ParseSCP(profile)
+	if err != nil {
+		return err
+	}
+
+	client := a.bmc.Redfish()
+	current, err := client.ExportSCP(ctx, idrac.SCPExport{Format: "JSON", Target: target})
+	if err != nil {
+		return fmt.Errorf("failed to export current configuration: %w", err)
+	}
+	currentAttributes, err := idrac.ParseSCP(current)
+	if err != nil {
+		return fmt.Errorf("failed to parse current configuration: %w", err)
+	}
+
+	diffs := idrac.DiffSCP(currentAttributes, desired)
+	if len(diffs) == 0 {
+		a.logger.LogSuccess("Server configuration matches the profile")
+	} else {
+		a.logger.LogWarn("Importing the profile changes %d attribute(s):", len(diffs))
+		for _, diff := range diffs {
+			if diff.Missing {
+				a.logger.LogWarn("  %s %s: not in the current configuration (set to %q)", diff.Component, diff.Name, diff.Desired)
+			} else {
+				a.logger.LogWarn("  %s %s: %q -> %q", diff.Component, diff.Name, diff.Current, diff.Desired)
+			}
+		}
+	}
+
+	task, err := client.PreviewSCP(ctx, profile, target)
+	if err != nil {
+		return fmt.Errorf("import preview failed: %w", err)
+	}
+	a.logSCPMessages(task)
+	return nil
+}
+
+// importSCP applies the profile and logs the results of the import job
+func (a *EnhancedApp) importSCP(ctx context.Context, profile []byte, opts idrac.SCPImport) error {
+	task, err := a.bmc.Redfish().ImportSCP(ctx, profile, opts)
+	if err != nil {
+		return fmt.Errorf("failed to import server configuration profile: %w", err)
+	}
+	a.logSCPMessages(task)
+
+	if task.Scheduled() {
+		a.logger.LogInfo("Profile import is pending and applies on the next reset")
+		return nil
+	}
+	a.logger.LogSuccess("Server configuration profile imported")
+	return nil
+}
+
+// logSCPMessages logs the messages of a preview or import job
+func (a *EnhancedApp) logSCPMessages(task *idrac.Task) {
+	for _, message := range task.AllMessages() {
+		a.logger.LogInfo("  %s", strings.TrimSpace(message.MessageID+" "+message.Message))
+	}
+}
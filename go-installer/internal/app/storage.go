@@ -0,0 +1,297 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"openshift-sno-hub-installer/internal/idrac"
+)
+
+// storage dispatches the storage subcommands: list (default), clear-foreign,
+// create, delete, non-raid and prepare
+func (a *EnhancedApp) storage(ctx context.Context, args []string) error {
+	subcommand := "list"
+	if len(args) > 0 {
+		subcommand, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("storage "+subcommand, flag.ContinueOnError)
+	controller := fs.String("controller", a.config.Storage.Controller, "storage controller ID (default: first controller with drives)")
+	raidType := fs.String("raid", valueOr(a.config.Storage.RAIDType, "RAID1"), "RAID level of the new volume, RAID0 or RAID1")
+	name := fs.String("name", valueOr(a.config.Storage.VolumeName, "sno-boot"), "name of the new volume")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	switch subcommand {
+	case "clear-foreign", "non-raid":
+		if err := a.requireDell("storage " + subcommand); err != nil {
+			return err
+		}
+	}
+	switch subcommand {
+	case "clear-foreign", "create", "delete", "non-raid", "prepare":
+		if err := a.confirm("Run storage %s on %s, which may destroy data", subcommand, a.config.IDRAC.IP); err != nil {
+			return err
+		}
+	}
+	if subcommand == "prepare" {
+		return a.prepareStorage(ctx)
+	}
+
+	storage, err := a.getStorage(ctx, *controller)
+	if err != nil {
+		return err
+	}
+	client := a.bmc.Redfish()
+
+	var change *idrac.PendingChange
+	switch subcommand {
+	case "list":
+		a.logStorage(storage)
+		return nil
+	case "clear-foreign":
+		change, err = client.ClearForeignConfig(ctx, storage)
+	case "create":
+		change, err = client.CreateVolume(ctx, storage, idrac.VolumeSpec{Name: *name, RAIDType: *raidType, Drives: fs.Args()})
+	case "delete":
+		if fs.NArg() != 1 {
+			return usagef("please provide the volume ID to delete")
+		}
+		volume := findVolume(storage, fs.Arg(0))
+		if volume == nil {
+			return fmt.Errorf("volume %q not found on %s", fs.Arg(0), storage.ID)
+		}
+		change, err = client.DeleteVolume(ctx, volume)
+	case "non-raid":
+		if fs.NArg() == 0 {
+			return usagef("please provide the drive IDs to convert")
+		}
+		change, err = client.ConvertToNonRAID(ctx, fs.Args())
+	default:
+		return usagef("unknown storage subcommand %q (expected list, clear-foreign, create, delete, non-raid or prepare)", subcommand)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := a.waitForChange(ctx, change); err != nil {
+		return err
+	}
+
+	storage, err = a.getStorage(ctx, storage.ID)
+	if err != nil {
+		return err
+	}
+	a.logStorage(storage)
+	return nil
+}
+
+// getStorage returns the storage controller and fills in the by-path names
+// when the controller PCI address is configured
+func (a *EnhancedApp) getStorage(ctx context.Context, id string) (*idrac.Storage, error) {
+	storage, err := a.bmc.Redfish().GetStorage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	applyPCIAddress(storage, a.config.Storage.PCIAddress)
+	return storage, nil
+}
+
+// applyPCIAddress overrides the controller PCI address and re-derives the
+// /dev/disk/by-path names of its drives and volumes
+func applyPCIAddress(storage *idrac.Storage, pciAddress string) {
+	if pciAddress == "" {
+		return
+	}
+
+	storage.PCIAddress = pciAddress
+	for i := range storage.Drives {
+		storage.Drives[i].DiskByPath = idrac.DiskByPath(pciAddress, storage.Drives[i].ID)
+	}
+	for i := range storage.Volumes {
+		storage.Volumes[i].DiskByPath = idrac.DiskByPath(pciAddress, storage.Volumes[i].ID)
+	}
+}
+
+// logStorage logs the controller, its drives and volumes
+func (a *EnhancedApp) logStorage(storage *idrac.Storage) {
+	a.logger.LogInfo("Storage controller %s (PCI %s):", storage.ID, valueOr(storage.PCIAddress, "unknown"))
+	for _, controller := range storage.Controllers {
+		a.logger.LogInfo("  Controller: %s, firmware %s", controller.Model, controller.FirmwareVersion)
+	}
+	for _, drive := range storage.Drives {
+		a.logger.LogInfo("  Drive %s: %s %s, %d GB, %s %s", drive.ID, drive.Model, drive.SerialNumber,
+			drive.CapacityBytes/1000/1000/1000, drive.MediaType, drive.DiskByPath)
+	}
+	for _, volume := range storage.Volumes {
+		a.logger.LogInfo("  Volume %s (%s): %s, %d GB, drives %v %s", volume.ID, volume.Name,
+			valueOr(volume.RAIDType, volume.VolumeType), volume.CapacityBytes/1000/1000/1000, volume.Drives, volume.DiskByPath)
+	}
+}
+
+// waitForChange waits for the job of a storage change. Jobs that need a reset
+// are applied by resetting the system.
+func (a *EnhancedApp) waitForChange(ctx context.Context, change *idrac.PendingChange) error {
+	if change.JobURI == "" {
+		return nil
+	}
+	if !change.Immediate {
+		return a.applyPendingChange(ctx, change)
+	}
+	if _, err := a.bmc.Redfish().WaitForTask(ctx, change.JobURI); err != nil {
+		return fmt.Errorf("storage job failed: %w", err)
+	}
+	return nil
+}
+
+// prepareStorage builds the boot volume declared in the storage configuration
+// and reports its /dev/disk/by-path name for rootDeviceHints
+func (a *EnhancedApp) prepareStorage(ctx context.Context) error {
+	cfg := a.config.Storage
+	client := a.bmc.Redfish()
+	if cfg.ClearForeignConfig || cfg.Mode == "non-raid" {
+		if err := a.requireDell("storage prepare with clear_foreign_config or mode non-raid"); err != nil {
+			return err
+		}
+	}
+
+	a.logger.LogInfo("Preparing boot storage (mode %s)...", valueOr(cfg.Mode, "raid"))
+	storage, err := a.getStorage(ctx, cfg.Controller)
+	if err != nil {
+		return err
+	}
+
+	if cfg.ClearForeignConfig {
+		change, err := client.ClearForeignConfig(ctx, storage)
+		if err == nil {
+			err = a.waitForChange(ctx, change)
+		}
+		if err != nil {
+			// iDRAC fails the action when there is no foreign configuration
+			a.logger.LogWarn("Failed to clear foreign configuration: %v", err)
+		}
+	}
+
+	if cfg.DeleteVolumes {
+		for i := range storage.Volumes {
+			change, err := client.DeleteVolume(ctx, &storage.Volumes[i])
+			if err != nil {
+				return err
+			}
+			if err := a.waitForChange(ctx, change); err != nil {
+				return err
+			}
+		}
+		if storage, err = a.getStorage(ctx, storage.ID); err != nil {
+			return err
+		}
+	}
+
+	var diskPaths []string
+	if cfg.Mode == "non-raid" {
+		drives := selectDrives(storage, cfg.Drives, len(storage.Drives))
+		change, err := client.ConvertToNonRAID(ctx, drives)
+		if err != nil {
+			return err
+		}
+		if err := a.waitForChange(ctx, change); err != nil {
+			return err
+		}
+		if storage, err = a.getStorage(ctx, storage.ID); err != nil {
+			return err
+		}
+		for _, drive := range storage.Drives {
+			if containsString(drives, drive.ID) {
+				diskPaths = append(diskPaths, drive.DiskByPath)
+			}
+		}
+	} else {
+		name := valueOr(cfg.VolumeName, "sno-boot")
+		volume := findVolume(storage, name)
+		if volume != nil {
+			a.logger.LogInfo("Volume %s already exists, skipping creation", name)
+		} else {
+			raidType := valueOr(cfg.RAIDType, "RAID1")
+			count := 1
+			if raidType == "RAID1" {
+				count = 2
+			}
+			spec := idrac.VolumeSpec{Name: name, RAIDType: raidType, Drives: selectDrives(storage, cfg.Drives, count)}
+			change, err := client.CreateVolume(ctx, storage, spec)
+			if err != nil {
+				return err
+			}
+			if err := a.waitForChange(ctx, change); err != nil {
+				return err
+			}
+			if storage, err = a.getStorage(ctx, storage.ID); err != nil {
+				return err
+			}
+			if volume = findVolume(storage, name); volume == nil {
+				return fmt.Errorf("volume %s not found on %s after creation", name, storage.ID)
+			}
+		}
+		diskPaths = append(diskPaths, volume.DiskByPath)
+	}
+
+	a.logStorage(storage)
+	a.reportRootDevice(ctx, diskPaths)
+	a.logger.LogSuccess("Boot storage prepared")
+	return nil
+}
+
+// reportRootDevice logs the by-path name of the prepared boot disk and warns
+// when the agent-config.yaml host on this BMC points rootDeviceHints elsewhere
+func (a *EnhancedApp) reportRootDevice(ctx context.Context, diskPaths []string) {
+	if len(diskPaths) == 0 || diskPaths[0] == "" {
+		a.logger.LogWarn("Cannot derive /dev/disk/by-path of the boot disk, set storage.pci_address to report it")
+		return
+	}
+	a.logger.LogInfo("Boot disk: rootDeviceHints.deviceName: %s", diskPaths[0])
+
+	host, err := a.agentHost(ctx)
+	if err != nil || host == nil {
+		return
+	}
+	if deviceName := host.RootDeviceHints.DeviceName; deviceName != "" && !containsString(diskPaths, deviceName) {
+		a.logger.LogWarn("agent-config.yaml rootDeviceHints.deviceName is %s, update it to %s", deviceName, diskPaths[0])
+	}
+}
+
+// selectDrives returns the configured drive IDs, or the first count drives of the controller
+func selectDrives(storage *idrac.Storage, configured []string, count int) []string {
+	if len(configured) > 0 {
+		return configured
+	}
+
+	var drives []string
+	for _, drive := range storage.Drives {
+		if len(drives) == count {
+			break
+		}
+		drives = append(drives, drive.ID)
+	}
+	return drives
+}
+
+// findVolume returns the volume with the given ID or name
+func findVolume(storage *idrac.Storage, idOrName string) *idrac.Volume {
+	for i, volume := range storage.Volumes {
+		if volume.ID == idOrName || volume.Name == idOrName {
+			return &storage.Volumes[i]
+		}
+	}
+	return nil
+}
+
+// containsString reports whether list contains value
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
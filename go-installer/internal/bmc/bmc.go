@@ -0,0 +1,182 @@
+package bmc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"openshift-sno-hub-installer/internal/config"
+	"openshift-sno-hub-installer/internal/idrac"
+	"openshift-sno-hub-installer/internal/logger"
+)
+
+// Vendor identifiers accepted in the bmc.vendor configuration field
+const (
+	VendorAuto       = "auto"
+	VendorDell       = "dell"
+	VendorHPE        = "hpe"
+	VendorSupermicro = "supermicro"
+	VendorRedfish    = "redfish"
+)
+
+// BMC is the vendor-neutral interface to a server's baseboard management controller
+type BMC interface {
+	// Vendor returns the name of the driver in use
+	Vendor() string
+	// Redfish returns the underlying Redfish client for operations outside
+	// this interface. Its standard operations adapt to the vendor; the Dell
+	// OEM ones (SCP, the RAID service, the Lifecycle Controller log) fail on
+	// other BMCs, so callers check RequireVendor before using them.
+	Redfish() *idrac.EnhancedClient
+
+	CheckConnectivity(ctx context.Context) error
+
+	// Power control
+	GetSystemPowerState(ctx context.Context) (string, error)
+	PowerOnSystem(ctx context.Context) error
+	PowerOffSystem(ctx context.Context) error
+	RestartSystem(ctx context.Context) error
+
+	// Boot override; an empty BootOverride.Target selects the virtual CD/DVD
+	SetBoot(ctx context.Context, override idrac.BootOverride) error
+	SetVirtualCDBoot(ctx context.Context) error
+	SetHDDBoot(ctx context.Context) error
+
+	// Virtual media
+	InsertVirtualMedia(ctx context.Context, isoURL string) error
+	EjectVirtualMedia(ctx context.Context) error
+	GetVirtualMediaInfo(ctx context.Context) (*idrac.VirtualMediaInfo, error)
+	ManageVirtualMediaBootProcess(ctx context.Context, isoURL string) error
+
+	// Inventory and health
+	GetSystemInfo(ctx context.Context) (*idrac.SystemInfo, error)
+	GetSystemHealth(ctx context.Context) (string, error)
+	GetManagerInfo(ctx context.Context) (*idrac.LifecycleControllerInfo, error)
+}
+
+// Compile-time checks that every driver implements BMC
+var (
+	_ BMC = (*dellDriver)(nil)
+	_ BMC = (*hpeDriver)(nil)
+	_ BMC = (*supermicroDriver)(nil)
+	_ BMC = (*redfishDriver)(nil)
+)
+
+// New creates the BMC driver selected by bmcCfg.Vendor, detecting the vendor
+// from the Redfish service root when it is empty or set to auto
+func New(ctx context.Context, idracCfg *config.IDRACConfig, bmcCfg *config.BMCConfig, log *logger.Logger) (BMC, error) {
+	client := idrac.NewEnhancedClient(idracCfg, log)
+
+	vendor := bmcCfg.Vendor
+	if vendor == "" || vendor == VendorAuto {
+		detected, err := Detect(ctx, client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect BMC vendor: %w", err)
+		}
+		log.LogInfo("Detected BMC vendor: %s", detected)
+		vendor = detected
+	}
+
+	switch vendor {
+	case VendorDell:
+		return newDellDriver(client), nil
+	case VendorHPE:
+		return newHPEDriver(client, log), nil
+	case VendorSupermicro:
+		return newSupermicroDriver(client, log), nil
+	case VendorRedfish:
+		return newRedfishDriver(client, log), nil
+	default:
+		return nil, fmt.Errorf("unsupported BMC vendor: %s", vendor)
+	}
+}
+
+// RequireVendor returns an error when b is not driven by the vendor driver.
+// feature names the operation that needs the OEM extensions of the vendor.
+func RequireVendor(b BMC, vendor, feature string) error {
+	if b.Vendor() != vendor {
+		return fmt.Errorf("%s is only supported by the %s driver, the BMC uses the %s driver", feature, vendor, b.Vendor())
+	}
+	return nil
+}
+
+// Detect determines the driver to use from the service root Vendor property,
+// falling back to the system manufacturer on firmware that predates it
+func Detect(ctx context.Context, client *idrac.EnhancedClient) (string, error) {
+	res, err := client.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	vendor := res.Root.Vendor
+	if vendor == "" {
+		info, err := client.GetSystemInfo(ctx)
+		if err != nil {
+			return "", err
+		}
+		vendor = info.Manufacturer
+	}
+
+	return vendorFromString(vendor), nil
+}
+
+// vendorFromString maps a Redfish Vendor or Manufacturer string to a driver name
+func vendorFromString(vendor string) string {
+	vendor = strings.ToLower(vendor)
+	switch {
+	case strings.Contains(vendor, "dell"):
+		return VendorDell
+	case strings.Contains(vendor, "hpe"), strings.Contains(vendor, "hewlett"):
+		return VendorHPE
+	case strings.Contains(vendor, "supermicro"):
+		return VendorSupermicro
+	default:
+		return VendorRedfish
+	}
+}
+
+// setVirtualCDBoot sets a one-time boot from the virtual CD/DVD through the driver's SetBoot
+func setVirtualCDBoot(ctx context.Context, b BMC, log *logger.Logger) error {
+	log.LogInfo("Setting boot device to Virtual CD/DVD...")
+
+	if err := b.SetBoot(ctx, idrac.BootOverride{Persistence: "Once"}); err != nil {
+		log.LogError("Failed to set boot device to Virtual CD/DVD: %v", err)
+		return err
+	}
+
+	log.LogSuccess("Boot device set to Virtual CD/DVD successfully")
+	return nil
+}
+
+// bootFromVirtualMedia runs the eject, insert, one-time CD boot and restart
+// sequence using the driver's own primitives
+func bootFromVirtualMedia(ctx context.Context, b BMC, log *logger.Logger, isoURL string) error {
+	log.LogInfo("Starting virtual media boot process (%s driver)...", b.Vendor())
+
+	log.LogInfo("Step 1: Ejecting existing virtual media...")
+	if err := b.EjectVirtualMedia(ctx); err != nil {
+		log.LogWarn("Failed to eject existing virtual media: %v", err)
+	}
+
+	log.LogInfo("Step 2: Inserting new virtual media...")
+	if err := b.InsertVirtualMedia(ctx, isoURL); err != nil {
+		return fmt.Errorf("failed to insert virtual media: %w", err)
+	}
+
+	if err := b.Redfish().WaitForVirtualMedia(ctx, true); err != nil {
+		return fmt.Errorf("virtual media was not attached: %w", err)
+	}
+
+	log.LogInfo("Step 3: Setting boot device to virtual CD/DVD...")
+	if err := b.SetVirtualCDBoot(ctx); err != nil {
+		return fmt.Errorf("failed to set boot device to virtual CD/DVD: %w", err)
+	}
+
+	log.LogInfo("Step 4: Restarting system...")
+	if err := b.RestartSystem(ctx); err != nil {
+		return fmt.Errorf("failed to restart system: %w", err)
+	}
+
+	log.LogSuccess("Virtual media boot process completed successfully")
+	return nil
+}
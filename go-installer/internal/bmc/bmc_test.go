@@ -0,0 +1,119 @@
+package bmc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"openshift-sno-hub-installer/internal/config"
+	"openshift-sno-hub-installer/internal/idrac"
+	"openshift-sno-hub-installer/internal/logger"
+)
+
+func TestVendorFromString(t *testing.T) {
+	tests := []struct {
+		vendor string
+		want   string
+	}{
+		{"Dell", VendorDell},
+		{"Dell Inc.", VendorDell},
+		{"HPE", VendorHPE},
+		{"Hewlett Packard Enterprise", VendorHPE},
+		{"Supermicro", VendorSupermicro},
+		{"SUPERMICRO", VendorSupermicro},
+		{"Lenovo", VendorRedfish},
+		{"", VendorRedfish},
+	}
+	for _, tt := range tests {
+		if got := vendorFromString(tt.vendor); got != tt.want {
+			t.Errorf("vendorFromString(%q) = %s, expected %s", tt.vendor, got, tt.want)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name         string
+		rootVendor   string
+		manufacturer string
+		want         string
+	}{
+		{"service root vendor", "Dell", "", VendorDell},
+		{"manufacturer fallback", "", "HPE", VendorHPE},
+		{"root vendor wins", "Supermicro", "Dell Inc.", VendorSupermicro},
+		{"unknown vendor", "", "Lenovo", VendorRedfish},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, tt.rootVendor, tt.manufacturer)
+			got, err := Detect(context.Background(), client)
+			if err != nil {
+				t.Fatalf("Detect failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRequireVendor(t *testing.T) {
+	client := newTestClient(t, "HPE", "")
+	if err := RequireVendor(newHPEDriver(client, nil), VendorDell, "scp"); err == nil ||
+		!strings.Contains(err.Error(), "scp is only supported by the dell driver") {
+		t.Errorf("Expected scp to be rejected on HPE, got %v", err)
+	}
+	if err := RequireVendor(newDellDriver(client), VendorDell, "scp"); err != nil {
+		t.Errorf("Expected scp to be accepted on Dell, got %v", err)
+	}
+}
+
+// newTestClient returns a Redfish client of a BMC that reports rootVendor in
+// its service root and manufacturer as its system manufacturer
+func newTestClient(t *testing.T, rootVendor, manufacturer string) *idrac.EnhancedClient {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/redfish/v1", func(w http.ResponseWriter, r *http.Request) {
+		root := map[string]interface{}{
+			"Systems":  map[string]string{"@odata.id": "/redfish/v1/Systems"},
+			"Managers": map[string]string{"@odata.id": "/redfish/v1/Managers"},
+		}
+		if rootVendor != "" {
+			root["Vendor"] = rootVendor
+		}
+		json.NewEncoder(w).Encode(root)
+	})
+	mux.HandleFunc("/redfish/v1/Systems", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"Members": []map[string]string{{"@odata.id": "/redfish/v1/Systems/1"}},
+		})
+	})
+	mux.HandleFunc("/redfish/v1/Managers", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"Members": []map[string]string{{"@odata.id": "/redfish/v1/Managers/1"}},
+		})
+	})
+	mux.HandleFunc("/redfish/v1/Managers/1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"Id": "1"})
+	})
+	mux.HandleFunc("/redfish/v1/Systems/1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"Manufacturer": manufacturer, "PowerState": "On"})
+	})
+	server := httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+
+	log := logger.NewFileLogger(t.TempDir())
+	t.Cleanup(func() { log.Close() })
+
+	return idrac.NewEnhancedClient(&config.IDRACConfig{
+		IP:         strings.TrimPrefix(server.URL, "https://"),
+		Username:   "root",
+		Password:   "calvin",
+		Timeout:    5,
+		AuthMethod: "basic",
+	}, log)
+}
@@ -0,0 +1,43 @@
+package bmc
+
+import (
+	"context"
+
+	"openshift-sno-hub-installer/internal/idrac"
+)
+
+// dellDriver drives Dell iDRAC 8/9 controllers through the iDRAC client
+type dellDriver struct {
+	*idrac.EnhancedClient
+}
+
+// newDellDriver creates a Dell iDRAC driver
+func newDellDriver(client *idrac.EnhancedClient) *dellDriver {
+	return &dellDriver{EnhancedClient: client}
+}
+
+// Vendor returns the driver name
+func (d *dellDriver) Vendor() string {
+	return VendorDell
+}
+
+// Redfish returns the underlying Redfish client
+func (d *dellDriver) Redfish() *idrac.EnhancedClient {
+	return d.EnhancedClient
+}
+
+// SetBoot sets a boot source override, negotiating the iDRAC virtual CD/DVD target
+func (d *dellDriver) SetBoot(ctx context.Context, override idrac.BootOverride) error {
+	_, err := d.ApplyBootOverride(ctx, override, idrac.DellVirtualCDTargets)
+	return err
+}
+
+// SetVirtualCDBoot sets a one-time boot from the iDRAC virtual CD/DVD
+func (d *dellDriver) SetVirtualCDBoot(ctx context.Context) error {
+	return d.SetVirtualCDBootEnhanced(ctx)
+}
+
+// GetManagerInfo retrieves iDRAC lifecycle controller information
+func (d *dellDriver) GetManagerInfo(ctx context.Context) (*idrac.LifecycleControllerInfo, error) {
+	return d.GetLifecycleControllerInfo(ctx)
+}
@@ -0,0 +1,56 @@
+package bmc
+
+import (
+	"context"
+
+	"openshift-sno-hub-installer/internal/idrac"
+	"openshift-sno-hub-installer/internal/logger"
+)
+
+// hpeDriver drives HPE iLO 5/6 controllers
+type hpeDriver struct {
+	*redfishDriver
+}
+
+// newHPEDriver creates an HPE iLO driver
+func newHPEDriver(client *idrac.EnhancedClient, log *logger.Logger) *hpeDriver {
+	return &hpeDriver{redfishDriver: newRedfishDriver(client, log)}
+}
+
+// Vendor returns the driver name
+func (d *hpeDriver) Vendor() string {
+	return VendorHPE
+}
+
+// SetBoot sets a boot source override; for the virtual CD/DVD it also flags
+// the inserted image for the next reset
+func (d *hpeDriver) SetBoot(ctx context.Context, override idrac.BootOverride) error {
+	if override.Target == "" {
+		// iLO only boots virtual media on the next reset when asked through its OEM extension
+		vmURI, err := d.VirtualMediaURI(ctx)
+		if err != nil {
+			return err
+		}
+		oem := map[string]interface{}{
+			"Oem": map[string]interface{}{
+				"Hpe": map[string]interface{}{"BootOnNextServerReset": true},
+			},
+		}
+		if err := d.Patch(ctx, vmURI, oem); err != nil {
+			d.logger.LogWarn("Failed to set BootOnNextServerReset on %s: %v", vmURI, err)
+		}
+	}
+
+	_, err := d.ApplyBootOverride(ctx, override, []string{"Cd"})
+	return err
+}
+
+// SetVirtualCDBoot sets a one-time boot from the iLO virtual CD/DVD
+func (d *hpeDriver) SetVirtualCDBoot(ctx context.Context) error {
+	return setVirtualCDBoot(ctx, d, d.logger)
+}
+
+// ManageVirtualMediaBootProcess boots the system from the given ISO
+func (d *hpeDriver) ManageVirtualMediaBootProcess(ctx context.Context, isoURL string) error {
+	return bootFromVirtualMedia(ctx, d, d.logger, isoURL)
+}
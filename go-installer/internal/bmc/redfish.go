@@ -0,0 +1,73 @@
+package bmc
+
+import (
+	"context"
+
+	"openshift-sno-hub-installer/internal/idrac"
+	"openshift-sno-hub-installer/internal/logger"
+)
+
+// redfishDriver drives any BMC that follows the DMTF Redfish specification
+type redfishDriver struct {
+	*idrac.EnhancedClient
+	logger *logger.Logger
+}
+
+// newRedfishDriver creates a generic Redfish driver
+func newRedfishDriver(client *idrac.EnhancedClient, log *logger.Logger) *redfishDriver {
+	return &redfishDriver{EnhancedClient: client, logger: log}
+}
+
+// Vendor returns the driver name
+func (d *redfishDriver) Vendor() string {
+	return VendorRedfish
+}
+
+// Redfish returns the underlying Redfish client
+func (d *redfishDriver) Redfish() *idrac.EnhancedClient {
+	return d.EnhancedClient
+}
+
+// InsertVirtualMedia inserts an ISO using the standard InsertMedia parameters
+func (d *redfishDriver) InsertVirtualMedia(ctx context.Context, isoURL string) error {
+	d.logger.LogInfo("Inserting ISO image: %s", isoURL)
+
+	if err := d.InsertVirtualMediaRequest(ctx, standardMediaRequest(isoURL)); err != nil {
+		d.logger.LogError("Failed to insert virtual media: %v", err)
+		return err
+	}
+
+	d.logger.LogSuccess("Virtual media inserted successfully")
+	return nil
+}
+
+// SetBoot sets a boot source override; the virtual CD/DVD is the standard Cd target
+func (d *redfishDriver) SetBoot(ctx context.Context, override idrac.BootOverride) error {
+	_, err := d.ApplyBootOverride(ctx, override, []string{"Cd"})
+	return err
+}
+
+// SetVirtualCDBoot sets a one-time boot from the virtual CD/DVD
+func (d *redfishDriver) SetVirtualCDBoot(ctx context.Context) error {
+	return setVirtualCDBoot(ctx, d, d.logger)
+}
+
+// GetManagerInfo retrieves BMC manager information
+func (d *redfishDriver) GetManagerInfo(ctx context.Context) (*idrac.LifecycleControllerInfo, error) {
+	return d.GetLifecycleControllerInfo(ctx)
+}
+
+// ManageVirtualMediaBootProcess boots the system from the given ISO
+func (d *redfishDriver) ManageVirtualMediaBootProcess(ctx context.Context, isoURL string) error {
+	return bootFromVirtualMedia(ctx, d, d.logger, isoURL)
+}
+
+// standardMediaRequest builds an InsertMedia body as defined by the DMTF schema
+func standardMediaRequest(isoURL string) idrac.VirtualMediaRequest {
+	inserted, writeProtected := true, true
+	return idrac.VirtualMediaRequest{
+		Image:          isoURL,
+		Inserted:       &inserted,
+		WriteProtected: &writeProtected,
+	}
+}
@@ -0,0 +1,60 @@
+package bmc
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"openshift-sno-hub-installer/internal/idrac"
+	"openshift-sno-hub-installer/internal/logger"
+)
+
+// supermicroDriver drives Supermicro X11/X12/X13 BMCs
+type supermicroDriver struct {
+	*redfishDriver
+}
+
+// newSupermicroDriver creates a Supermicro driver
+func newSupermicroDriver(client *idrac.EnhancedClient, log *logger.Logger) *supermicroDriver {
+	return &supermicroDriver{redfishDriver: newRedfishDriver(client, log)}
+}
+
+// Vendor returns the driver name
+func (d *supermicroDriver) Vendor() string {
+	return VendorSupermicro
+}
+
+// InsertVirtualMedia inserts an ISO, passing the transfer protocol Supermicro firmware requires
+func (d *supermicroDriver) InsertVirtualMedia(ctx context.Context, isoURL string) error {
+	d.logger.LogInfo("Inserting ISO image: %s", isoURL)
+
+	mediaReq := standardMediaRequest(isoURL)
+	if u, err := url.Parse(isoURL); err == nil && u.Scheme != "" {
+		mediaReq.TransferProtocolType = strings.ToUpper(u.Scheme)
+	}
+
+	if err := d.InsertVirtualMediaRequest(ctx, mediaReq); err != nil {
+		d.logger.LogError("Failed to insert virtual media: %v", err)
+		return err
+	}
+
+	d.logger.LogSuccess("Virtual media inserted successfully")
+	return nil
+}
+
+// SetBoot sets a boot source override; X11 boards expose the virtual drive
+// as UsbCd, newer boards as Cd
+func (d *supermicroDriver) SetBoot(ctx context.Context, override idrac.BootOverride) error {
+	_, err := d.ApplyBootOverride(ctx, override, []string{"UsbCd", "Cd"})
+	return err
+}
+
+// SetVirtualCDBoot sets a one-time boot from the virtual CD/DVD
+func (d *supermicroDriver) SetVirtualCDBoot(ctx context.Context) error {
+	return setVirtualCDBoot(ctx, d, d.logger)
+}
+
+// ManageVirtualMediaBootProcess boots the system from the given ISO
+func (d *supermicroDriver) ManageVirtualMediaBootProcess(ctx context.Context, isoURL string) error {
+	return bootFromVirtualMedia(ctx, d, d.logger, isoURL)
+}
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,6 +13,15 @@ import (
 // Config holds all configuration for the application
 type Config struct {
 	IDRAC     IDRACConfig     `yaml:"idrac"`
+	BMC       BMCConfig       `yaml:"bmc"`
+	Boot      BootConfig      `yaml:"boot"`
+	BIOS      BIOSConfig      `yaml:"bios"`
+	Storage   StorageConfig   `yaml:"storage"`
+	Firmware  FirmwareConfig  `yaml:"firmware"`
+	Events    EventsConfig    `yaml:"events"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Power     PowerConfig     `yaml:"power"`
+	Reset     ResetConfig     `yaml:"reset"`
 	OpenShift OpenShiftConfig `yaml:"openshift"`
 	Remote    RemoteConfig    `yaml:"remote"`
 	Paths     PathsConfig     `yaml:"paths"`
@@ -23,6 +34,164 @@ type IDRACConfig struct {
 	Password   string `yaml:"password"`
 	VerifySSL  bool   `yaml:"verify_ssl"`
 	Timeout    int    `yaml:"timeout"`
+	// SystemID and ManagerID select a member of the Redfish Systems and
+	// Managers collections; the first member is used when left empty
+	SystemID   string `yaml:"system_id,omitempty"`
+	ManagerID  string `yaml:"manager_id,omitempty"`
+	// AuthMethod is "session" (X-Auth-Token, the default) or "basic"
+	AuthMethod string `yaml:"auth_method,omitempty"`
+	// CABundle is a PEM file of CAs trusted to sign the BMC certificate;
+	// setting it turns on certificate verification
+	CABundle string `yaml:"ca_bundle,omitempty"`
+	// Fingerprint pins the SHA-256 fingerprint of the BMC certificate
+	// (hex, colons optional); it is checked with or without verify_ssl
+	Fingerprint string `yaml:"fingerprint,omitempty"`
+	// TrustOnFirstUse records the BMC certificate fingerprint on the first
+	// connection and refuses to connect when it changes later
+	TrustOnFirstUse bool `yaml:"trust_on_first_use,omitempty"`
+	// KnownHosts is the file the trusted fingerprints are recorded in
+	// (default bmc_known_hosts)
+	KnownHosts string `yaml:"known_hosts,omitempty"`
+	// TaskTimeout is how long in seconds a Redfish task or iDRAC job may
+	// run before it is given up (default 7200)
+	TaskTimeout int `yaml:"task_timeout,omitempty"`
+}
+
+// BMCConfig holds vendor-neutral BMC driver configuration
+type BMCConfig struct {
+	// Vendor selects the BMC driver: auto, dell, hpe, supermicro or redfish
+	Vendor string `yaml:"vendor"`
+	// Network is the NTP, DNS and VLAN configuration of the BMC itself
+	Network BMCNetworkConfig `yaml:"network"`
+}
+
+// BMCNetworkConfig holds the network settings applied to the BMC by
+// 'bmc-network apply'; unset values are left alone
+type BMCNetworkConfig struct {
+	NTPServers []string `yaml:"ntp_servers,omitempty"`
+	// DNSServers are static DNS servers; iDRAC accepts at most two
+	DNSServers []string `yaml:"dns_servers,omitempty"`
+	// VLANID tags the BMC traffic; 0 disables VLAN tagging
+	VLANID *int `yaml:"vlan_id,omitempty"`
+}
+
+// BootConfig holds persistent boot order configuration
+type BootConfig struct {
+	// DiskFirst moves the installation disk (selected by the rootDeviceHints
+	// of agent-config.yaml) to the front of the persistent boot order
+	DiskFirst bool `yaml:"disk_first"`
+}
+
+// BIOSConfig holds BIOS attribute profile configuration
+type BIOSConfig struct {
+	// Profile is the path of a YAML file declaring the expected BIOS attributes
+	Profile string `yaml:"profile,omitempty"`
+	// Enforce applies the profile during install before the agent ISO is booted
+	Enforce bool `yaml:"enforce"`
+	// SystemProfile is the BIOS system profile applied during install:
+	// Performance, PerformancePerWatt, PerformancePerWattOS, Custom or a
+	// SysProfile value; it overrides SysProfile of the profile
+	SystemProfile string `yaml:"system_profile,omitempty"`
+}
+
+// StorageConfig holds boot volume preparation configuration
+type StorageConfig struct {
+	// Prepare builds the boot volume during install before the ISO is booted
+	Prepare bool `yaml:"prepare"`
+	// Controller is the Storage member ID (e.g. RAID.Integrated.1-1); the
+	// first controller with drives is used when empty
+	Controller         string `yaml:"controller,omitempty"`
+	ClearForeignConfig bool   `yaml:"clear_foreign_config"`
+	DeleteVolumes      bool   `yaml:"delete_volumes"`
+	// Mode is "raid" to create a virtual disk or "non-raid" to pass the drives through
+	Mode       string `yaml:"mode,omitempty"`
+	RAIDType   string `yaml:"raid_type,omitempty"`
+	VolumeName string `yaml:"volume_name,omitempty"`
+	// Drives are the drive IDs to use; the first drives of the controller when empty
+	Drives []string `yaml:"drives,omitempty"`
+	// PCIAddress overrides the controller PCI address used for /dev/disk/by-path names
+	PCIAddress string `yaml:"pci_address,omitempty"`
+}
+
+// FirmwareConfig holds firmware baseline configuration
+type FirmwareConfig struct {
+	// Manifest is the path of the firmware baseline YAML file
+	Manifest string `yaml:"manifest,omitempty"`
+	// BaseURL is where the BMC downloads the update packages named in the
+	// manifest; the firmware/ directory next to remote.iso_url when empty
+	BaseURL string `yaml:"base_url,omitempty"`
+	// Upload copies the update packages next to the manifest to
+	// remote.path/firmware on the web cache host before updating
+	Upload bool `yaml:"upload"`
+	// Method is "simple" (SimpleUpdate, the BMC pulls from BaseURL) or
+	// "multipart" (the package is pushed to the BMC)
+	Method string `yaml:"method,omitempty"`
+	// Enforce brings the firmware to the baseline during install
+	Enforce bool `yaml:"enforce"`
+}
+
+// EventsConfig holds BMC event subscription configuration
+type EventsConfig struct {
+	// Enabled subscribes to BMC events during install, so waits react to
+	// power and virtual media changes as they happen
+	Enabled bool `yaml:"enabled"`
+	// Mode is "auto" (SSE when the BMC offers it, push otherwise), "sse" or "push"
+	Mode string `yaml:"mode,omitempty"`
+	// Listen is the address of the HTTPS listener receiving pushed events
+	Listen string `yaml:"listen,omitempty"`
+	// Destination is the listener URL the BMC posts events to; derived from
+	// the local address that reaches the BMC when empty
+	Destination string `yaml:"destination,omitempty"`
+	// CertFile and KeyFile hold the listener certificate; a self-signed
+	// certificate is generated when empty
+	CertFile string `yaml:"cert_file,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty"`
+}
+
+// PowerConfig holds the chassis power policy applied during install
+type PowerConfig struct {
+	// LimitWatts is the chassis power cap; unset leaves the cap alone, 0 removes it
+	LimitWatts *int `yaml:"limit_watts,omitempty"`
+	// LimitException is the action taken when the cap cannot be held:
+	// NoAction, HardPowerOff, LogEventOnly or Oem
+	LimitException string `yaml:"limit_exception,omitempty"`
+	// PSURedundancy is the PSU redundancy policy: on iDRAC a
+	// ServerPwr.1.PSRedPolicy value such as "A/B Grid Redundant", elsewhere
+	// a Redfish redundancy mode such as N+m
+	PSURedundancy string `yaml:"psu_redundancy,omitempty"`
+}
+
+// ResetConfig holds the reset types used to power off and restart the system
+// and the policy of the power state and virtual media waits
+type ResetConfig struct {
+	// OffType is the ResetType of power-off: GracefulShutdown,
+	// PushPowerButton or ForceOff; auto (the default) shuts a system that is
+	// on down gracefully and forces it off after GracefulTimeout
+	OffType string `yaml:"off_type,omitempty"`
+	// RestartType is ForceRestart, GracefulRestart or PowerCycle
+	RestartType string `yaml:"restart_type,omitempty"`
+	// GracefulTimeout is how long in seconds a graceful shutdown may take
+	// before the system is forced off
+	GracefulTimeout int `yaml:"graceful_timeout"`
+	// WaitInterval is the first delay in seconds between power state and
+	// virtual media polls; it doubles after every poll up to WaitMaxInterval
+	WaitInterval    int `yaml:"wait_interval"`
+	WaitMaxInterval int `yaml:"wait_max_interval"`
+	// WaitTimeout is how long in seconds to wait for a power state or
+	// virtual media change
+	WaitTimeout int `yaml:"wait_timeout"`
+}
+
+// MetricsConfig holds thermal and power telemetry configuration
+type MetricsConfig struct {
+	// Listen is the address of the Prometheus endpoint served by the metrics command
+	Listen string `yaml:"listen,omitempty"`
+	// Interval is the telemetry poll interval in seconds
+	Interval int `yaml:"interval,omitempty"`
+	// Trends logs inlet temperature and power while waiting for the install to complete
+	Trends bool `yaml:"trends"`
+	// TrendInterval is the trend logging interval in seconds
+	TrendInterval int `yaml:"trend_interval,omitempty"`
 }
 
 // OpenShiftConfig holds OpenShift-specific configuration
@@ -46,16 +215,44 @@ type PathsConfig struct {
 	SourceDir   string `yaml:"source_dir"`
 	SSHKeyPath  string `yaml:"ssh_key_path"`
 	InstallerPath string `yaml:"installer_path"`
+	// StateFile records the progress of install for --resume and --from-step
+	StateFile string `yaml:"state_file,omitempty"`
 }
 
 // DefaultConfig returns a default configuration
 func DefaultConfig() *Config {
 	return &Config{
 		IDRAC: IDRACConfig{
-			IP:        "192.168.1.228",
-			Username:  "root",
-			VerifySSL: false,
-			Timeout:   30,
+			IP:         "192.168.1.228",
+			Username:   "root",
+			VerifySSL:  false,
+			Timeout:    30,
+			AuthMethod: "session",
+		},
+		BMC: BMCConfig{
+			Vendor: "auto",
+		},
+		Storage: StorageConfig{
+			Mode:       "raid",
+			RAIDType:   "RAID1",
+			VolumeName: "sno-boot",
+		},
+		Events: EventsConfig{
+			Mode:   "auto",
+			Listen: ":8443",
+		},
+		Reset: ResetConfig{
+			OffType:         "auto",
+			RestartType:     "ForceRestart",
+			GracefulTimeout: 300,
+			WaitInterval:    10,
+			WaitMaxInterval: 60,
+			WaitTimeout:     300,
+		},
+		Metrics: MetricsConfig{
+			Listen:        ":9610",
+			Interval:      30,
+			TrendInterval: 300,
 		},
 		OpenShift: OpenShiftConfig{
 			Version:     "4.16.45",
@@ -73,13 +270,17 @@ func DefaultConfig() *Config {
 			SourceDir:     "./abi-master-0",
 			SSHKeyPath:    os.Getenv("HOME") + "/.ssh/id_ed25519.pub",
 			InstallerPath: "./openshift-install",
+			StateFile:     "./install-state.json",
 		},
 	}
 }
 
-// LoadConfig loads configuration from file or creates default
-func LoadConfig() (*Config, error) {
-	configFile := "idrac_config.yaml"
+// DefaultConfigFile is the configuration file used when --config is not given
+const DefaultConfigFile = "idrac_config.yaml"
+
+// LoadConfig loads configuration from configFile, creating a default one
+// when it does not exist
+func LoadConfig(configFile string) (*Config, error) {
 	
 	// Check if config file exists
 	if _, err := os.Stat(configFile); os.IsNotExist(err) {
@@ -140,6 +341,80 @@ func (c *Config) Validate() error {
 	if c.IDRAC.Password == "" {
 		return fmt.Errorf("idrac.password is required")
 	}
+	if c.IDRAC.AuthMethod != "" && c.IDRAC.AuthMethod != "session" && c.IDRAC.AuthMethod != "basic" {
+		return fmt.Errorf("idrac.auth_method must be session or basic, got %q", c.IDRAC.AuthMethod)
+	}
+	if c.IDRAC.Fingerprint != "" {
+		raw, err := hex.DecodeString(strings.ReplaceAll(strings.TrimPrefix(c.IDRAC.Fingerprint, "sha256:"), ":", ""))
+		if err != nil || len(raw) != 32 {
+			return fmt.Errorf("idrac.fingerprint must be a SHA-256 fingerprint in hex, got %q", c.IDRAC.Fingerprint)
+		}
+		if c.IDRAC.TrustOnFirstUse {
+			return fmt.Errorf("idrac.fingerprint and idrac.trust_on_first_use are mutually exclusive")
+		}
+	}
+	if c.IDRAC.TaskTimeout < 0 {
+		return fmt.Errorf("idrac.task_timeout must not be negative")
+	}
+	switch c.BMC.Vendor {
+	case "", "auto", "dell", "hpe", "supermicro", "redfish":
+	default:
+		return fmt.Errorf("bmc.vendor must be one of auto, dell, hpe, supermicro, redfish, got %q", c.BMC.Vendor)
+	}
+	if id := c.BMC.Network.VLANID; id != nil && (*id < 0 || *id > 4094) {
+		return fmt.Errorf("bmc.network.vlan_id must be between 0 and 4094, got %d", *id)
+	}
+	if c.Firmware.Method != "" && c.Firmware.Method != "simple" && c.Firmware.Method != "multipart" {
+		return fmt.Errorf("firmware.method must be simple or multipart, got %q", c.Firmware.Method)
+	}
+	if c.Firmware.Enforce && c.Firmware.Manifest == "" {
+		return fmt.Errorf("firmware.manifest is required when firmware.enforce is set")
+	}
+	switch c.Events.Mode {
+	case "", "auto", "sse", "push":
+	default:
+		return fmt.Errorf("events.mode must be auto, sse or push, got %q", c.Events.Mode)
+	}
+	if (c.Events.CertFile == "") != (c.Events.KeyFile == "") {
+		return fmt.Errorf("events.cert_file and events.key_file must be set together")
+	}
+	if c.Power.LimitWatts != nil && *c.Power.LimitWatts < 0 {
+		return fmt.Errorf("power.limit_watts must not be negative")
+	}
+	switch c.Power.LimitException {
+	case "", "NoAction", "HardPowerOff", "LogEventOnly", "Oem":
+	default:
+		return fmt.Errorf("power.limit_exception must be NoAction, HardPowerOff, LogEventOnly or Oem, got %q", c.Power.LimitException)
+	}
+	switch c.Reset.OffType {
+	case "", "auto", "GracefulShutdown", "PushPowerButton", "ForceOff":
+	default:
+		return fmt.Errorf("reset.off_type must be auto, GracefulShutdown, PushPowerButton or ForceOff, got %q", c.Reset.OffType)
+	}
+	switch c.Reset.RestartType {
+	case "", "ForceRestart", "GracefulRestart", "PowerCycle":
+	default:
+		return fmt.Errorf("reset.restart_type must be ForceRestart, GracefulRestart or PowerCycle, got %q", c.Reset.RestartType)
+	}
+	if c.Reset.GracefulTimeout < 0 || c.Reset.WaitInterval < 0 || c.Reset.WaitMaxInterval < 0 || c.Reset.WaitTimeout < 0 {
+		return fmt.Errorf("reset timeouts and intervals must not be negative")
+	}
+	if c.Metrics.Interval < 0 || c.Metrics.TrendInterval < 0 {
+		return fmt.Errorf("metrics.interval and metrics.trend_interval must not be negative")
+	}
+	if c.BIOS.Enforce && c.BIOS.Profile == "" {
+		return fmt.Errorf("bios.profile is required when bios.enforce is set")
+	}
+	switch c.Storage.Mode {
+	case "", "raid", "non-raid":
+	default:
+		return fmt.Errorf("storage.mode must be raid or non-raid, got %q", c.Storage.Mode)
+	}
+	switch c.Storage.RAIDType {
+	case "", "RAID0", "RAID1":
+	default:
+		return fmt.Errorf("storage.raid_type must be RAID0 or RAID1, got %q", c.Storage.RAIDType)
+	}
 	if c.OpenShift.Version == "" {
 		return fmt.Errorf("openshift.version is required")
 	}
@@ -155,6 +430,14 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// GetFirmwareBaseURL returns the URL the BMC downloads update packages from
+func (c *Config) GetFirmwareBaseURL() string {
+	if c.Firmware.BaseURL != "" {
+		return strings.TrimSuffix(c.Firmware.BaseURL, "/") + "/"
+	}
+	return c.Remote.ISOURL[:strings.LastIndex(c.Remote.ISOURL, "/")+1] + "firmware/"
+}
+
 // GetISOFilePath returns the full path to the ISO file
 func (c *Config) GetISOFilePath() string {
 	return filepath.Join(c.Paths.WorkDir, "agent.x86_64.iso")
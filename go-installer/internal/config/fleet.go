@@ -0,0 +1,208 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFleetFile is the fleet inventory used when --inventory is not given
+const DefaultFleetFile = "fleet.yaml"
+
+// DefaultFleetDir holds the site directories when the inventory sets no dir
+const DefaultFleetDir = "./fleet"
+
+// siteNamePattern limits site names to what is safe as a directory name
+var siteNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Fleet is an inventory of SNO sites installed by fleet install
+type Fleet struct {
+	// BaseConfig is the configuration file the sites share; empty means the
+	// --config file
+	BaseConfig string `yaml:"base_config,omitempty"`
+	// Dir holds a directory per site with its workdir, installer, install
+	// state and logs
+	Dir string `yaml:"dir,omitempty"`
+	// Concurrency is how many sites install at once; 0 leaves it to the
+	// command line
+	Concurrency int         `yaml:"concurrency,omitempty"`
+	Sites       []FleetSite `yaml:"sites"`
+}
+
+// FleetSite is a site of the fleet: a BMC, an agent-based installer source
+// directory, the ISO URL the BMC boots from and a cluster name
+type FleetSite struct {
+	Name string `yaml:"name"`
+	// ClusterName is the metadata.name of the install-config.yaml in
+	// SourceDir; fleet install refuses a site where they differ (default:
+	// metadata.name)
+	ClusterName string `yaml:"cluster_name,omitempty"`
+	SourceDir   string `yaml:"source_dir"`
+	ISOURL      string `yaml:"iso_url"`
+	// IDRAC is merged over the idrac section of the base configuration,
+	// usually with ip, username and password
+	IDRAC yaml.Node `yaml:"idrac,omitempty"`
+}
+
+// LoadFleet loads and validates the fleet inventory in path
+func LoadFleet(path string) (*Fleet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fleet inventory: %w", err)
+	}
+
+	var fleet Fleet
+	if err := yaml.Unmarshal(data, &fleet); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fleet inventory: %w", err)
+	}
+	if fleet.Dir == "" {
+		fleet.Dir = DefaultFleetDir
+	}
+
+	if err := fleet.Validate(); err != nil {
+		return nil, fmt.Errorf("fleet inventory validation failed: %w", err)
+	}
+	return &fleet, nil
+}
+
+// Validate validates the fleet inventory
+func (f *Fleet) Validate() error {
+	if len(f.Sites) == 0 {
+		return fmt.Errorf("sites is empty")
+	}
+	if f.Concurrency < 0 {
+		return fmt.Errorf("concurrency must not be negative")
+	}
+
+	names := make(map[string]bool)
+	isoURLs := make(map[string]string)
+	for i, site := range f.Sites {
+		if !siteNamePattern.MatchString(site.Name) {
+			return fmt.Errorf("sites[%d].name must be letters, digits, '.', '_' or '-', got %q", i, site.Name)
+		}
+		if names[site.Name] {
+			return fmt.Errorf("site %s is listed twice", site.Name)
+		}
+		names[site.Name] = true
+
+		if site.SourceDir == "" {
+			return fmt.Errorf("site %s: source_dir is required", site.Name)
+		}
+		if site.ISOURL == "" {
+			return fmt.Errorf("site %s: iso_url is required", site.Name)
+		}
+		// The ISO is uploaded under the name in its URL, a shared URL
+		// would let the sites overwrite each other's ISO
+		if other, ok := isoURLs[site.ISOURL]; ok {
+			return fmt.Errorf("sites %s and %s have the same iso_url %s", other, site.Name, site.ISOURL)
+		}
+		isoURLs[site.ISOURL] = site.Name
+
+		if !site.IDRAC.IsZero() && site.IDRAC.Kind != yaml.MappingNode {
+			return fmt.Errorf("site %s: idrac must be a mapping", site.Name)
+		}
+	}
+	return nil
+}
+
+// SiteDir returns the directory of the files of site
+func (f *Fleet) SiteDir(site *FleetSite) string {
+	return filepath.Join(f.Dir, site.Name)
+}
+
+// SiteConfig returns the configuration of site: the base configuration with
+// the site settings applied, the workdir, installer and install state in the
+// site directory, and an event listener port of its own
+func (f *Fleet) SiteConfig(site *FleetSite) (*Config, error) {
+	data, err := os.ReadFile(f.BaseConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read base config: %w", err)
+	}
+
+	// Read for every site, so the sites share no slices or pointers
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal base config: %w", err)
+	}
+
+	if !site.IDRAC.IsZero() {
+		if err := site.IDRAC.Decode(&config.IDRAC); err != nil {
+			return nil, fmt.Errorf("site %s: invalid idrac section: %w", site.Name, err)
+		}
+	}
+	if site.ClusterName != "" {
+		config.OpenShift.ClusterName = site.ClusterName
+	}
+	config.Paths.SourceDir = site.SourceDir
+	config.Remote.ISOURL = site.ISOURL
+
+	dir := f.SiteDir(site)
+	config.Paths.WorkDir = filepath.Join(dir, "workdir")
+	config.Paths.InstallerPath = filepath.Join(dir, "openshift-install")
+	config.Paths.StateFile = filepath.Join(dir, "install-state.json")
+
+	// Sites installing at once cannot share the push event listener
+	if err := offsetEventsPort(&config.Events, f.siteIndex(site)); err != nil {
+		return nil, fmt.Errorf("site %s: %w", site.Name, err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("site %s: configuration validation failed: %w", site.Name, err)
+	}
+	return &config, nil
+}
+
+// siteIndex returns the position of site in the inventory
+func (f *Fleet) siteIndex(site *FleetSite) int {
+	for i := range f.Sites {
+		if f.Sites[i].Name == site.Name {
+			return i
+		}
+	}
+	return 0
+}
+
+// offsetEventsPort adds offset to the port of events.listen and, when set,
+// of events.destination. Port 0 lets the system pick a free port and is kept.
+func offsetEventsPort(events *EventsConfig, offset int) error {
+	if offset == 0 || events.Listen == "" {
+		return nil
+	}
+
+	host, port, err := net.SplitHostPort(events.Listen)
+	if err != nil {
+		return fmt.Errorf("invalid events.listen %q: %w", events.Listen, err)
+	}
+	listenPort, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("invalid events.listen %q: port must be a number", events.Listen)
+	}
+	if listenPort == 0 {
+		return nil
+	}
+	if listenPort+offset > 65535 {
+		return fmt.Errorf("events.listen port %d leaves no port for every site", listenPort)
+	}
+	events.Listen = net.JoinHostPort(host, strconv.Itoa(listenPort+offset))
+
+	if events.Destination == "" {
+		return nil
+	}
+	destination, err := url.Parse(events.Destination)
+	if err != nil {
+		return fmt.Errorf("invalid events.destination %q: %w", events.Destination, err)
+	}
+	destinationPort, err := strconv.Atoi(destination.Port())
+	if err != nil {
+		return fmt.Errorf("events.destination %q needs an explicit port to give every site its own", events.Destination)
+	}
+	destination.Host = net.JoinHostPort(destination.Hostname(), strconv.Itoa(destinationPort+offset))
+	events.Destination = destination.String()
+	return nil
+}
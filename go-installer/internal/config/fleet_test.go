@@ -0,0 +1,248 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFleet(t *testing.T) {
+	tests := []struct {
+		name      string
+		inventory string
+		wantErr   string
+	}{
+		{
+			name: "valid",
+			inventory: `
+sites:
+  - name: edge-01
+    source_dir: ./abi-edge-01
+    iso_url: http://10.0.0.5/edge-01/agent.x86_64.iso
+  - name: edge-02
+    source_dir: ./abi-edge-02
+    iso_url: http://10.0.0.5/edge-02/agent.x86_64.iso
+`,
+		},
+		{
+			name:      "no sites",
+			inventory: "concurrency: 2\n",
+			wantErr:   "sites is empty",
+		},
+		{
+			name: "duplicate name",
+			inventory: `
+sites:
+  - name: edge-01
+    source_dir: ./abi-a
+    iso_url: http://10.0.0.5/a.iso
+  - name: edge-01
+    source_dir: ./abi-b
+    iso_url: http://10.0.0.5/b.iso
+`,
+			wantErr: "site edge-01 is listed twice",
+		},
+		{
+			name: "duplicate iso_url",
+			inventory: `
+sites:
+  - name: edge-01
+    source_dir: ./abi-a
+    iso_url: http://10.0.0.5/agent.x86_64.iso
+  - name: edge-02
+    source_dir: ./abi-b
+    iso_url: http://10.0.0.5/agent.x86_64.iso
+`,
+			wantErr: "sites edge-01 and edge-02 have the same iso_url",
+		},
+		{
+			name: "unsafe name",
+			inventory: `
+sites:
+  - name: ../edge-01
+    source_dir: ./abi-a
+    iso_url: http://10.0.0.5/a.iso
+`,
+			wantErr: "sites[0].name must be",
+		},
+		{
+			name: "missing source_dir",
+			inventory: `
+sites:
+  - name: edge-01
+    iso_url: http://10.0.0.5/a.iso
+`,
+			wantErr: "site edge-01: source_dir is required",
+		},
+		{
+			name: "idrac not a mapping",
+			inventory: `
+sites:
+  - name: edge-01
+    source_dir: ./abi-a
+    iso_url: http://10.0.0.5/a.iso
+    idrac: 10.0.1.10
+`,
+			wantErr: "site edge-01: idrac must be a mapping",
+		},
+		{
+			name:      "negative concurrency",
+			inventory: "concurrency: -1\nsites:\n  - name: a\n    source_dir: ./a\n    iso_url: http://h/a.iso\n",
+			wantErr:   "concurrency must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), DefaultFleetFile)
+			if err := os.WriteFile(path, []byte(tt.inventory), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			fleet, err := LoadFleet(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadFleet failed: %v", err)
+			}
+			if fleet.Dir != DefaultFleetDir || len(fleet.Sites) != 2 {
+				t.Errorf("Expected 2 sites in %s, got %d in %s", DefaultFleetDir, len(fleet.Sites), fleet.Dir)
+			}
+		})
+	}
+}
+
+func TestOffsetEventsPort(t *testing.T) {
+	tests := []struct {
+		name            string
+		events          EventsConfig
+		offset          int
+		wantListen      string
+		wantDestination string
+		wantErr         bool
+	}{
+		{"first site", EventsConfig{Listen: ":8443"}, 0, ":8443", "", false},
+		{"listen port", EventsConfig{Listen: ":8443"}, 3, ":8446", "", false},
+		{"listen address", EventsConfig{Listen: "10.0.0.5:9000"}, 1, "10.0.0.5:9001", "", false},
+		{"destination", EventsConfig{Listen: ":8443", Destination: "https://hub.example.com:443/events"}, 2, ":8445", "https://hub.example.com:445/events", false},
+		{"system picked port", EventsConfig{Listen: ":0"}, 2, ":0", "", false},
+		{"destination without port", EventsConfig{Listen: ":8443", Destination: "https://hub.example.com/events"}, 1, "", "", true},
+		{"port out of range", EventsConfig{Listen: ":65535"}, 1, "", "", true},
+		{"invalid listen", EventsConfig{Listen: "8443"}, 1, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := tt.events
+			err := offsetEventsPort(&events, tt.offset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if events.Listen != tt.wantListen || events.Destination != tt.wantDestination {
+				t.Errorf("Expected %s and %q, got %s and %q", tt.wantListen, tt.wantDestination, events.Listen, events.Destination)
+			}
+		})
+	}
+}
+
+func TestSiteConfig(t *testing.T) {
+	dir := t.TempDir()
+	base := DefaultConfig()
+	base.IDRAC.Password = "calvin"
+	base.IDRAC.Timeout = 45
+	base.OpenShift.ClusterName = "hub"
+	base.Paths.WorkDir = "./workdir"
+	base.Events.Destination = "https://10.0.0.5:8443/redfish/events"
+	basePath := filepath.Join(dir, "base.yaml")
+	if err := base.Save(basePath); err != nil {
+		t.Fatal(err)
+	}
+
+	inventoryPath := filepath.Join(dir, DefaultFleetFile)
+	inventory := `
+base_config: ` + basePath + `
+dir: ` + filepath.Join(dir, "sites") + `
+sites:
+  - name: edge-01
+    source_dir: ./abi-edge-01
+    iso_url: http://10.0.0.5/edge-01/agent.x86_64.iso
+    idrac:
+      ip: 10.0.1.10
+      password: s3cret
+  - name: edge-02
+    cluster_name: store-42
+    source_dir: ./abi-edge-02
+    iso_url: http://10.0.0.5/edge-02/agent.x86_64.iso
+  - name: edge-03
+    source_dir: ./abi-edge-03
+    iso_url: http://10.0.0.5/edge-03/agent.x86_64.iso
+    idrac:
+      timeout: sixty
+`
+	if err := os.WriteFile(inventoryPath, []byte(inventory), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fleet, err := LoadFleet(inventoryPath)
+	if err != nil {
+		t.Fatalf("LoadFleet failed: %v", err)
+	}
+
+	cfg, err := fleet.SiteConfig(&fleet.Sites[0])
+	if err != nil {
+		t.Fatalf("SiteConfig failed: %v", err)
+	}
+	// The site idrac section is merged over the base one
+	if cfg.IDRAC.IP != "10.0.1.10" || cfg.IDRAC.Password != "s3cret" ||
+		cfg.IDRAC.Username != base.IDRAC.Username || cfg.IDRAC.Timeout != 45 {
+		t.Errorf("Unexpected idrac section: %+v", cfg.IDRAC)
+	}
+	siteDir := filepath.Join(dir, "sites", "edge-01")
+	if cfg.Paths.WorkDir != filepath.Join(siteDir, "workdir") ||
+		cfg.Paths.InstallerPath != filepath.Join(siteDir, "openshift-install") ||
+		cfg.Paths.StateFile != filepath.Join(siteDir, "install-state.json") {
+		t.Errorf("Expected the site files in %s, got %+v", siteDir, cfg.Paths)
+	}
+	if cfg.Paths.SourceDir != "./abi-edge-01" || cfg.Remote.ISOURL != "http://10.0.0.5/edge-01/agent.x86_64.iso" {
+		t.Errorf("Unexpected site source: %s, %s", cfg.Paths.SourceDir, cfg.Remote.ISOURL)
+	}
+	if cfg.OpenShift.ClusterName != "hub" {
+		t.Errorf("Expected the cluster name of the base configuration, got %s", cfg.OpenShift.ClusterName)
+	}
+	if cfg.Events.Listen != ":8443" || cfg.Events.Destination != base.Events.Destination {
+		t.Errorf("Expected the first site to keep the event listener, got %s, %s", cfg.Events.Listen, cfg.Events.Destination)
+	}
+
+	// Every site listens for pushed events on a port of its own
+	second, err := fleet.SiteConfig(&fleet.Sites[1])
+	if err != nil {
+		t.Fatalf("SiteConfig failed: %v", err)
+	}
+	if second.Events.Listen != ":8444" || second.Events.Destination != "https://10.0.0.5:8444/redfish/events" {
+		t.Errorf("Expected the second site to listen on port 8444, got %s, %s", second.Events.Listen, second.Events.Destination)
+	}
+	if second.OpenShift.ClusterName != "store-42" {
+		t.Errorf("Expected cluster name store-42, got %s", second.OpenShift.ClusterName)
+	}
+
+	// Every site gets a configuration of its own
+	other, err := fleet.SiteConfig(&fleet.Sites[0])
+	if err != nil {
+		t.Fatalf("SiteConfig failed: %v", err)
+	}
+	other.IDRAC.IP = "10.0.9.9"
+	if cfg.IDRAC.IP != "10.0.1.10" {
+		t.Error("Expected site configurations not to share state")
+	}
+
+	if _, err := fleet.SiteConfig(&fleet.Sites[2]); err == nil || !strings.Contains(err.Error(), "site edge-03: invalid idrac section") {
+		t.Errorf("Expected the invalid idrac section of edge-03 to be reported, got %v", err)
+	}
+}
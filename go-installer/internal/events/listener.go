@@ -0,0 +1,198 @@
+package events
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"openshift-sno-hub-installer/internal/config"
+	"openshift-sno-hub-installer/internal/idrac"
+	"openshift-sno-hub-installer/internal/logger"
+)
+
+// eventPath is the path the BMC posts events to
+const eventPath = "/events"
+
+// maxEventSize bounds the body of a pushed event
+const maxEventSize = 1 << 20
+
+// Listener is the HTTPS endpoint of a Redfish push event subscription. It
+// publishes every event carrying the context of its subscription on a hub.
+type Listener struct {
+	config *config.EventsConfig
+	logger *logger.Logger
+	hub    *idrac.EventHub
+	server *http.Server
+
+	// token is the random part of the subscription context, see Token
+	token string
+}
+
+// NewListener creates a listener publishing the received events on hub
+func NewListener(cfg *config.EventsConfig, hub *idrac.EventHub, log *logger.Logger) *Listener {
+	return &Listener{
+		config: cfg,
+		logger: log,
+		hub:    hub,
+	}
+}
+
+// Destination returns the URL the BMC at bmcAddress posts events to: the
+// configured destination, or the listen port on the local address that
+// reaches the BMC
+func Destination(cfg *config.EventsConfig, bmcAddress string) (string, error) {
+	if cfg.Destination != "" {
+		return cfg.Destination, nil
+	}
+
+	_, port, err := net.SplitHostPort(cfg.Listen)
+	if err != nil {
+		return "", fmt.Errorf("invalid events.listen %q: %w", cfg.Listen, err)
+	}
+
+	// Dialing UDP sends nothing, it only selects the outgoing interface
+	conn, err := net.Dial("udp", net.JoinHostPort(bmcAddress, "443"))
+	if err != nil {
+		return "", fmt.Errorf("failed to find the local address reaching %s: %w", bmcAddress, err)
+	}
+	defer conn.Close()
+
+	host := conn.LocalAddr().(*net.UDPAddr).IP.String()
+	return "https://" + net.JoinHostPort(host, port) + eventPath, nil
+}
+
+// Token returns the token to subscribe with; see idrac.EventContext
+func (l *Listener) Token() string {
+	return l.token
+}
+
+// Start serves HTTPS on the configured address in the background. destination
+// names the host in the self-signed certificate.
+func (l *Listener) Start(destination string) error {
+	certificate, err := l.certificate(destination)
+	if err != nil {
+		return err
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return fmt.Errorf("failed to generate event subscription token: %w", err)
+	}
+	l.token = hex.EncodeToString(token)
+
+	ln, err := net.Listen("tcp", l.config.Listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", l.config.Listen, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(eventPath, l.handleEvent)
+	l.server = &http.Server{
+		Handler:           mux,
+		TLSConfig:         &tls.Config{Certificates: []tls.Certificate{certificate}},
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := l.server.ServeTLS(ln, "", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			l.logger.LogError("BMC event listener failed: %v", err)
+		}
+	}()
+
+	l.logger.LogInfo("Listening for BMC events on %s", ln.Addr())
+	return nil
+}
+
+// Close stops the listener
+func (l *Listener) Close(ctx context.Context) error {
+	if l.server == nil {
+		return nil
+	}
+	return l.server.Shutdown(ctx)
+}
+
+// handleEvent publishes the events of a pushed Event resource. Anyone able
+// to reach the listener can post to it, so events without the context of the
+// subscription are rejected.
+func (l *Listener) handleEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxEventSize))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	subscription, events, err := idrac.ParsePushedEvents(data)
+	if err != nil {
+		l.logger.LogDebug("Ignoring event from %s: %v", r.RemoteAddr, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	expected := idrac.EventContext(l.token)
+	if l.token == "" || subtle.ConstantTimeCompare([]byte(subscription), []byte(expected)) != 1 {
+		l.logger.LogWarn("Rejected event from %s without the subscription context", r.RemoteAddr)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	for _, event := range events {
+		l.hub.Publish(event)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// certificate loads the configured certificate or generates a self-signed one
+// for the host of destination
+func (l *Listener) certificate(destination string) (tls.Certificate, error) {
+	if l.config.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(l.config.CertFile, l.config.KeyFile)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to load event listener certificate: %w", err)
+		}
+		return certificate, nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to generate event listener key: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "openshift-sno-hub-installer events"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(7 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if u, err := url.Parse(destination); err == nil {
+		if ip := net.ParseIP(u.Hostname()); ip != nil {
+			template.IPAddresses = []net.IP{ip}
+		} else if u.Hostname() != "" {
+			template.DNSNames = []string{u.Hostname()}
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to create event listener certificate: %w", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
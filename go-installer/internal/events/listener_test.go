@@ -0,0 +1,60 @@
+package events
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"openshift-sno-hub-installer/internal/config"
+	"openshift-sno-hub-installer/internal/idrac"
+	"openshift-sno-hub-installer/internal/logger"
+)
+
+func TestHandleEvent(t *testing.T) {
+	log := logger.NewFileLogger(t.TempDir())
+	defer log.Close()
+
+	hub := idrac.NewEventHub()
+	received, unsubscribe := hub.Subscribe()
+	defer unsubscribe()
+
+	listener := NewListener(&config.EventsConfig{}, hub, log)
+	listener.token = "secret"
+
+	tests := []struct {
+		name    string
+		method  string
+		body    string
+		want    int
+		publish bool
+	}{
+		{"subscription context", "POST", `{"Context":"openshift-sno-hub-installer secret","Events":[{"MessageId":"SYS1000"}]}`, http.StatusNoContent, true},
+		{"context in the event record", "POST", `{"Events":[{"MessageId":"SYS1000","Context":"openshift-sno-hub-installer secret"}]}`, http.StatusNoContent, true},
+		{"missing context", "POST", `{"Events":[{"MessageId":"SYS1000"}]}`, http.StatusForbidden, false},
+		{"wrong token", "POST", `{"Context":"openshift-sno-hub-installer guess","Events":[{"MessageId":"SYS1000"}]}`, http.StatusForbidden, false},
+		{"not an event", "POST", `<html>`, http.StatusBadRequest, false},
+		{"not a post", "GET", ``, http.StatusMethodNotAllowed, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			listener.handleEvent(w, httptest.NewRequest(tt.method, eventPath, strings.NewReader(tt.body)))
+			if w.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, w.Code)
+			}
+
+			select {
+			case event := <-received:
+				if !tt.publish {
+					t.Errorf("Expected no event to be published, got %+v", event)
+				}
+			case <-time.After(50 * time.Millisecond):
+				if tt.publish {
+					t.Error("Expected the event to be published")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package idrac
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ManagerAccount represents a Redfish ManagerAccount resource
+type ManagerAccount struct {
+	ODataID  string `json:"@odata.id"`
+	ID       string `json:"Id"`
+	UserName string `json:"UserName"`
+	RoleID   string `json:"RoleId"`
+	Enabled  bool   `json:"Enabled"`
+	Locked   bool   `json:"Locked"`
+}
+
+// accountsURI returns the Accounts collection of the AccountService
+func (c *Client) accountsURI(ctx context.Context) (string, error) {
+	res, err := c.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var service struct {
+		Accounts ODataLink `json:"Accounts"`
+	}
+	accountService := linkOrDefault(res.Root.AccountService, "/AccountService")
+	if err := c.getJSON(ctx, accountService, &service); err != nil {
+		return "", fmt.Errorf("failed to get account service: %w", err)
+	}
+	return valueOr(service.Accounts.ODataID, accountService+"/Accounts"), nil
+}
+
+// GetAccounts lists the BMC accounts. iDRAC reports all 16 account slots,
+// unused ones with an empty user name.
+func (c *Client) GetAccounts(ctx context.Context) ([]ManagerAccount, error) {
+	uri, err := c.accountsURI(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []ManagerAccount
+	if err := collectMembers(ctx, c, ODataLink{ODataID: uri}, &accounts); err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+// FindAccount returns the account named userName
+func (c *Client) FindAccount(ctx context.Context, userName string) (*ManagerAccount, error) {
+	accounts, err := c.GetAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range accounts {
+		if accounts[i].UserName == userName {
+			return &accounts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("account %s not found", userName)
+}
+
+// SetAccountPassword changes the password of the account named userName.
+// When it is the account the client logs in with, the client switches to the
+// new password.
+func (c *Client) SetAccountPassword(ctx context.Context, userName, password string) error {
+	account, err := c.FindAccount(ctx, userName)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.makeRequest(ctx, "PATCH", account.ODataID, map[string]string{"Password": password})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) {
+		return fmt.Errorf("failed to set password of %s: %w", userName, newRedfishError(resp))
+	}
+
+	if userName == c.config.Username {
+		c.config.Password = password
+		c.sessionMu.Lock()
+		c.authToken, c.sessionURI = "", ""
+		c.sessionMu.Unlock()
+	}
+	return nil
+}
+
+// CreateAccount creates an enabled account with the given role. iDRAC has a
+// fixed set of account slots, so the first free slot above slot 2 (slot 1 is
+// reserved, slot 2 holds root) is filled in; other BMCs get a POST to the
+// Accounts collection.
+func (c *Client) CreateAccount(ctx context.Context, userName, password, role string) (*ManagerAccount, error) {
+	accounts, err := c.GetAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range accounts {
+		if account.UserName == userName {
+			return nil, fmt.Errorf("account %s already exists (%s)", userName, account.ODataID)
+		}
+	}
+
+	body := map[string]interface{}{
+		"UserName": userName,
+		"Password": password,
+		"RoleId":   role,
+		"Enabled":  true,
+	}
+
+	if c.isDell(ctx) {
+		for _, account := range accounts {
+			if account.UserName != "" || account.ID == "1" || account.ID == "2" {
+				continue
+			}
+			if err := c.patchAccount(ctx, account.ODataID, body); err != nil {
+				return nil, err
+			}
+			account.UserName, account.RoleID, account.Enabled = userName, role, true
+			return &account, nil
+		}
+		return nil, fmt.Errorf("no free iDRAC account slot for %s", userName)
+	}
+
+	uri, err := c.accountsURI(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.makeRequest(ctx, "POST", uri, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to create account %s: %w", userName, newRedfishError(resp))
+	}
+	return &ManagerAccount{
+		ODataID:  uriPath(resp.Header.Get("Location")),
+		UserName: userName,
+		RoleID:   role,
+		Enabled:  true,
+	}, nil
+}
+
+// SetAccountEnabled enables or disables the account named userName
+func (c *Client) SetAccountEnabled(ctx context.Context, userName string, enabled bool) error {
+	if strings.EqualFold(userName, c.config.Username) && !enabled {
+		return fmt.Errorf("refusing to disable %s, the account the installer logs in with", userName)
+	}
+
+	account, err := c.FindAccount(ctx, userName)
+	if err != nil {
+		return err
+	}
+	return c.patchAccount(ctx, account.ODataID, map[string]interface{}{"Enabled": enabled})
+}
+
+// patchAccount sends a PATCH to an account
+func (c *Client) patchAccount(ctx context.Context, uri string, body map[string]interface{}) error {
+	resp, err := c.makeRequest(ctx, "PATCH", uri, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) {
+		return fmt.Errorf("failed to update account %s: %w", uri, newRedfishError(resp))
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package idrac
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BIOSProfile declares the BIOS attribute values a host is expected to have
+type BIOSProfile struct {
+	Name       string                 `yaml:"name"`
+	Attributes map[string]interface{} `yaml:"attributes"`
+}
+
+// BIOSDifference describes a BIOS attribute whose current value differs from the profile
+type BIOSDifference struct {
+	Name    string
+	Current interface{}
+	Desired interface{}
+	// Missing is set when the attribute does not exist on this BIOS
+	Missing bool
+}
+
+// biosResource holds the BIOS properties used for attribute management
+type biosResource struct {
+	Attributes map[string]interface{} `json:"Attributes"`
+	Settings   struct {
+		SettingsObject ODataLink `json:"SettingsObject"`
+	} `json:"@Redfish.Settings"`
+}
+
+// LoadBIOSProfile reads a BIOS profile YAML file
+func LoadBIOSProfile(path string) (*BIOSProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read BIOS profile: %w", err)
+	}
+
+	var profile BIOSProfile
+	if err := yaml.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal BIOS profile: %w", err)
+	}
+	if len(profile.Attributes) == 0 {
+		return nil, fmt.Errorf("BIOS profile %s declares no attributes", path)
+	}
+
+	return &profile, nil
+}
+
+// getBIOS reads the BIOS resource of the system
+func (c *Client) getBIOS(ctx context.Context) (string, *biosResource, error) {
+	systemURI, err := c.SystemURI(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	biosURI := systemURI + "/Bios"
+	var bios biosResource
+	if err := c.getJSON(ctx, biosURI, &bios); err != nil {
+		return "", nil, fmt.Errorf("failed to get BIOS attributes: %w", err)
+	}
+	return biosURI, &bios, nil
+}
+
+// GetBIOSAttributes retrieves the current BIOS attributes
+func (c *Client) GetBIOSAttributes(ctx context.Context) (map[string]interface{}, error) {
+	_, bios, err := c.getBIOS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return bios.Attributes, nil
+}
+
+// GetPendingBIOSAttributes retrieves the BIOS attribute changes waiting for the next reset
+func (c *Client) GetPendingBIOSAttributes(ctx context.Context) (map[string]interface{}, error) {
+	biosURI, bios, err := c.getBIOS(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending biosResource
+	if err := c.getJSON(ctx, valueOr(bios.Settings.SettingsObject.ODataID, biosURI+"/Settings"), &pending); err != nil {
+		return nil, fmt.Errorf("failed to get pending BIOS attributes: %w", err)
+	}
+	return pending.Attributes, nil
+}
+
+// DiffBIOSAttributes compares the current attributes with the desired ones and
+// returns the differences sorted by attribute name
+func DiffBIOSAttributes(current, desired map[string]interface{}) []BIOSDifference {
+	var diffs []BIOSDifference
+	for name, want := range desired {
+		have, ok := current[name]
+		if !ok {
+			diffs = append(diffs, BIOSDifference{Name: name, Desired: want, Missing: true})
+			continue
+		}
+		if !biosValueEqual(have, want) {
+			diffs = append(diffs, BIOSDifference{Name: name, Current: have, Desired: want})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+	return diffs
+}
+
+// biosValueEqual compares attribute values decoded from JSON and YAML, where
+// numbers may come back as float64 or int
+func biosValueEqual(a, b interface{}) bool {
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// SetBIOSAttributes writes attributes to the pending BIOS settings. On iDRAC a
+// BIOS configuration job is scheduled to apply them on the next reset; other
+// BMCs apply pending BIOS settings on the next reset by themselves.
+func (c *Client) SetBIOSAttributes(ctx context.Context, attributes map[string]interface{}) (*PendingChange, error) {
+	biosURI, bios, err := c.getBIOS(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	c.logger.LogInfo("Setting BIOS attributes: %s", strings.Join(names, ", "))
+
+	change := &PendingChange{SettingsURI: valueOr(bios.Settings.SettingsObject.ODataID, biosURI+"/Settings")}
+	body := map[string]interface{}{"Attributes": attributes}
+
+	resp, err := c.makeRequest(ctx, "PATCH", change.SettingsURI, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) {
+		return nil, fmt.Errorf("failed to set BIOS attributes: %w", newRedfishError(resp))
+	}
+
+	if location := resp.Header.Get("Location"); strings.Contains(location, "/Jobs/") {
+		change.JobURI = uriPath(location)
+	} else if resp.StatusCode == http.StatusAccepted {
+		if err := c.completeAction(ctx, resp); err != nil {
+			return nil, err
+		}
+	} else if c.isDell(ctx) {
+		if change.JobURI, err = c.CreateConfigJob(ctx, change.SettingsURI); err != nil {
+			return nil, err
+		}
+	}
+
+	c.logger.LogSuccess("BIOS attributes written to %s", change.SettingsURI)
+	return change, nil
+}
@@ -0,0 +1,181 @@
+package idrac
+
+import (
+	"context"
+	"fmt"
+)
+
+// ManagerNetwork is the network configuration of the BMC itself
+type ManagerNetwork struct {
+	InterfaceURI  string
+	HostName      string
+	MACAddress    string
+	IPv4Addresses []string
+	NameServers   []string
+	VLANEnabled   bool
+	VLANID        int
+	NTPEnabled    bool
+	NTPServers    []string
+}
+
+// managerNetworkLinks holds the manager links to its network resources
+type managerNetworkLinks struct {
+	EthernetInterfaces ODataLink `json:"EthernetInterfaces"`
+	NetworkProtocol    ODataLink `json:"NetworkProtocol"`
+}
+
+// managerInterface represents an EthernetInterface of the manager
+type managerInterface struct {
+	HostName      string `json:"HostName"`
+	MACAddress    string `json:"MACAddress"`
+	IPv4Addresses []struct {
+		Address string `json:"Address"`
+	} `json:"IPv4Addresses"`
+	NameServers []string `json:"NameServers"`
+	VLAN        struct {
+		VLANEnable bool `json:"VLANEnable"`
+		VLANID     int  `json:"VLANId"`
+	} `json:"VLAN"`
+}
+
+// networkProtocol represents the ManagerNetworkProtocol resource
+type networkProtocol struct {
+	NTP struct {
+		ProtocolEnabled bool     `json:"ProtocolEnabled"`
+		NTPServers      []string `json:"NTPServers"`
+	} `json:"NTP"`
+	HTTPS struct {
+		Certificates ODataLink `json:"Certificates"`
+	} `json:"HTTPS"`
+}
+
+// managerNetworkURIs returns the first EthernetInterface and the
+// NetworkProtocol resource of the manager
+func (c *Client) managerNetworkURIs(ctx context.Context) (string, string, error) {
+	managerURI, err := c.ManagerURI(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	var manager managerNetworkLinks
+	if err := c.getJSON(ctx, managerURI, &manager); err != nil {
+		return "", "", fmt.Errorf("failed to get manager: %w", err)
+	}
+
+	interfaces, err := c.getCollection(ctx, valueOr(manager.EthernetInterfaces.ODataID, managerURI+"/EthernetInterfaces"))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list manager interfaces: %w", err)
+	}
+	iface, err := selectMember(interfaces, "")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to select manager interface: %w", err)
+	}
+	return iface, valueOr(manager.NetworkProtocol.ODataID, managerURI+"/NetworkProtocol"), nil
+}
+
+// GetManagerNetwork reads the address, DNS, VLAN and NTP settings of the BMC
+func (c *Client) GetManagerNetwork(ctx context.Context) (*ManagerNetwork, error) {
+	ifaceURI, protocolURI, err := c.managerNetworkURIs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var iface managerInterface
+	if err := c.getJSON(ctx, ifaceURI, &iface); err != nil {
+		return nil, fmt.Errorf("failed to get manager interface: %w", err)
+	}
+	var protocol networkProtocol
+	if err := c.getJSON(ctx, protocolURI, &protocol); err != nil {
+		return nil, fmt.Errorf("failed to get network protocol: %w", err)
+	}
+
+	network := &ManagerNetwork{
+		InterfaceURI: ifaceURI,
+		HostName:     iface.HostName,
+		MACAddress:   iface.MACAddress,
+		VLANEnabled:  iface.VLAN.VLANEnable,
+		VLANID:       iface.VLAN.VLANID,
+		NTPEnabled:   protocol.NTP.ProtocolEnabled,
+	}
+	for _, address := range iface.IPv4Addresses {
+		network.IPv4Addresses = append(network.IPv4Addresses, address.Address)
+	}
+	for _, server := range iface.NameServers {
+		if server != "" && server != "0.0.0.0" && server != "::" {
+			network.NameServers = append(network.NameServers, server)
+		}
+	}
+	for _, server := range protocol.NTP.NTPServers {
+		if server != "" {
+			network.NTPServers = append(network.NTPServers, server)
+		}
+	}
+	return network, nil
+}
+
+// SetNTPServers sets the NTP servers of the BMC; an empty list disables NTP
+func (c *Client) SetNTPServers(ctx context.Context, servers []string) error {
+	_, protocolURI, err := c.managerNetworkURIs(ctx)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]interface{}{
+		"NTP": map[string]interface{}{"ProtocolEnabled": len(servers) > 0, "NTPServers": servers},
+	}
+	return c.patchManagerNetwork(ctx, protocolURI, "NTP servers", body)
+}
+
+// SetDNSServers sets static DNS servers for the BMC. iDRAC takes them from
+// its IPv4Static attributes (at most two), other BMCs from the
+// StaticNameServers of the interface.
+func (c *Client) SetDNSServers(ctx context.Context, servers []string) error {
+	if c.isDell(ctx) {
+		if len(servers) > 2 {
+			return fmt.Errorf("iDRAC supports at most 2 static DNS servers, got %d", len(servers))
+		}
+		managerURI, err := c.ManagerURI(ctx)
+		if err != nil {
+			return err
+		}
+		attributes := map[string]string{"IPv4.1.DNSFromDHCP": "Disabled", "IPv4Static.1.DNS1": "0.0.0.0", "IPv4Static.1.DNS2": "0.0.0.0"}
+		for i, server := range servers {
+			attributes[fmt.Sprintf("IPv4Static.1.DNS%d", i+1)] = server
+		}
+		return c.patchManagerNetwork(ctx, managerURI+"/Attributes", "DNS servers", map[string]interface{}{"Attributes": attributes})
+	}
+
+	ifaceURI, _, err := c.managerNetworkURIs(ctx)
+	if err != nil {
+		return err
+	}
+	return c.patchManagerNetwork(ctx, ifaceURI, "DNS servers", map[string]interface{}{"StaticNameServers": servers})
+}
+
+// SetVLAN tags the BMC traffic with VLAN id; 0 disables VLAN tagging
+func (c *Client) SetVLAN(ctx context.Context, id int) error {
+	ifaceURI, _, err := c.managerNetworkURIs(ctx)
+	if err != nil {
+		return err
+	}
+
+	vlan := map[string]interface{}{"VLANEnable": id > 0}
+	if id > 0 {
+		vlan["VLANId"] = id
+	}
+	return c.patchManagerNetwork(ctx, ifaceURI, "VLAN", map[string]interface{}{"VLAN": vlan})
+}
+
+// patchManagerNetwork sends a PATCH changing a network setting of the BMC
+func (c *Client) patchManagerNetwork(ctx context.Context, uri, setting string, body map[string]interface{}) error {
+	resp, err := c.makeRequest(ctx, "PATCH", uri, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) {
+		return fmt.Errorf("failed to set BMC %s: %w", setting, newRedfishError(resp))
+	}
+	return nil
+}
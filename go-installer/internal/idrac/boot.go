@@ -0,0 +1,139 @@
+package idrac
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DellVirtualCDTargets lists the iDRAC virtual CD/DVD boot targets in order of preference
+var DellVirtualCDTargets = []string{"RemoteCd", "VirtualCd", "Cd"}
+
+// BootSettings represents the boot override state and its allowable values
+type BootSettings struct {
+	BootSourceOverrideTarget  string   `json:"BootSourceOverrideTarget"`
+	BootSourceOverrideEnabled string   `json:"BootSourceOverrideEnabled"`
+	BootSourceOverrideMode    string   `json:"BootSourceOverrideMode"`
+	AllowableTargets          []string `json:"BootSourceOverrideTarget@Redfish.AllowableValues"`
+	AllowableEnabled          []string `json:"BootSourceOverrideEnabled@Redfish.AllowableValues"`
+	AllowableModes            []string `json:"BootSourceOverrideMode@Redfish.AllowableValues"`
+}
+
+// BootOverride describes a requested boot source override. An empty Target
+// selects the virtual CD/DVD; an empty Mode leaves the boot mode unchanged.
+type BootOverride struct {
+	Target      string
+	Mode        string
+	Persistence string
+}
+
+// GetBootSettings retrieves the boot override settings of the system
+func (c *Client) GetBootSettings(ctx context.Context) (*BootSettings, error) {
+	systemURI, err := c.SystemURI(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var system struct {
+		Boot BootSettings `json:"Boot"`
+	}
+	if err := c.getJSON(ctx, systemURI, &system); err != nil {
+		return nil, fmt.Errorf("failed to get boot settings: %w", err)
+	}
+
+	return &system.Boot, nil
+}
+
+// ApplyBootOverride sets a boot source override and returns the target used.
+// Values are checked against the @Redfish.AllowableValues annotations; when
+// Target is empty the first allowable entry of virtualCDTargets is chosen, or,
+// on firmware without annotations, each one is tried until one is accepted.
+func (c *Client) ApplyBootOverride(ctx context.Context, o BootOverride, virtualCDTargets []string) (string, error) {
+	if o.Persistence == "" {
+		o.Persistence = "Once"
+	}
+
+	settings, err := c.GetBootSettings(ctx)
+	if err != nil {
+		c.logger.LogWarn("Could not read allowable boot values, trying blindly: %v", err)
+		settings = &BootSettings{}
+	}
+
+	if err := checkAllowed("BootSourceOverrideEnabled", o.Persistence, settings.AllowableEnabled); err != nil {
+		return "", err
+	}
+	if o.Mode != "" {
+		if err := checkAllowed("BootSourceOverrideMode", o.Mode, settings.AllowableModes); err != nil {
+			return "", err
+		}
+	}
+
+	candidates := []string{o.Target}
+	if o.Target == "" {
+		if len(settings.AllowableTargets) > 0 {
+			target, err := NegotiateBootTarget(settings.AllowableTargets, virtualCDTargets)
+			if err != nil {
+				return "", err
+			}
+			candidates = []string{target}
+		} else {
+			candidates = virtualCDTargets
+		}
+	} else if err := checkAllowed("BootSourceOverrideTarget", o.Target, settings.AllowableTargets); err != nil {
+		return "", err
+	}
+
+	for _, target := range candidates {
+		c.logger.LogInfo("Setting boot override: target=%s persistence=%s mode=%s", target, o.Persistence, valueOr(o.Mode, "unchanged"))
+
+		err := c.patchBoot(ctx, BootConfig{
+			BootSourceOverrideTarget:  target,
+			BootSourceOverrideEnabled: o.Persistence,
+			BootSourceOverrideMode:    o.Mode,
+		})
+		if err == nil {
+			return target, nil
+		}
+
+		// Only a rejected target value is worth retrying with the next option
+		if len(candidates) == 1 || !IsUnsupportedValue(err) {
+			return "", fmt.Errorf("failed to set boot device to %s: %w", target, err)
+		}
+		c.logger.LogWarn("Boot target %s is not supported by this BMC, skipping", target)
+	}
+
+	return "", fmt.Errorf("none of the boot targets %v is supported", candidates)
+}
+
+// NegotiateBootTarget returns the first preferred target the system allows
+func NegotiateBootTarget(allowable, preferred []string) (string, error) {
+	for _, want := range preferred {
+		for _, have := range allowable {
+			if strings.EqualFold(want, have) {
+				return have, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("none of the boot targets %v is allowed (allowable: %v)", preferred, allowable)
+}
+
+// checkAllowed verifies value against an allowable values annotation, when present
+func checkAllowed(property, value string, allowable []string) error {
+	if len(allowable) == 0 {
+		return nil
+	}
+	for _, v := range allowable {
+		if v == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %q is not allowed (allowable: %s)", property, value, strings.Join(allowable, ", "))
+}
+
+// valueOr returns value, or fallback when value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,197 @@
+package idrac
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// BootOption represents a Redfish BootOption resource
+type BootOption struct {
+	ID                  string `json:"Id"`
+	BootOptionReference string `json:"BootOptionReference"`
+	DisplayName         string `json:"DisplayName"`
+	Alias               string `json:"Alias"`
+	UefiDevicePath      string `json:"UefiDevicePath"`
+	BootOptionEnabled   *bool  `json:"BootOptionEnabled,omitempty"`
+}
+
+// String returns a human readable description of the boot option
+func (o BootOption) String() string {
+	name := o.DisplayName
+	if name == "" {
+		name = o.UefiDevicePath
+	}
+	return fmt.Sprintf("%s (%s)", o.BootOptionReference, name)
+}
+
+// bootOrderSystem holds the system properties used for boot order management
+type bootOrderSystem struct {
+	Boot struct {
+		BootOrder   []string  `json:"BootOrder"`
+		BootOptions ODataLink `json:"BootOptions"`
+	} `json:"Boot"`
+	Settings struct {
+		SettingsObject      ODataLink `json:"SettingsObject"`
+		SupportedApplyTimes []string  `json:"SupportedApplyTimes"`
+	} `json:"@Redfish.Settings"`
+}
+
+// PendingChange describes how a settings change was written and applied
+type PendingChange struct {
+	// SettingsURI is the resource the change was written to
+	SettingsURI string
+	// JobURI is the job or task that applies the change, if any
+	JobURI string
+	// Immediate is set when the job runs without waiting for a reset
+	Immediate bool
+}
+
+// GetBootOrder retrieves the persistent boot order and the boot options it references
+func (c *Client) GetBootOrder(ctx context.Context) ([]string, []BootOption, error) {
+	systemURI, err := c.SystemURI(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var system bootOrderSystem
+	if err := c.getJSON(ctx, systemURI, &system); err != nil {
+		return nil, nil, fmt.Errorf("failed to get boot order: %w", err)
+	}
+
+	var options []BootOption
+	if system.Boot.BootOptions.ODataID != "" {
+		members, err := c.getCollection(ctx, system.Boot.BootOptions.ODataID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list boot options: %w", err)
+		}
+		for _, uri := range members {
+			var option BootOption
+			if err := c.getJSON(ctx, uri, &option); err != nil {
+				return nil, nil, err
+			}
+			options = append(options, option)
+		}
+	}
+
+	return system.Boot.BootOrder, options, nil
+}
+
+// SetBootOrder writes a new persistent boot order. The change goes to the
+// system's pending settings object when one is advertised; on iDRAC a BIOS
+// configuration job is scheduled to apply it on the next reset.
+func (c *Client) SetBootOrder(ctx context.Context, order []string) (*PendingChange, error) {
+	c.logger.LogInfo("Setting persistent boot order: %s", strings.Join(order, ", "))
+
+	systemURI, err := c.SystemURI(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var system bootOrderSystem
+	if err := c.getJSON(ctx, systemURI, &system); err != nil {
+		return nil, fmt.Errorf("failed to get boot order: %w", err)
+	}
+
+	change := &PendingChange{SettingsURI: systemURI}
+	body := map[string]interface{}{
+		"Boot": map[string]interface{}{"BootOrder": order},
+	}
+	if uri := system.Settings.SettingsObject.ODataID; uri != "" {
+		change.SettingsURI = uri
+		if containsString(system.Settings.SupportedApplyTimes, "OnReset") {
+			body["@Redfish.SettingsApplyTime"] = map[string]string{"ApplyTime": "OnReset"}
+		}
+	}
+
+	resp, err := c.makeRequest(ctx, "PATCH", change.SettingsURI, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) {
+		return nil, fmt.Errorf("failed to set boot order: %w", newRedfishError(resp))
+	}
+
+	// iDRAC may answer with the job it created for the pending change
+	if location := resp.Header.Get("Location"); strings.Contains(location, "/Jobs/") {
+		change.JobURI = uriPath(location)
+	} else if resp.StatusCode == http.StatusAccepted {
+		if err := c.completeAction(ctx, resp); err != nil {
+			return nil, err
+		}
+	} else if change.SettingsURI != systemURI && c.isDell(ctx) {
+		if change.JobURI, err = c.CreateConfigJob(ctx, change.SettingsURI); err != nil {
+			return nil, err
+		}
+	}
+
+	c.logger.LogSuccess("Boot order written to %s", change.SettingsURI)
+	return change, nil
+}
+
+// SelectDiskBootOption picks the boot option of the installation disk: the
+// first local disk whose name or device path contains one of the hints, or
+// the first local disk when no hints are given.
+func SelectDiskBootOption(options []BootOption, hints []string) (*BootOption, error) {
+	var disks []BootOption
+	for _, option := range options {
+		if isDiskBootOption(option) {
+			disks = append(disks, option)
+		}
+	}
+
+	if len(disks) == 0 {
+		return nil, fmt.Errorf("no disk boot option found among %d boot options", len(options))
+	}
+	if len(hints) == 0 {
+		return &disks[0], nil
+	}
+
+	for _, hint := range hints {
+		for i, option := range disks {
+			text := strings.ToLower(option.DisplayName + " " + option.UefiDevicePath + " " + option.ID)
+			if strings.Contains(text, strings.ToLower(hint)) {
+				return &disks[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no disk boot option matches the root device hints %s", strings.Join(hints, ", "))
+}
+
+// isDiskBootOption reports whether a boot option refers to a local disk
+func isDiskBootOption(option BootOption) bool {
+	if option.Alias == "Hdd" {
+		return true
+	}
+	text := strings.ToLower(option.DisplayName + " " + option.UefiDevicePath)
+	for _, keyword := range []string{"hard", "disk", "raid", "nvme", "sata", "scsi", "ahci", "hd("} {
+		if strings.Contains(text, keyword) {
+			return !strings.Contains(text, "usb") && !strings.Contains(text, "virtual")
+		}
+	}
+	return false
+}
+
+// MoveToFront returns order with ref moved to the first position
+func MoveToFront(order []string, ref string) []string {
+	result := []string{ref}
+	for _, entry := range order {
+		if entry != ref {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// containsString reports whether list contains value
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
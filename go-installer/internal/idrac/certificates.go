@@ -0,0 +1,199 @@
+package idrac
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// CertificateIdentifier is the subject or issuer of a Redfish Certificate
+type CertificateIdentifier struct {
+	CommonName         string `json:"CommonName"`
+	Organization       string `json:"Organization"`
+	OrganizationalUnit string `json:"OrganizationalUnit"`
+	City               string `json:"City"`
+	State              string `json:"State"`
+	Country            string `json:"Country"`
+}
+
+// Certificate represents a Redfish Certificate resource
+type Certificate struct {
+	ODataID        string                `json:"@odata.id"`
+	ID             string                `json:"Id"`
+	Subject        CertificateIdentifier `json:"Subject"`
+	Issuer         CertificateIdentifier `json:"Issuer"`
+	ValidNotBefore string                `json:"ValidNotBefore"`
+	ValidNotAfter  string                `json:"ValidNotAfter"`
+}
+
+// CSRRequest holds the subject of a certificate signing request for the BMC
+type CSRRequest struct {
+	CommonName         string
+	Organization       string
+	OrganizationalUnit string
+	City               string
+	State              string
+	Country            string
+	// AlternativeNames are the DNS names and addresses the certificate covers
+	AlternativeNames []string
+}
+
+// httpsCertificatesURI returns the collection holding the certificate of the
+// BMC web server
+func (c *Client) httpsCertificatesURI(ctx context.Context) (string, error) {
+	_, protocolURI, err := c.managerNetworkURIs(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var protocol networkProtocol
+	if err := c.getJSON(ctx, protocolURI, &protocol); err != nil {
+		return "", fmt.Errorf("failed to get network protocol: %w", err)
+	}
+	return valueOr(protocol.HTTPS.Certificates.ODataID, protocolURI+"/HTTPS/Certificates"), nil
+}
+
+// certificateServiceURI returns the CertificateService of the BMC
+func (c *Client) certificateServiceURI(ctx context.Context) (string, error) {
+	res, err := c.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return linkOrDefault(res.Root.CertificateService, "/CertificateService"), nil
+}
+
+// GetHTTPSCertificates reads the certificates of the BMC web server
+func (c *Client) GetHTTPSCertificates(ctx context.Context) ([]Certificate, error) {
+	uri, err := c.httpsCertificatesURI(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var certificates []Certificate
+	if err := collectMembers(ctx, c, ODataLink{ODataID: uri}, &certificates); err != nil {
+		return nil, fmt.Errorf("failed to list HTTPS certificates: %w", err)
+	}
+	return certificates, nil
+}
+
+// GenerateCSR has the BMC generate a key pair for its web server and returns
+// the PEM certificate signing request
+func (c *Client) GenerateCSR(ctx context.Context, csr CSRRequest) (string, error) {
+	serviceURI, err := c.certificateServiceURI(ctx)
+	if err != nil {
+		return "", err
+	}
+	collectionURI, err := c.httpsCertificatesURI(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	body := map[string]interface{}{
+		"CertificateCollection": ODataLink{ODataID: collectionURI},
+		"CommonName":            csr.CommonName,
+		"Organization":          csr.Organization,
+		"OrganizationalUnit":    csr.OrganizationalUnit,
+		"City":                  csr.City,
+		"State":                 csr.State,
+		"Country":               csr.Country,
+	}
+	if len(csr.AlternativeNames) > 0 {
+		body["AlternativeNames"] = csr.AlternativeNames
+	}
+
+	resp, err := c.makeRequest(ctx, "POST", serviceURI+"/Actions/CertificateService.GenerateCSR", body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) && resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("failed to generate CSR: %w", newRedfishError(resp))
+	}
+
+	var result struct {
+		CSRString string `json:"CSRString"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || result.CSRString == "" {
+		return "", fmt.Errorf("the BMC did not return the CSR")
+	}
+	return result.CSRString, nil
+}
+
+// ReplaceHTTPSCertificate installs a signed PEM certificate for the key of the
+// last generated CSR. iDRAC firmware without CertificateService.ReplaceCertificate
+// gets it through DelliDRACCardService.ImportSSLCertificate instead.
+func (c *Client) ReplaceHTTPSCertificate(ctx context.Context, pem string) error {
+	certificates, err := c.GetHTTPSCertificates(ctx)
+	if err != nil {
+		return err
+	}
+	serviceURI, err := c.certificateServiceURI(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(certificates) > 0 {
+		body := map[string]interface{}{
+			"CertificateString": pem,
+			"CertificateType":   "PEM",
+			"CertificateUri":    ODataLink{ODataID: certificates[0].ODataID},
+		}
+		resp, err := c.makeRequest(ctx, "POST", serviceURI+"/Actions/CertificateService.ReplaceCertificate", body)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if isSuccess(resp.StatusCode) {
+			return c.completeAction(ctx, resp)
+		}
+		if !c.isDell(ctx) {
+			return fmt.Errorf("failed to replace HTTPS certificate: %w", newRedfishError(resp))
+		}
+		c.logger.LogWarn("ReplaceCertificate failed (%v), using the iDRAC card service", newRedfishError(resp))
+	} else if !c.isDell(ctx) {
+		return fmt.Errorf("the BMC reports no HTTPS certificate to replace")
+	}
+
+	managerURI, err := c.ManagerURI(ctx)
+	if err != nil {
+		return err
+	}
+	body := map[string]string{"CertificateType": "Server", "SSLCertificateFile": pem}
+	resp, err := c.makeRequest(ctx, "POST", managerURI+"/Oem/Dell/DelliDRACCardService/Actions/DelliDRACCardService.ImportSSLCertificate", body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) {
+		return fmt.Errorf("failed to import HTTPS certificate: %w", newRedfishError(resp))
+	}
+	return c.completeAction(ctx, resp)
+}
+
+// ResetManager restarts the BMC, e.g. to load a new web server certificate.
+// The Redfish session does not survive the restart.
+func (c *Client) ResetManager(ctx context.Context) error {
+	managerURI, err := c.ManagerURI(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.makeRequest(ctx, "POST", managerURI+"/Actions/Manager.Reset", ResetRequest{ResetType: ResetGracefulRestart})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) {
+		return fmt.Errorf("failed to restart the BMC: %w", newRedfishError(resp))
+	}
+
+	c.sessionMu.Lock()
+	c.authToken, c.sessionURI = "", ""
+	c.sessionMu.Unlock()
+	return nil
+}
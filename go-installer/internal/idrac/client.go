@@ -3,12 +3,11 @@ package idrac
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
+	"sync"
 	"time"
 
 	"openshift-sno-hub-installer/internal/config"
@@ -21,6 +20,34 @@ type Client struct {
 	httpClient *http.Client
 	logger     *logger.Logger
 	baseURL    string
+
+	// pollInterval is the delay between task and state polls
+	pollInterval time.Duration
+
+	discoverMu sync.Mutex
+	resources  *Resources
+
+	// events wakes up power and virtual media waits when the BMC reports a change
+	events *EventHub
+
+	// reset selects the reset types and waits of power off and restart
+	reset ResetPolicy
+
+	// dryRun, when set, receives the requests that would change the BMC
+	// instead of sending them; see SetDryRun
+	dryRun func(format string, args ...interface{})
+
+	// trust records the certificate fingerprints trusted on first use, and
+	// trustErr is returned by every request when the TLS trust configuration
+	// could not be loaded, so the client never falls back to an unverified
+	// connection
+	trust    *knownHosts
+	trustErr error
+
+	sessionMu          sync.Mutex
+	authToken          string
+	sessionURI         string
+	sessionUnsupported bool
 }
 
 // SystemInfo represents system information from iDRAC
@@ -30,13 +57,18 @@ type SystemInfo struct {
 	SerialNumber string `json:"SerialNumber"`
 	BiosVersion  string `json:"BiosVersion"`
 	PowerState   string `json:"PowerState"`
-	Health       string `json:"Health"`
+	Health       string `json:"-"`
+	Status       struct {
+		Health string `json:"Health"`
+		State  string `json:"State"`
+	} `json:"Status"`
 }
 
 // BootConfig represents boot configuration
 type BootConfig struct {
 	BootSourceOverrideTarget    string `json:"BootSourceOverrideTarget"`
 	BootSourceOverrideEnabled   string `json:"BootSourceOverrideEnabled"`
+	BootSourceOverrideMode      string `json:"BootSourceOverrideMode,omitempty"`
 }
 
 // SystemBoot represents system boot settings
@@ -51,16 +83,26 @@ type ResetRequest struct {
 
 // VirtualMediaRequest represents a virtual media request
 type VirtualMediaRequest struct {
-	Image string `json:"Image,omitempty"`
+	Image                string `json:"Image,omitempty"`
+	Inserted             *bool  `json:"Inserted,omitempty"`
+	WriteProtected       *bool  `json:"WriteProtected,omitempty"`
+	TransferProtocolType string `json:"TransferProtocolType,omitempty"`
 }
 
 // NewClient creates a new iDRAC client
 func NewClient(cfg *config.IDRACConfig, log *logger.Logger) *Client {
+	trust := &knownHosts{path: cfg.KnownHosts}
+	if trust.path == "" {
+		trust.path = DefaultKnownHostsFile
+	}
+	tlsConfig, trustErr := newTLSConfig(cfg, trust, log)
+	if trustErr != nil {
+		log.LogError("Invalid TLS trust configuration: %v", trustErr)
+	}
+
 	// Create HTTP client with custom transport
 	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: !cfg.VerifySSL,
-		},
+		TLSClientConfig: tlsConfig,
 	}
 
 	httpClient := &http.Client{
@@ -75,70 +117,121 @@ func NewClient(cfg *config.IDRACConfig, log *logger.Logger) *Client {
 		httpClient: httpClient,
 		logger:     log,
 		baseURL:    baseURL,
+		trust:      trust,
+		trustErr:   trustErr,
 	}
 }
 
 // makeRequest makes an HTTP request to the iDRAC API
 func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
-	var reqBody io.Reader
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		var err error
+		jsonData, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
 	}
 
-	url := c.baseURL + endpoint
-	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	c.logger.LogDebug("Making %s request to %s", method, c.baseURL+endpoint)
+	if body != nil {
+		c.logger.LogDebug("Request body:\n%s", redactBody(jsonData))
+	}
+
+	return c.makeRawRequest(ctx, method, endpoint, "application/json", jsonData)
+}
+
+// makeRawRequest sends a request body of the given content type to the iDRAC API
+func (c *Client) makeRawRequest(ctx context.Context, method, endpoint, contentType string, data []byte) (*http.Response, error) {
+	if c.dryRun != nil && method != "GET" && method != "HEAD" {
+		return c.planRequest(method, endpoint, contentType, data), nil
+	}
+
+	resp, token, err := c.doRequest(ctx, method, endpoint, contentType, data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	// Set authentication
-	req.SetBasicAuth(c.config.Username, c.config.Password)
-	req.Header.Set("Content-Type", "application/json")
+	// An expired or revoked session gets one fresh login and a single retry
+	if resp.StatusCode == http.StatusUnauthorized && token != "" {
+		resp.Body.Close()
+		c.logger.LogDebug("Session token rejected, re-authenticating...")
+		c.invalidateSession(token)
 
-	c.logger.LogDebug("Making %s request to %s", method, url)
-	if body != nil {
-		c.logger.LogDebug("Request body: %+v", body)
+		resp, _, err = c.doRequest(ctx, method, endpoint, contentType, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// doRequest sends a single request, authenticating with the session token when
+// one is available and with HTTP Basic auth otherwise. It returns the token used.
+func (c *Client) doRequest(ctx context.Context, method, endpoint, contentType string, data []byte) (*http.Response, string, error) {
+	req, token, err := c.newRequest(ctx, method, endpoint, contentType, data)
+	if err != nil {
+		return nil, "", err
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, "", fmt.Errorf("failed to make request: %w", err)
 	}
 
-	return resp, nil
+	return resp, token, nil
 }
 
-// CheckConnectivity checks if iDRAC is reachable
-func (c *Client) CheckConnectivity(ctx context.Context) error {
-	c.logger.LogInfo("Checking iDRAC connectivity to %s...", c.config.IP)
+// newRequest builds an authenticated request and returns the session token used
+func (c *Client) newRequest(ctx context.Context, method, endpoint, contentType string, data []byte) (*http.Request, string, error) {
+	if c.trustErr != nil {
+		return nil, "", fmt.Errorf("TLS trust configuration: %w", c.trustErr)
+	}
+
+	var reqBody io.Reader
+	if data != nil {
+		reqBody = bytes.NewReader(data)
+	}
 
-	resp, err := c.makeRequest(ctx, "GET", "/redfish/v1/Systems/System.Embedded.1", nil)
+	url := c.baseURL + endpoint
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
-		c.logger.LogError("Failed to connect to iDRAC at %s: %v", c.config.IP, err)
-		return err
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		c.logger.LogError("iDRAC returned status code: %d", resp.StatusCode)
-		return fmt.Errorf("iDRAC returned status code: %d", resp.StatusCode)
+	// Set authentication
+	token, err := c.sessionToken(ctx)
+	if err != nil {
+		return nil, "", err
 	}
+	if token != "" {
+		req.Header.Set("X-Auth-Token", token)
+	} else {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+	req.Header.Set("Content-Type", contentType)
 
-	body, err := io.ReadAll(resp.Body)
+	return req, token, nil
+}
+
+// CheckConnectivity checks if iDRAC is reachable
+func (c *Client) CheckConnectivity(ctx context.Context) error {
+	c.logger.LogInfo("Checking iDRAC connectivity to %s...", c.config.IP)
+
+	res, err := c.Discover(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		c.logger.LogError("Failed to connect to iDRAC at %s: %v", c.config.IP, err)
+		return err
 	}
 
-	if !strings.Contains(string(body), "System.Embedded.1") {
-		c.logger.LogError("Invalid response from iDRAC")
-		return fmt.Errorf("invalid response from iDRAC")
+	var system SystemInfo
+	if err := c.getJSON(ctx, res.SystemURI, &system); err != nil {
+		c.logger.LogError("Invalid response from iDRAC: %v", err)
+		return fmt.Errorf("invalid response from iDRAC: %w", err)
 	}
 
-	c.logger.LogSuccess("iDRAC connectivity verified")
+	c.logger.LogSuccess("iDRAC connectivity verified (%s %s, system %s)", res.Root.Vendor, res.Root.RedfishVersion, res.SystemURI)
 	return nil
 }
 
@@ -146,7 +239,13 @@ func (c *Client) CheckConnectivity(ctx context.Context) error {
 func (c *Client) GetSystemInfo(ctx context.Context) (*SystemInfo, error) {
 	c.logger.LogInfo("Getting system information...")
 
-	resp, err := c.makeRequest(ctx, "GET", "/redfish/v1/Systems/System.Embedded.1", nil)
+	systemURI, err := c.SystemURI(ctx)
+	if err != nil {
+		c.logger.LogError("Failed to get system information: %v", err)
+		return nil, err
+	}
+
+	resp, err := c.makeRequest(ctx, "GET", systemURI, nil)
 	if err != nil {
 		c.logger.LogError("Failed to get system information: %v", err)
 		return nil, err
@@ -154,7 +253,7 @@ func (c *Client) GetSystemInfo(ctx context.Context) (*SystemInfo, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get system info, status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("failed to get system info: %w", newRedfishError(resp))
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -166,6 +265,7 @@ func (c *Client) GetSystemInfo(ctx context.Context) (*SystemInfo, error) {
 	if err := json.Unmarshal(body, &systemInfo); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal system info: %w", err)
 	}
+	systemInfo.Health = systemInfo.Status.Health
 
 	c.logger.LogInfo("System Information:")
 	c.logger.LogInfo("  Manufacturer: %s", systemInfo.Manufacturer)
@@ -217,142 +317,172 @@ func (c *Client) PowerOnSystem(ctx context.Context) error {
 		return nil
 	}
 
-	resetReq := ResetRequest{ResetType: "On"}
-	resp, err := c.makeRequest(ctx, "POST", "/redfish/v1/Systems/System.Embedded.1/Actions/ComputerSystem.Reset", resetReq)
-	if err != nil {
+	if err := c.ResetSystem(ctx, "On"); err != nil {
 		c.logger.LogError("Failed to power on system: %v", err)
 		return err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		return fmt.Errorf("failed to power on system, status code: %d", resp.StatusCode)
-	}
 
 	c.logger.LogSuccess("System power on command sent successfully")
 	return c.WaitForSystemPowerOn(ctx)
 }
 
-// PowerOffSystem powers off the system
+// PowerOffSystem powers off the system with the reset policy's off type
 func (c *Client) PowerOffSystem(ctx context.Context) error {
 	c.logger.LogInfo("Powering off system...")
 
-	// Check current power state
-	powerState, err := c.GetSystemPowerState(ctx)
-	if err != nil {
+	policy := c.resetPolicy()
+	return c.ShutdownSystem(ctx, policy.OffType, policy.GracefulTimeout)
+}
+
+// RestartSystem restarts the system with the reset policy's restart type
+func (c *Client) RestartSystem(ctx context.Context) error {
+	c.logger.LogInfo("Restarting system...")
+
+	policy := c.resetPolicy()
+	return c.RebootSystem(ctx, policy.RestartType, policy.GracefulTimeout)
+}
+
+// SetVirtualCDBoot sets the boot device to Virtual CD/DVD
+func (c *Client) SetVirtualCDBoot(ctx context.Context) error {
+	c.logger.LogInfo("Setting boot device to Virtual CD/DVD...")
+
+	if err := c.SetBootOverride(ctx, "Cd", "Once"); err != nil {
+		c.logger.LogError("Failed to set boot device to Virtual CD/DVD: %v", err)
 		return err
 	}
 
-	if powerState == "Off" {
-		c.logger.LogInfo("System is already powered off")
-		return nil
+	c.logger.LogSuccess("Boot device set to Virtual CD/DVD successfully")
+	return nil
+}
+
+// SetHDDBoot sets the boot device to HDD
+func (c *Client) SetHDDBoot(ctx context.Context) error {
+	c.logger.LogInfo("Setting boot device to HDD...")
+
+	if err := c.SetBootOverride(ctx, "Hdd", "Once"); err != nil {
+		c.logger.LogError("Failed to set boot device to HDD: %v", err)
+		return err
 	}
 
-	resetReq := ResetRequest{ResetType: "ForceOff"}
-	resp, err := c.makeRequest(ctx, "POST", "/redfish/v1/Systems/System.Embedded.1/Actions/ComputerSystem.Reset", resetReq)
+	c.logger.LogSuccess("Boot device set to HDD successfully")
+	return nil
+}
+
+// ResetSystem sends a ComputerSystem.Reset action with the given reset type
+func (c *Client) ResetSystem(ctx context.Context, resetType string) error {
+	systemURI, err := c.SystemURI(ctx)
+	if err != nil {
+		return err
+	}
+
+	resetReq := ResetRequest{ResetType: resetType}
+	resp, err := c.makeRequest(ctx, "POST", systemURI+"/Actions/ComputerSystem.Reset", resetReq)
 	if err != nil {
-		c.logger.LogError("Failed to power off system: %v", err)
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		return fmt.Errorf("failed to power off system, status code: %d", resp.StatusCode)
+	if !isSuccess(resp.StatusCode) {
+		return fmt.Errorf("reset %s failed: %w", resetType, newRedfishError(resp))
 	}
 
-	c.logger.LogSuccess("System power off command sent successfully")
-	return c.WaitForSystemPowerOff(ctx)
+	return c.completeAction(ctx, resp)
 }
 
-// RestartSystem restarts the system
-func (c *Client) RestartSystem(ctx context.Context) error {
-	c.logger.LogInfo("Restarting system...")
+// SetBootOverride sets the boot source override target and how long it stays enabled
+func (c *Client) SetBootOverride(ctx context.Context, target, enabled string) error {
+	return c.patchBoot(ctx, BootConfig{
+		BootSourceOverrideTarget:  target,
+		BootSourceOverrideEnabled: enabled,
+	})
+}
 
-	resetReq := ResetRequest{ResetType: "ForceRestart"}
-	resp, err := c.makeRequest(ctx, "POST", "/redfish/v1/Systems/System.Embedded.1/Actions/ComputerSystem.Reset", resetReq)
+// patchBoot sends a boot configuration PATCH to the system
+func (c *Client) patchBoot(ctx context.Context, boot BootConfig) error {
+	systemURI, err := c.SystemURI(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.makeRequest(ctx, "PATCH", systemURI, SystemBoot{Boot: boot})
 	if err != nil {
-		c.logger.LogError("Failed to restart system: %v", err)
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		return fmt.Errorf("failed to restart system, status code: %d", resp.StatusCode)
+	if !isSuccess(resp.StatusCode) {
+		return fmt.Errorf("failed to set boot source override to %s: %w", boot.BootSourceOverrideTarget, newRedfishError(resp))
 	}
 
-	c.logger.LogSuccess("System restart command sent successfully")
-	return nil
+	return c.completeAction(ctx, resp)
 }
 
-// SetVirtualCDBoot sets the boot device to Virtual CD/DVD
-func (c *Client) SetVirtualCDBoot(ctx context.Context) error {
-	c.logger.LogInfo("Setting boot device to Virtual CD/DVD...")
-
-	bootConfig := SystemBoot{
-		Boot: BootConfig{
-			BootSourceOverrideTarget:  "Cd",
-			BootSourceOverrideEnabled: "Once",
-		},
+// InsertVirtualMediaRequest sends a VirtualMedia.InsertMedia action with a caller supplied body
+func (c *Client) InsertVirtualMediaRequest(ctx context.Context, mediaReq VirtualMediaRequest) error {
+	vmURI, err := c.VirtualMediaURI(ctx)
+	if err != nil {
+		return err
 	}
 
-	resp, err := c.makeRequest(ctx, "PATCH", "/redfish/v1/Systems/System.Embedded.1", bootConfig)
+	resp, err := c.makeRequest(ctx, "POST", vmURI+"/Actions/VirtualMedia.InsertMedia", mediaReq)
 	if err != nil {
-		c.logger.LogError("Failed to set boot device to Virtual CD/DVD: %v", err)
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to set boot device to Virtual CD/DVD, status code: %d", resp.StatusCode)
+	if !isSuccess(resp.StatusCode) {
+		return fmt.Errorf("failed to insert virtual media: %w", newRedfishError(resp))
 	}
 
-	c.logger.LogSuccess("Boot device set to Virtual CD/DVD successfully")
-	return nil
+	return c.completeAction(ctx, resp)
 }
 
-// SetHDDBoot sets the boot device to HDD
-func (c *Client) SetHDDBoot(ctx context.Context) error {
-	c.logger.LogInfo("Setting boot device to HDD...")
-
-	bootConfig := SystemBoot{
-		Boot: BootConfig{
-			BootSourceOverrideTarget:  "Hdd",
-			BootSourceOverrideEnabled: "Once",
-		},
-	}
-
-	resp, err := c.makeRequest(ctx, "PATCH", "/redfish/v1/Systems/System.Embedded.1", bootConfig)
+// Patch sends a PATCH request to an arbitrary Redfish resource
+func (c *Client) Patch(ctx context.Context, uri string, body interface{}) error {
+	resp, err := c.makeRequest(ctx, "PATCH", uri, body)
 	if err != nil {
-		c.logger.LogError("Failed to set boot device to HDD: %v", err)
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to set boot device to HDD, status code: %d", resp.StatusCode)
+	if !isSuccess(resp.StatusCode) {
+		return fmt.Errorf("failed to patch %s: %w", uri, newRedfishError(resp))
 	}
 
-	c.logger.LogSuccess("Boot device set to HDD successfully")
-	return nil
+	return c.completeAction(ctx, resp)
+}
+
+// isSuccess reports whether a status code indicates a successful Redfish operation
+func isSuccess(statusCode int) bool {
+	return statusCode == http.StatusOK || statusCode == http.StatusAccepted || statusCode == http.StatusNoContent
 }
 
 // EjectVirtualMedia ejects virtual media
 func (c *Client) EjectVirtualMedia(ctx context.Context) error {
 	c.logger.LogInfo("Ejecting virtual media...")
 
-	resp, err := c.makeRequest(ctx, "POST", "/redfish/v1/Managers/iDRAC.Embedded.1/VirtualMedia/CD/Actions/VirtualMedia.EjectMedia", map[string]interface{}{})
+	vmURI, err := c.VirtualMediaURI(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.makeRequest(ctx, "POST", vmURI+"/Actions/VirtualMedia.EjectMedia", map[string]interface{}{})
 	if err != nil {
 		c.logger.LogError("Failed to eject virtual media: %v", err)
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		return fmt.Errorf("failed to eject virtual media, status code: %d", resp.StatusCode)
+	if !isSuccess(resp.StatusCode) {
+		return fmt.Errorf("failed to eject virtual media: %w", newRedfishError(resp))
+	}
+
+	if err := c.completeAction(ctx, resp); err != nil {
+		c.logger.LogError("Failed to eject virtual media: %v", err)
+		return err
 	}
 
 	c.logger.LogSuccess("Virtual media ejected successfully")
-	time.Sleep(5 * time.Second)
 	return nil
 }
 
@@ -360,79 +490,33 @@ func (c *Client) EjectVirtualMedia(ctx context.Context) error {
 func (c *Client) InsertVirtualMedia(ctx context.Context, isoURL string) error {
 	c.logger.LogInfo("Inserting ISO image: %s", isoURL)
 
-	mediaReq := VirtualMediaRequest{Image: isoURL}
-	resp, err := c.makeRequest(ctx, "POST", "/redfish/v1/Managers/iDRAC.Embedded.1/VirtualMedia/CD/Actions/VirtualMedia.InsertMedia", mediaReq)
-	if err != nil {
+	if err := c.InsertVirtualMediaRequest(ctx, VirtualMediaRequest{Image: isoURL}); err != nil {
 		c.logger.LogError("Failed to insert virtual media: %v", err)
 		return err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		return fmt.Errorf("failed to insert virtual media, status code: %d", resp.StatusCode)
-	}
 
 	c.logger.LogSuccess("Virtual media inserted successfully")
-	time.Sleep(5 * time.Second)
 	return nil
 }
 
 // WaitForSystemPowerOn waits for the system to power on
 func (c *Client) WaitForSystemPowerOn(ctx context.Context) error {
 	c.logger.LogInfo("Waiting for system to power on...")
-	maxAttempts := 30
-	attempt := 0
-
-	for attempt < maxAttempts {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
 
-		powerState, err := c.GetSystemPowerState(ctx)
-		if err != nil {
-			c.logger.LogWarn("Failed to get power state: %v", err)
-		} else if powerState == "On" {
-			c.logger.LogSuccess("System is now powered on")
-			return nil
-		}
-
-		c.logger.LogInfo("System power state: %s, waiting... (attempt %d/%d)", powerState, attempt+1, maxAttempts)
-		time.Sleep(10 * time.Second)
-		attempt++
+	if err := c.waitForPowerState(ctx, "On", c.resetPolicy().Wait.Timeout); err != nil {
+		c.logger.LogError("System failed to power on within expected time")
+		return err
 	}
-
-	c.logger.LogError("System failed to power on within expected time")
-	return fmt.Errorf("system failed to power on within expected time")
+	return nil
 }
 
 // WaitForSystemPowerOff waits for the system to power off
 func (c *Client) WaitForSystemPowerOff(ctx context.Context) error {
 	c.logger.LogInfo("Waiting for system to power off...")
-	maxAttempts := 30
-	attempt := 0
-
-	for attempt < maxAttempts {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
 
-		powerState, err := c.GetSystemPowerState(ctx)
-		if err != nil {
-			c.logger.LogWarn("Failed to get power state: %v", err)
-		} else if powerState == "Off" {
-			c.logger.LogSuccess("System is now powered off")
-			return nil
-		}
-
-		c.logger.LogInfo("System power state: %s, waiting... (attempt %d/%d)", powerState, attempt+1, maxAttempts)
-		time.Sleep(10 * time.Second)
-		attempt++
+	if err := c.waitForPowerState(ctx, "Off", c.resetPolicy().Wait.Timeout); err != nil {
+		c.logger.LogError("System failed to power off within expected time")
+		return err
 	}
-
-	c.logger.LogError("System failed to power off within expected time")
-	return fmt.Errorf("system failed to power off within expected time")
+	return nil
 }
\ No newline at end of file
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"time"
 
 	"openshift-sno-hub-installer/internal/config"
 	"openshift-sno-hub-installer/internal/logger"
@@ -37,7 +36,13 @@ type VirtualMediaInfo struct {
 func (c *EnhancedClient) GetVirtualMediaInfo(ctx context.Context) (*VirtualMediaInfo, error) {
 	c.logger.LogInfo("Getting virtual media information...")
 
-	resp, err := c.makeRequest(ctx, "GET", "/redfish/v1/Managers/iDRAC.Embedded.1/VirtualMedia/CD", nil)
+	vmURI, err := c.VirtualMediaURI(ctx)
+	if err != nil {
+		c.logger.LogError("Failed to get virtual media info: %v", err)
+		return nil, err
+	}
+
+	resp, err := c.makeRequest(ctx, "GET", vmURI, nil)
 	if err != nil {
 		c.logger.LogError("Failed to get virtual media info: %v", err)
 		return nil, err
@@ -45,7 +50,7 @@ func (c *EnhancedClient) GetVirtualMediaInfo(ctx context.Context) (*VirtualMedia
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get virtual media info, status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("failed to get virtual media info: %w", newRedfishError(resp))
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -80,36 +85,31 @@ func (c *EnhancedClient) SetVirtualCDBootEnhanced(ctx context.Context) error {
 		c.logger.LogWarn("No virtual media is currently inserted")
 	}
 
-	// Try different virtual CD boot options for iDRAC 8 compatibility
-	// Priority order: RemoteCd (most common for iDRAC 8), VirtualCd, Cd
-	virtualCDOptions := []string{"RemoteCd", "VirtualCd", "Cd"}
-	
-	for _, bootTarget := range virtualCDOptions {
-		c.logger.LogInfo("Attempting to set boot device to %s...", bootTarget)
-		
-		bootConfig := SystemBoot{
-			Boot: BootConfig{
-				BootSourceOverrideTarget:  bootTarget,
-				BootSourceOverrideEnabled: "Once",
-			},
-		}
+	// RemoteCd is most common on iDRAC 8, newer firmware uses VirtualCd or Cd
+	target, err := c.ApplyBootOverride(ctx, BootOverride{Persistence: "Once"}, DellVirtualCDTargets)
+	if err != nil {
+		return fmt.Errorf("failed to set boot device to Virtual CD/DVD: %w", err)
+	}
 
-		resp, err := c.makeRequest(ctx, "PATCH", "/redfish/v1/Systems/System.Embedded.1", bootConfig)
-		if err != nil {
-			c.logger.LogWarn("Failed to set boot device to %s: %v", bootTarget, err)
-			continue
-		}
-		defer resp.Body.Close()
+	c.logger.LogSuccess("Boot device set to Virtual CD/DVD (%s) successfully", target)
+	return nil
+}
 
-		if resp.StatusCode == http.StatusOK {
-			c.logger.LogSuccess("Boot device set to Virtual CD/DVD (%s) successfully", bootTarget)
-			return nil
-		} else {
-			c.logger.LogWarn("Failed to set boot device to %s, status code: %d", bootTarget, resp.StatusCode)
-		}
+// WaitForVirtualMedia polls the virtual CD/DVD until its Inserted state
+// matches inserted, backing off like the power state waits
+func (c *EnhancedClient) WaitForVirtualMedia(ctx context.Context, inserted bool) error {
+	what := fmt.Sprintf("virtual media Inserted=%t", inserted)
+	if c.planWait(what) {
+		return nil
 	}
 
-	return fmt.Errorf("failed to set boot device to Virtual CD/DVD with any supported option")
+	return c.waitForState(ctx, what, c.resetPolicy().Wait.Timeout, IsVirtualMediaEvent, func() (string, bool, error) {
+		mediaInfo, err := c.GetVirtualMediaInfo(ctx)
+		if err != nil {
+			return "", false, err
+		}
+		return fmt.Sprintf("Inserted=%t", mediaInfo.Inserted), mediaInfo.Inserted == inserted, nil
+	})
 }
 
 // ManageVirtualMediaBootProcess manages the complete virtual media boot process
@@ -120,15 +120,18 @@ func (c *EnhancedClient) ManageVirtualMediaBootProcess(ctx context.Context, isoU
 	c.logger.LogInfo("Step 1: Ejecting existing virtual media...")
 	if err := c.EjectVirtualMedia(ctx); err != nil {
 		c.logger.LogWarn("Failed to eject existing virtual media: %v", err)
+	} else if err := c.WaitForVirtualMedia(ctx, false); err != nil {
+		c.logger.LogWarn("Virtual media still reported as inserted: %v", err)
 	}
-	time.Sleep(10 * time.Second)
 
 	// Step 2: Insert the new ISO
 	c.logger.LogInfo("Step 2: Inserting new virtual media...")
 	if err := c.InsertVirtualMedia(ctx, isoURL); err != nil {
 		return fmt.Errorf("failed to insert virtual media: %w", err)
 	}
-	time.Sleep(10 * time.Second)
+	if err := c.WaitForVirtualMedia(ctx, true); err != nil {
+		return fmt.Errorf("virtual media was not attached: %w", err)
+	}
 
 	// Step 3: Set boot device to virtual CD/DVD
 	c.logger.LogInfo("Step 3: Setting boot device to virtual CD/DVD...")
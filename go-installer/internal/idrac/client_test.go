@@ -3,8 +3,18 @@ package idrac
 import (
 	"context"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -12,12 +22,48 @@ import (
 	"openshift-sno-hub-installer/internal/logger"
 )
 
-// Mock iDRAC server for testing
-func createMockIDRACServer() *httptest.Server {
-	mux := http.NewServeMux()
+// mockIDRACHandlers serves a Dell iDRAC with one system and a virtual CD
+func mockIDRACHandlers() testHandlers {
+	handlers := testHandlers{}
+	powerState := "On"
+
+	// Mock service root and collections used for resource discovery
+	handlers["/redfish/v1"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"Vendor":         "Dell",
+			"RedfishVersion": "1.4.0",
+			"Systems":        map[string]string{"@odata.id": "/redfish/v1/Systems"},
+			"Managers":       map[string]string{"@odata.id": "/redfish/v1/Managers"},
+		})
+	}
+	handlers["/redfish/v1/Systems"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, collectionOf(dellSystemURI))
+	}
+	handlers["/redfish/v1/Managers"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, collectionOf(dellManagerURI))
+	}
+	handlers[dellManagerURI] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"Id":              "iDRAC.Embedded.1",
+			"FirmwareVersion": "2.83.83.83",
+			"VirtualMedia":    map[string]string{"@odata.id": "/redfish/v1/Managers/iDRAC.Embedded.1/VirtualMedia"},
+		})
+	}
+	handlers["/redfish/v1/Managers/iDRAC.Embedded.1/VirtualMedia"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, collectionOf(
+			"/redfish/v1/Managers/iDRAC.Embedded.1/VirtualMedia/RemovableDisk",
+			"/redfish/v1/Managers/iDRAC.Embedded.1/VirtualMedia/CD",
+		))
+	}
+	handlers["/redfish/v1/Managers/iDRAC.Embedded.1/VirtualMedia/RemovableDisk"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"MediaTypes": []string{"USBStick"}})
+	}
+	handlers["/redfish/v1/Managers/iDRAC.Embedded.1/VirtualMedia/CD"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"MediaTypes": []string{"CD", "DVD"}})
+	}
 
 	// Mock system info endpoint
-	mux.HandleFunc("/redfish/v1/Systems/System.Embedded.1", func(w http.ResponseWriter, r *http.Request) {
+	handlers[dellSystemURI] = func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case "GET":
 			systemInfo := map[string]interface{}{
@@ -25,57 +71,125 @@ func createMockIDRACServer() *httptest.Server {
 				"Model":        "PowerEdge R640",
 				"SerialNumber": "ABC123456",
 				"BiosVersion":  "2.15.0",
-				"PowerState":   "On",
+				"PowerState":   powerState,
 				"Status": map[string]interface{}{
 					"Health": "OK",
 				},
 			}
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(systemInfo)
+			writeJSON(w, systemInfo)
 		case "PATCH":
 			// Mock boot configuration
 			w.WriteHeader(http.StatusOK)
 		default:
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
-	})
+	}
 
 	// Mock system reset endpoint
-	mux.HandleFunc("/redfish/v1/Systems/System.Embedded.1/Actions/ComputerSystem.Reset", func(w http.ResponseWriter, r *http.Request) {
+	handlers["/redfish/v1/Systems/System.Embedded.1/Actions/ComputerSystem.Reset"] = func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == "POST" {
+			var req ResetRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			switch req.ResetType {
+			case "On":
+				powerState = "On"
+			case "ForceOff", "GracefulShutdown":
+				powerState = "Off"
+			}
 			w.WriteHeader(http.StatusOK)
 		} else {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
-	})
+	}
 
 	// Mock virtual media endpoints
-	mux.HandleFunc("/redfish/v1/Managers/iDRAC.Embedded.1/VirtualMedia/CD/Actions/VirtualMedia.EjectMedia", func(w http.ResponseWriter, r *http.Request) {
+	handlers["/redfish/v1/Managers/iDRAC.Embedded.1/VirtualMedia/CD/Actions/VirtualMedia.EjectMedia"] = func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == "POST" {
 			w.WriteHeader(http.StatusOK)
 		} else {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
-	})
+	}
 
-	mux.HandleFunc("/redfish/v1/Managers/iDRAC.Embedded.1/VirtualMedia/CD/Actions/VirtualMedia.InsertMedia", func(w http.ResponseWriter, r *http.Request) {
+	handlers["/redfish/v1/Managers/iDRAC.Embedded.1/VirtualMedia/CD/Actions/VirtualMedia.InsertMedia"] = func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == "POST" {
 			w.WriteHeader(http.StatusOK)
 		} else {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
-	})
+	}
 
-	return httptest.NewServer(mux)
+	return handlers
 }
 
-func TestIDRACClient(t *testing.T) {
-	// Create mock server
-	server := createMockIDRACServer()
-	defer server.Close()
+// testHandlers maps the request paths of a test BMC to their handlers
+type testHandlers map[string]http.HandlerFunc
 
-	// Create test configuration
-	cfg := &config.IDRACConfig{
+// newTestClient starts a test BMC serving handlers and returns a client of it
+// that authenticates with HTTP Basic auth and polls tasks every millisecond
+func newTestClient(tb testing.TB, handlers testHandlers) *Client {
+	tb.Helper()
+
+	mux := http.NewServeMux()
+	for path, handler := range handlers {
+		mux.HandleFunc(path, handler)
+	}
+	server := httptest.NewServer(mux)
+	tb.Cleanup(server.Close)
+
+	log := logger.NewFileLogger(tb.TempDir())
+	tb.Cleanup(func() { log.Close() })
+
+	return &Client{
+		config:       &config.IDRACConfig{IP: "localhost", AuthMethod: "basic"},
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		logger:       log,
+		baseURL:      server.URL,
+		pollInterval: time.Millisecond,
+	}
+}
+
+// The system and manager of a Dell iDRAC
+const (
+	dellSystemURI  = "/redfish/v1/Systems/System.Embedded.1"
+	dellManagerURI = "/redfish/v1/Managers/iDRAC.Embedded.1"
+)
+
+// newDellTestClient returns a test client that has already discovered a Dell
+// iDRAC at dellSystemURI and dellManagerURI
+func newDellTestClient(tb testing.TB, handlers testHandlers) *Client {
+	tb.Helper()
+
+	client := newTestClient(tb, handlers)
+	client.resources = &Resources{
+		Root:       ServiceRoot{Vendor: "Dell"},
+		SystemURI:  dellSystemURI,
+		ManagerURI: dellManagerURI,
+	}
+	return client
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// collectionOf builds a Redfish collection body from member URIs
+func collectionOf(uris ...string) map[string]interface{} {
+	members := make([]map[string]string, 0, len(uris))
+	for _, uri := range uris {
+		members = append(members, map[string]string{"@odata.id": uri})
+	}
+	return map[string]interface{}{
+		"Members":             members,
+		"Members@odata.count": len(members),
+	}
+}
+
+func TestIDRACClient(t *testing.T) {
+	client := newTestClient(t, mockIDRACHandlers())
+	client.config = &config.IDRACConfig{
 		IP:        "localhost",
 		Username:  "root",
 		Password:  "password",
@@ -83,18 +197,6 @@ func TestIDRACClient(t *testing.T) {
 		Timeout:   30,
 	}
 
-	// Create logger
-	log := logger.NewLogger()
-	defer log.Close()
-
-	// Create client with mock server URL
-	client := &Client{
-		config:     cfg,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
-		logger:     log,
-		baseURL:    server.URL,
-	}
-
 	ctx := context.Background()
 
 	t.Run("CheckConnectivity", func(t *testing.T) {
@@ -187,60 +289,1710 @@ func TestIDRACClient(t *testing.T) {
 	})
 }
 
-func TestIDRACClientErrorHandling(t *testing.T) {
-	// Create client with invalid configuration
-	cfg := &config.IDRACConfig{
-		IP:        "invalid-host",
-		Username:  "root",
-		Password:  "password",
-		VerifySSL: false,
-		Timeout:   1, // Very short timeout
+func TestDiscoverNonDellResourceIDs(t *testing.T) {
+	handlers := testHandlers{}
+	handlers["/redfish/v1"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"Vendor":   "HPE",
+			"Systems":  map[string]string{"@odata.id": "/redfish/v1/Systems"},
+			"Managers": map[string]string{"@odata.id": "/redfish/v1/Managers"},
+		})
+	}
+	handlers["/redfish/v1/Systems"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, collectionOf("/redfish/v1/Systems/1", "/redfish/v1/Systems/2"))
 	}
+	handlers["/redfish/v1/Managers"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, collectionOf("/redfish/v1/Managers/1"))
+	}
+	handlers["/redfish/v1/Managers/1"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{})
+	}
+	handlers["/redfish/v1/Systems/2"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"VirtualMedia": map[string]string{"@odata.id": "/redfish/v1/Systems/2/VirtualMedia"},
+		})
+	}
+	handlers["/redfish/v1/Systems/2/VirtualMedia"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, collectionOf("/redfish/v1/Systems/2/VirtualMedia/1", "/redfish/v1/Systems/2/VirtualMedia/2"))
+	}
+	handlers["/redfish/v1/Systems/2/VirtualMedia/1"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"MediaTypes": []string{"Floppy", "USBStick"}})
+	}
+	handlers["/redfish/v1/Systems/2/VirtualMedia/2"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"MediaTypes": []string{"CD", "DVD"}})
+	}
+	client := newTestClient(t, handlers)
+	client.config = &config.IDRACConfig{IP: "localhost", SystemID: "2"}
 
-	log := logger.NewLogger()
-	defer log.Close()
+	res, err := client.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if res.SystemURI != "/redfish/v1/Systems/2" {
+		t.Errorf("Expected system '/redfish/v1/Systems/2', got '%s'", res.SystemURI)
+	}
+	if res.ManagerURI != "/redfish/v1/Managers/1" {
+		t.Errorf("Expected manager '/redfish/v1/Managers/1', got '%s'", res.ManagerURI)
+	}
+	if res.VirtualMediaURI != "/redfish/v1/Systems/2/VirtualMedia/2" {
+		t.Errorf("Expected virtual media '/redfish/v1/Systems/2/VirtualMedia/2', got '%s'", res.VirtualMediaURI)
+	}
+}
 
-	client := NewClient(cfg, log)
+func TestSessionAuthentication(t *testing.T) {
+	logins, logouts := 0, 0
+	validToken := ""
+
+	handlers := testHandlers{}
+	handlers["/redfish/v1/SessionService/Sessions"] = func(w http.ResponseWriter, r *http.Request) {
+		var req SessionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.UserName != "root" || req.Password != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		logins++
+		validToken = fmt.Sprintf("token-%d", logins)
+		w.Header().Set("X-Auth-Token", validToken)
+		w.Header().Set("Location", fmt.Sprintf("/redfish/v1/SessionService/Sessions/%d", logins))
+		w.WriteHeader(http.StatusCreated)
+	}
+	handlers["/redfish/v1/SessionService/Sessions/"] = func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" && r.Header.Get("X-Auth-Token") == validToken {
+			logouts++
+			validToken = ""
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+	handlers["/redfish/v1/Managers/1"] = func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); ok {
+			t.Error("Basic auth sent while a session is open")
+		}
+		if r.Header.Get("X-Auth-Token") != validToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"Id": "1"})
+	}
+	client := newTestClient(t, handlers)
+	client.config = &config.IDRACConfig{IP: "localhost", Username: "root", Password: "password"}
 	ctx := context.Background()
 
-	t.Run("ConnectivityFailure", func(t *testing.T) {
-		err := client.CheckConnectivity(ctx)
-		if err == nil {
-			t.Error("Expected connectivity check to fail with invalid host")
+	var manager map[string]interface{}
+	for i := 0; i < 3; i++ {
+		if err := client.getJSON(ctx, "/redfish/v1/Managers/1", &manager); err != nil {
+			t.Fatalf("getJSON failed: %v", err)
 		}
-	})
+	}
+	if logins != 1 {
+		t.Errorf("Expected session to be reused across requests, got %d logins", logins)
+	}
+
+	// Revoke the token on the BMC side; the client must log in again once
+	validToken = "revoked"
+	if err := client.getJSON(ctx, "/redfish/v1/Managers/1", &manager); err != nil {
+		t.Fatalf("getJSON after token expiry failed: %v", err)
+	}
+	if logins != 2 {
+		t.Errorf("Expected re-authentication after 401, got %d logins", logins)
+	}
+
+	if err := client.Logout(ctx); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if logouts != 1 {
+		t.Errorf("Expected session to be deleted, got %d logouts", logouts)
+	}
 }
 
-// Benchmark tests
-func BenchmarkGetSystemInfo(b *testing.B) {
-	server := createMockIDRACServer()
-	defer server.Close()
+func TestTaskTracking(t *testing.T) {
+	polls := 0
 
-	cfg := &config.IDRACConfig{
-		IP:        "localhost",
-		Username:  "root",
-		Password:  "password",
-		VerifySSL: false,
-		Timeout:   30,
+	handlers := testHandlers{}
+	handlers["/redfish/v1/Systems/1/Actions/ComputerSystem.Reset"] = func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/redfish/v1/TaskService/Tasks/JID_001")
+		w.WriteHeader(http.StatusAccepted)
+	}
+	handlers["/redfish/v1/TaskService/Tasks/JID_001"] = func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls < 3 {
+			w.WriteHeader(http.StatusAccepted)
+			writeJSON(w, map[string]interface{}{"Id": "JID_001", "TaskState": "Running", "PercentComplete": polls * 30})
+			return
+		}
+		writeJSON(w, map[string]interface{}{"Id": "JID_001", "TaskState": "Completed", "TaskStatus": "OK"})
+	}
+	handlers["/redfish/v1/Managers/1/Jobs/JID_002"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"Id":        "JID_002",
+			"JobState":  "Failed",
+			"Message":   "Unable to complete the operation.",
+			"MessageId": "SYS051",
+		})
+	}
+	unavailable := 0
+	handlers["/redfish/v1/TaskService/Tasks/JID_003"] = func(w http.ResponseWriter, r *http.Request) {
+		unavailable++
+		if unavailable < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"Id": "JID_003", "TaskState": "Completed", "TaskStatus": "OK"})
+	}
+	garbled := 0
+	handlers["/redfish/v1/TaskService/Tasks/JID_004"] = func(w http.ResponseWriter, r *http.Request) {
+		garbled++
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("<html>"))
 	}
+	handlers["/redfish/v1/TaskService/Tasks/JID_005"] = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		writeJSON(w, map[string]interface{}{"Id": "JID_005", "TaskState": "Running"})
+	}
+	client := newTestClient(t, handlers)
+	client.resources = &Resources{SystemURI: "/redfish/v1/Systems/1", ManagerURI: "/redfish/v1/Managers/1"}
+	ctx := context.Background()
 
-	log := logger.NewLogger()
-	defer log.Close()
+	t.Run("AcceptedResetWaitsForTask", func(t *testing.T) {
+		if err := client.RestartSystem(ctx); err != nil {
+			t.Fatalf("RestartSystem failed: %v", err)
+		}
+		if polls != 3 {
+			t.Errorf("Expected 3 task polls, got %d", polls)
+		}
+	})
+
+	t.Run("FailedJobReturnsTaskError", func(t *testing.T) {
+		_, err := client.WaitForJob(ctx, "JID_002")
+		var taskErr *TaskError
+		if !errors.As(err, &taskErr) {
+			t.Fatalf("Expected *TaskError, got %v", err)
+		}
+		if taskErr.State != "Failed" {
+			t.Errorf("Expected state 'Failed', got '%s'", taskErr.State)
+		}
+		if len(taskErr.Messages) != 1 || taskErr.Messages[0].MessageID != "SYS051" {
+			t.Errorf("Expected job message SYS051, got %+v", taskErr.Messages)
+		}
+	})
+
+	t.Run("RetriesServerErrors", func(t *testing.T) {
+		if _, err := client.WaitForTask(ctx, "/redfish/v1/TaskService/Tasks/JID_003"); err != nil {
+			t.Fatalf("WaitForTask failed: %v", err)
+		}
+		if unavailable != 3 {
+			t.Errorf("Expected 3 task polls, got %d", unavailable)
+		}
+	})
+
+	t.Run("DecodeErrorIsPermanent", func(t *testing.T) {
+		if _, err := client.WaitForTask(ctx, "/redfish/v1/TaskService/Tasks/JID_004"); err == nil {
+			t.Fatal("Expected an undecodable task to fail")
+		}
+		if garbled != 1 {
+			t.Errorf("Expected the task to be polled once, got %d", garbled)
+		}
+	})
+
+	t.Run("TaskTimeout", func(t *testing.T) {
+		client.config = &config.IDRACConfig{IP: "localhost", AuthMethod: "basic", TaskTimeout: 1}
+		defer func() { client.config = &config.IDRACConfig{IP: "localhost", AuthMethod: "basic"} }()
+
+		_, err := client.WaitForTask(ctx, "/redfish/v1/TaskService/Tasks/JID_005")
+		if !errors.Is(err, errTaskTimeout) {
+			t.Fatalf("Expected task timeout, got %v", err)
+		}
+	})
+}
 
-	client := &Client{
-		config:     cfg,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
-		logger:     log,
-		baseURL:    server.URL,
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"connection refused", &url.Error{Op: "Get", URL: "https://bmc", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, true},
+		{"connection reset", fmt.Errorf("failed to make request: %w", syscall.ECONNRESET), true},
+		{"unexpected EOF", fmt.Errorf("failed to read response body: %w", io.ErrUnexpectedEOF), true},
+		{"dial timeout", &url.Error{Op: "Get", URL: "https://bmc", Err: &net.OpError{Op: "dial", Err: os.ErrDeadlineExceeded}}, true},
+		{"service unavailable", &RedfishError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"not found", &RedfishError{StatusCode: http.StatusNotFound}, false},
+		{"fingerprint mismatch", &url.Error{Op: "Get", URL: "https://bmc", Err: &FingerprintMismatchError{Host: "bmc"}}, false},
+		{"unsupported scheme", &url.Error{Op: "Get", URL: "bmc", Err: errors.New("unsupported protocol scheme")}, false},
+		{"decode error", fmt.Errorf("failed to unmarshal task: %w", &json.SyntaxError{}), false},
+		{"canceled", &url.Error{Op: "Get", URL: "https://bmc", Err: context.Canceled}, false},
+	}
+	for _, tt := range tests {
+		if got := isTransient(tt.err); got != tt.want {
+			t.Errorf("isTransient(%s) = %v, expected %v", tt.name, got, tt.want)
+		}
 	}
+}
+
+func TestRedfishErrorParsing(t *testing.T) {
+	var targets []string
+
+	handlers := testHandlers{}
+	handlers["/redfish/v1/Systems/1"] = func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			// Older firmware without @Redfish.AllowableValues annotations
+			writeJSON(w, map[string]interface{}{"Boot": map[string]interface{}{}})
+			return
+		}
+		var boot SystemBoot
+		json.NewDecoder(r.Body).Decode(&boot)
+		target := boot.Boot.BootSourceOverrideTarget
+		targets = append(targets, target)
 
+		switch target {
+		case "Cd":
+			w.WriteHeader(http.StatusNoContent)
+		case "Hdd":
+			w.WriteHeader(http.StatusForbidden)
+			writeJSON(w, map[string]interface{}{
+				"error": map[string]interface{}{
+					"code":    "Base.1.2.GeneralError",
+					"message": "A general error has occurred.",
+					"@Message.ExtendedInfo": []map[string]string{{
+						"MessageId":  "IDRAC.2.8.SYS403",
+						"Message":    "Unable to complete the operation because the value Hdd is not allowed.",
+						"Resolution": "Enter a valid value and retry the operation.",
+						"Severity":   "Critical",
+					}},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]interface{}{
+				"error": map[string]interface{}{
+					"code": "Base.1.2.GeneralError",
+					"@Message.ExtendedInfo": []map[string]string{{
+						"MessageId": "Base.1.2.PropertyValueNotInList",
+						"Message":   "The value " + target + " for the property BootSourceOverrideTarget is not in the list of acceptable values.",
+					}},
+				},
+			})
+		}
+	}
+	handlers["/redfish/v1/Managers/1/VirtualMedia/CD"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"Inserted": true})
+	}
+	client := &EnhancedClient{Client: newTestClient(t, handlers)}
+	client.resources = &Resources{
+		SystemURI:       "/redfish/v1/Systems/1",
+		ManagerURI:      "/redfish/v1/Managers/1",
+		VirtualMediaURI: "/redfish/v1/Managers/1/VirtualMedia/CD",
+	}
 	ctx := context.Background()
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		_, err := client.GetSystemInfo(ctx)
+	t.Run("ExtendedInfo", func(t *testing.T) {
+		err := client.SetBootOverride(ctx, "Hdd", "Once")
+		var rfErr *RedfishError
+		if !errors.As(err, &rfErr) {
+			t.Fatalf("Expected *RedfishError, got %v", err)
+		}
+		if rfErr.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", rfErr.StatusCode)
+		}
+		if !rfErr.HasMessage("IDRAC.2.8.SYS403") || !rfErr.HasMessage("SYS403") {
+			t.Errorf("Expected SYS403 message, got %+v", rfErr.ExtendedInfo)
+		}
+		if rfErr.ExtendedInfo[0].Resolution == "" || rfErr.ExtendedInfo[0].Severity != "Critical" {
+			t.Errorf("Expected resolution and severity to be parsed, got %+v", rfErr.ExtendedInfo[0])
+		}
+	})
+
+	t.Run("SkipsUnsupportedBootTargets", func(t *testing.T) {
+		targets = nil
+		if err := client.SetVirtualCDBootEnhanced(ctx); err != nil {
+			t.Fatalf("SetVirtualCDBootEnhanced failed: %v", err)
+		}
+		if len(targets) != 3 || targets[2] != "Cd" {
+			t.Errorf("Expected RemoteCd and VirtualCd to be skipped before Cd, got %v", targets)
+		}
+	})
+}
+
+func TestBootTargetNegotiation(t *testing.T) {
+	var patches []BootConfig
+
+	handlers := testHandlers{}
+	handlers["/redfish/v1/Systems/1"] = func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			var boot SystemBoot
+			json.NewDecoder(r.Body).Decode(&boot)
+			patches = append(patches, boot.Boot)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"Boot": map[string]interface{}{
+				"BootSourceOverrideTarget":                          "None",
+				"BootSourceOverrideTarget@Redfish.AllowableValues":  []string{"None", "Pxe", "Hdd", "Cd", "BiosSetup"},
+				"BootSourceOverrideMode@Redfish.AllowableValues":    []string{"UEFI", "Legacy"},
+				"BootSourceOverrideEnabled@Redfish.AllowableValues": []string{"Once", "Continuous", "Disabled"},
+			},
+		})
+	}
+	client := newTestClient(t, handlers)
+	client.resources = &Resources{SystemURI: "/redfish/v1/Systems/1"}
+	ctx := context.Background()
+
+	t.Run("VirtualCDChosenOnce", func(t *testing.T) {
+		target, err := client.ApplyBootOverride(ctx, BootOverride{Mode: "UEFI", Persistence: "Continuous"}, DellVirtualCDTargets)
 		if err != nil {
-			b.Fatalf("GetSystemInfo failed: %v", err)
+			t.Fatalf("ApplyBootOverride failed: %v", err)
+		}
+		if target != "Cd" {
+			t.Errorf("Expected target 'Cd', got '%s'", target)
+		}
+		if len(patches) != 1 {
+			t.Fatalf("Expected a single PATCH, got %d", len(patches))
+		}
+		if patches[0].BootSourceOverrideMode != "UEFI" || patches[0].BootSourceOverrideEnabled != "Continuous" {
+			t.Errorf("Unexpected boot PATCH: %+v", patches[0])
+		}
+	})
+
+	t.Run("DisallowedValuesRejected", func(t *testing.T) {
+		patches = nil
+		if _, err := client.ApplyBootOverride(ctx, BootOverride{Target: "Usb"}, nil); err == nil {
+			t.Error("Expected disallowed target to be rejected")
+		}
+		if _, err := client.ApplyBootOverride(ctx, BootOverride{Target: "Hdd", Mode: "CSM"}, nil); err == nil {
+			t.Error("Expected disallowed mode to be rejected")
+		}
+		if len(patches) != 0 {
+			t.Errorf("Expected no PATCH for disallowed values, got %d", len(patches))
+		}
+	})
+}
+
+func TestBootOrder(t *testing.T) {
+	var written []string
+	var jobTarget string
+
+	handlers := testHandlers{}
+	handlers[dellSystemURI] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"Boot": map[string]interface{}{
+				"BootOrder":   []string{"Boot0001", "Boot0002", "Boot0003"},
+				"BootOptions": map[string]string{"@odata.id": dellSystemURI + "/BootOptions"},
+			},
+			"@Redfish.Settings": map[string]interface{}{
+				"SettingsObject": map[string]string{"@odata.id": dellSystemURI + "/Settings"},
+			},
+		})
+	}
+	handlers[dellSystemURI+"/BootOptions"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, collectionOf(dellSystemURI+"/BootOptions/Boot0001", dellSystemURI+"/BootOptions/Boot0002", dellSystemURI+"/BootOptions/Boot0003"))
+	}
+	options := map[string]BootOption{
+		"Boot0001": {ID: "Boot0001", BootOptionReference: "Boot0001", DisplayName: "PXE Device 1: Integrated NIC 1 Port 1"},
+		"Boot0002": {ID: "Boot0002", BootOptionReference: "Boot0002", DisplayName: "Virtual Optical Drive"},
+		"Boot0003": {ID: "Boot0003", BootOptionReference: "Boot0003", DisplayName: "RAID Controller in SL 3: Disk 0:0:0"},
+	}
+	for ref, option := range options {
+		option := option
+		handlers[dellSystemURI+"/BootOptions/"+ref] = func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, option)
+		}
+	}
+	handlers[dellSystemURI+"/Settings"] = func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Boot struct {
+				BootOrder []string `json:"BootOrder"`
+			} `json:"Boot"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		written = body.Boot.BootOrder
+		w.WriteHeader(http.StatusOK)
+	}
+	handlers[dellManagerURI+"/Jobs"] = func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		jobTarget = body["TargetSettingsURI"]
+		w.Header().Set("Location", dellManagerURI+"/Jobs/JID_123")
+		w.WriteHeader(http.StatusOK)
+	}
+	client := newDellTestClient(t, handlers)
+	ctx := context.Background()
+
+	order, bootOptions, err := client.GetBootOrder(ctx)
+	if err != nil {
+		t.Fatalf("GetBootOrder failed: %v", err)
+	}
+	if len(order) != 3 || len(bootOptions) != 3 {
+		t.Fatalf("Expected 3 boot order entries and options, got %d and %d", len(order), len(bootOptions))
+	}
+
+	if _, err := SelectDiskBootOption(bootOptions, []string{"no-such-serial"}); err == nil {
+		t.Error("Expected an error when no disk matches the root device hints")
+	}
+	disk, err := SelectDiskBootOption(bootOptions, []string{"no-such-serial", "raid controller in sl 3"})
+	if err != nil {
+		t.Fatalf("SelectDiskBootOption failed: %v", err)
+	}
+	if disk.BootOptionReference != "Boot0003" {
+		t.Errorf("Expected disk boot option Boot0003, got %s", disk.BootOptionReference)
+	}
+	if disk, err := SelectDiskBootOption(bootOptions, nil); err != nil || disk.BootOptionReference != "Boot0003" {
+		t.Errorf("Expected the first disk without hints, got %v, %v", disk, err)
+	}
+
+	change, err := client.SetBootOrder(ctx, MoveToFront(order, disk.BootOptionReference))
+	if err != nil {
+		t.Fatalf("SetBootOrder failed: %v", err)
+	}
+	if fmt.Sprint(written) != "[Boot0003 Boot0001 Boot0002]" {
+		t.Errorf("Unexpected boot order written: %v", written)
+	}
+	if change.SettingsURI != dellSystemURI+"/Settings" || jobTarget != change.SettingsURI {
+		t.Errorf("Expected configuration job for %s/Settings, got settings %s and job target %s", dellSystemURI, change.SettingsURI, jobTarget)
+	}
+	if change.JobURI != dellManagerURI+"/Jobs/JID_123" {
+		t.Errorf("Expected job URI %s/Jobs/JID_123, got %s", dellManagerURI, change.JobURI)
+	}
+}
+
+func TestBIOSAttributes(t *testing.T) {
+	var written map[string]interface{}
+
+	handlers := testHandlers{}
+	handlers[dellSystemURI+"/Bios"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"Attributes": map[string]interface{}{
+				"SriovGlobalEnable": "Disabled",
+				"ProcCStates":       "Enabled",
+				"MemFrequency":      3200,
+			},
+			"@Redfish.Settings": map[string]interface{}{
+				"SettingsObject": map[string]string{"@odata.id": dellSystemURI + "/Bios/Settings"},
+			},
+		})
+	}
+	handlers[dellSystemURI+"/Bios/Settings"] = func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Attributes map[string]interface{} `json:"Attributes"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		written = body.Attributes
+		w.WriteHeader(http.StatusOK)
+	}
+	handlers[dellManagerURI+"/Jobs"] = func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", dellManagerURI+"/Jobs/JID_456")
+		w.WriteHeader(http.StatusOK)
+	}
+	client := newDellTestClient(t, handlers)
+	ctx := context.Background()
+
+	current, err := client.GetBIOSAttributes(ctx)
+	if err != nil {
+		t.Fatalf("GetBIOSAttributes failed: %v", err)
+	}
+
+	diffs := DiffBIOSAttributes(current, map[string]interface{}{
+		"SriovGlobalEnable": "Enabled",
+		"ProcCStates":       "Enabled",
+		"MemFrequency":      3200,
+		"WorkloadProfile":   "TelcoOptimizedProfile",
+	})
+	if len(diffs) != 2 {
+		t.Fatalf("Expected 2 differences, got %+v", diffs)
+	}
+	if diffs[0].Name != "SriovGlobalEnable" || diffs[0].Current != "Disabled" || diffs[0].Missing {
+		t.Errorf("Unexpected difference: %+v", diffs[0])
+	}
+	if diffs[1].Name != "WorkloadProfile" || !diffs[1].Missing {
+		t.Errorf("Expected WorkloadProfile to be reported missing, got %+v", diffs[1])
+	}
+
+	change, err := client.SetBIOSAttributes(ctx, map[string]interface{}{"SriovGlobalEnable": "Enabled"})
+	if err != nil {
+		t.Fatalf("SetBIOSAttributes failed: %v", err)
+	}
+	if written["SriovGlobalEnable"] != "Enabled" {
+		t.Errorf("Unexpected attributes written: %v", written)
+	}
+	if change.JobURI != dellManagerURI+"/Jobs/JID_456" {
+		t.Errorf("Expected job URI %s/Jobs/JID_456, got %s", dellManagerURI, change.JobURI)
+	}
+}
+
+func TestInventory(t *testing.T) {
+	const systemURI = "/redfish/v1/Systems/1"
+	const chassisURI = "/redfish/v1/Chassis/1"
+
+	resources := map[string]interface{}{
+		systemURI: map[string]interface{}{
+			"Manufacturer":       "Dell Inc.",
+			"Model":              "PowerEdge R650",
+			"SerialNumber":       "ABC1234",
+			"PowerState":         "On",
+			"Status":             map[string]string{"Health": "OK"},
+			"Processors":         map[string]string{"@odata.id": systemURI + "/Processors"},
+			"Memory":             map[string]string{"@odata.id": systemURI + "/Memory"},
+			"Storage":            map[string]string{"@odata.id": systemURI + "/Storage"},
+			"EthernetInterfaces": map[string]string{"@odata.id": systemURI + "/EthernetInterfaces"},
+			"Links": map[string]interface{}{
+				"Chassis": []map[string]string{{"@odata.id": chassisURI}},
+			},
+		},
+		systemURI + "/Processors":       collectionOf(systemURI + "/Processors/CPU.1"),
+		systemURI + "/Processors/CPU.1": map[string]interface{}{"Id": "CPU.1", "Model": "Xeon Gold 6338N", "TotalCores": 32},
+		systemURI + "/Memory":           collectionOf(systemURI+"/Memory/DIMM.A1", systemURI+"/Memory/DIMM.A2"),
+		systemURI + "/Memory/DIMM.A1":   map[string]interface{}{"Id": "DIMM.A1", "CapacityMiB": 32768, "Status": map[string]string{"State": "Enabled"}},
+		systemURI + "/Memory/DIMM.A2":   map[string]interface{}{"Id": "DIMM.A2", "Status": map[string]string{"State": "Absent"}},
+		systemURI + "/Storage":          collectionOf(systemURI + "/Storage/RAID.1"),
+		systemURI + "/Storage/RAID.1": map[string]interface{}{
+			"Id":                 "RAID.1",
+			"StorageControllers": []map[string]string{{"Model": "PERC H755"}},
+			"Drives":             []map[string]string{{"@odata.id": systemURI + "/Storage/RAID.1/Drives/Disk.0"}},
+		},
+		systemURI + "/Storage/RAID.1/Drives/Disk.0": map[string]interface{}{"Id": "Disk.0", "SerialNumber": "S4ENNF0N123456", "CapacityBytes": 479559942144},
+		systemURI + "/EthernetInterfaces":           collectionOf(systemURI + "/EthernetInterfaces/NIC.1"),
+		systemURI + "/EthernetInterfaces/NIC.1":     map[string]interface{}{"Id": "NIC.1", "MACAddress": "84:16:0c:2a:83:fe"},
+		chassisURI: map[string]interface{}{
+			"NetworkAdapters": map[string]string{"@odata.id": chassisURI + "/NetworkAdapters"},
+		},
+		chassisURI + "/NetworkAdapters": collectionOf(chassisURI + "/NetworkAdapters/NIC.2"),
+		chassisURI + "/NetworkAdapters/NIC.2": map[string]interface{}{
+			"Id":                     "NIC.2",
+			"Model":                  "E810-XXV",
+			"NetworkDeviceFunctions": map[string]string{"@odata.id": chassisURI + "/NetworkAdapters/NIC.2/NetworkDeviceFunctions"},
+		},
+		chassisURI + "/NetworkAdapters/NIC.2/NetworkDeviceFunctions":         collectionOf(chassisURI + "/NetworkAdapters/NIC.2/NetworkDeviceFunctions/NIC.2-1"),
+		chassisURI + "/NetworkAdapters/NIC.2/NetworkDeviceFunctions/NIC.2-1": map[string]interface{}{"Id": "NIC.2-1", "Ethernet": map[string]string{"MACAddress": "B4:96:91:00:00:01"}},
+	}
+
+	handlers := testHandlers{}
+	for uri, resource := range resources {
+		resource := resource
+		handlers[uri] = func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, resource)
 		}
 	}
-}
\ No newline at end of file
+	client := newTestClient(t, handlers)
+	client.resources = &Resources{SystemURI: systemURI}
+
+	inv, err := client.GetInventory(context.Background())
+	if err != nil {
+		t.Fatalf("GetInventory failed: %v", err)
+	}
+
+	if inv.System.Model != "PowerEdge R650" || inv.System.Health != "OK" {
+		t.Errorf("Unexpected system summary: %+v", inv.System)
+	}
+	if len(inv.Processors) != 1 || inv.Processors[0].TotalCores != 32 {
+		t.Errorf("Unexpected processors: %+v", inv.Processors)
+	}
+	if len(inv.Memory) != 1 {
+		t.Errorf("Expected absent DIMMs to be skipped, got %+v", inv.Memory)
+	}
+	drives := inv.Drives()
+	if len(drives) != 1 || drives[0].SerialNumber != "S4ENNF0N123456" {
+		t.Errorf("Unexpected drives: %+v", drives)
+	}
+	if len(inv.Storage) != 1 || len(inv.Storage[0].Controllers) != 1 {
+		t.Errorf("Unexpected storage: %+v", inv.Storage)
+	}
+	if macs := fmt.Sprint(inv.MACAddresses()); macs != "[84:16:0C:2A:83:FE B4:96:91:00:00:01]" {
+		t.Errorf("Unexpected MAC addresses: %s", macs)
+	}
+}
+
+func TestStorageVolumes(t *testing.T) {
+	const storageURI = dellSystemURI + "/Storage/RAID.Integrated.1-1"
+	var created map[string]interface{}
+
+	handlers := testHandlers{}
+	handlers[dellSystemURI] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"Storage": map[string]string{"@odata.id": dellSystemURI + "/Storage"},
+		})
+	}
+	handlers[dellSystemURI+"/Storage"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, collectionOf(storageURI))
+	}
+	handlers[storageURI] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"@odata.id": storageURI,
+			"Id":        "RAID.Integrated.1-1",
+			"StorageControllers": []map[string]interface{}{{
+				"Model": "PERC H755 Front",
+				"Links": map[string]interface{}{
+					"PCIeFunctions": []map[string]string{{"@odata.id": "/redfish/v1/Chassis/System.Embedded.1/PCIeDevices/2-0/PCIeFunctions/2-0-0"}},
+				},
+			}},
+			"Drives": []map[string]string{
+				{"@odata.id": storageURI + "/Drives/Disk.Bay.0:Enclosure.Internal.0-1:RAID.Integrated.1-1"},
+				{"@odata.id": storageURI + "/Drives/Disk.Bay.1:Enclosure.Internal.0-1:RAID.Integrated.1-1"},
+			},
+			"Volumes": map[string]string{"@odata.id": storageURI + "/Volumes"},
+		})
+	}
+	for _, id := range []string{"Disk.Bay.0:Enclosure.Internal.0-1:RAID.Integrated.1-1", "Disk.Bay.1:Enclosure.Internal.0-1:RAID.Integrated.1-1"} {
+		id := id
+		handlers[storageURI+"/Drives/"+id] = func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]interface{}{"Id": id, "CapacityBytes": 479559942144})
+		}
+	}
+	handlers[storageURI+"/Volumes"] = func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			created = nil
+			json.NewDecoder(r.Body).Decode(&created)
+			// Reject RAIDType like iDRAC firmware predating Redfish 2019.1
+			if _, ok := created["RAIDType"]; ok {
+				w.WriteHeader(http.StatusBadRequest)
+				writeJSON(w, map[string]interface{}{
+					"error": map[string]interface{}{
+						"code":    "Base.1.5.GeneralError",
+						"message": "A general error has occurred.",
+						"@Message.ExtendedInfo": []map[string]string{
+							{"MessageId": "Base.1.5.PropertyUnknown", "Message": "The property RAIDType is not in the list of valid properties."},
+						},
+					},
+				})
+				return
+			}
+			w.Header().Set("Location", dellManagerURI+"/Jobs/JID_789")
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"Members": []map[string]string{{"@odata.id": storageURI + "/Volumes/Disk.Virtual.0:RAID.Integrated.1-1"}},
+			"@Redfish.OperationApplyTimeSupport": map[string]interface{}{
+				"SupportedValues": []string{"Immediate", "OnReset"},
+			},
+		})
+	}
+	handlers[storageURI+"/Volumes/Disk.Virtual.0:RAID.Integrated.1-1"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"Id":         "Disk.Virtual.0:RAID.Integrated.1-1",
+			"Name":       "old",
+			"VolumeType": "NonRedundant",
+			"Links": map[string]interface{}{
+				"Drives": []map[string]string{{"@odata.id": storageURI + "/Drives/Disk.Bay.0:Enclosure.Internal.0-1:RAID.Integrated.1-1"}},
+			},
+		})
+	}
+	client := newDellTestClient(t, handlers)
+	ctx := context.Background()
+
+	storage, err := client.GetStorage(ctx, "")
+	if err != nil {
+		t.Fatalf("GetStorage failed: %v", err)
+	}
+	if storage.PCIAddress != "0000:02:00.0" {
+		t.Errorf("Expected PCI address 0000:02:00.0, got %s", storage.PCIAddress)
+	}
+	if len(storage.Drives) != 2 || storage.Drives[0].DiskByPath != "/dev/disk/by-path/pci-0000:02:00.0-scsi-0:0:0:0" {
+		t.Errorf("Unexpected drives: %+v", storage.Drives)
+	}
+	if len(storage.Volumes) != 1 || storage.Volumes[0].DiskByPath != "/dev/disk/by-path/pci-0000:02:00.0-scsi-0:2:0:0" ||
+		len(storage.Volumes[0].Drives) != 1 {
+		t.Errorf("Unexpected volumes: %+v", storage.Volumes)
+	}
+
+	if _, err := client.CreateVolume(ctx, storage, VolumeSpec{Name: "sno-boot", RAIDType: "RAID1", Drives: []string{"Disk.Bay.0:Enclosure.Internal.0-1:RAID.Integrated.1-1"}}); err == nil {
+		t.Error("Expected RAID1 with a single drive to be rejected")
+	}
+
+	change, err := client.CreateVolume(ctx, storage, VolumeSpec{
+		Name:     "sno-boot",
+		RAIDType: "RAID1",
+		Drives:   []string{storage.Drives[0].ID, storage.Drives[1].ID},
+	})
+	if err != nil {
+		t.Fatalf("CreateVolume failed: %v", err)
+	}
+	if created["VolumeType"] != "Mirrored" {
+		t.Errorf("Expected retry with VolumeType Mirrored, got %v", created)
+	}
+	if change.JobURI != dellManagerURI+"/Jobs/JID_789" || !change.Immediate {
+		t.Errorf("Unexpected change: %+v", change)
+	}
+}
+
+func TestFirmwareUpdate(t *testing.T) {
+	const inventoryURI = "/redfish/v1/UpdateService/FirmwareInventory"
+	const jobURI = "/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/JID_111"
+	var imageURI string
+	polls := 0
+
+	handlers := testHandlers{}
+	handlers["/redfish/v1/UpdateService"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"FirmwareInventory": map[string]string{"@odata.id": inventoryURI},
+			"Actions": map[string]interface{}{
+				"#UpdateService.SimpleUpdate": map[string]string{"target": "/redfish/v1/UpdateService/Actions/UpdateService.SimpleUpdate"},
+			},
+		})
+	}
+	handlers[inventoryURI] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, collectionOf(inventoryURI+"/Installed-159-1.10.2", inventoryURI+"/Previous-159-1.9.0", inventoryURI+"/Installed-25227-7.00.60.00"))
+	}
+	for id, entry := range map[string]map[string]string{
+		"Installed-159-1.10.2":       {"Id": "Installed-159-1.10.2", "Name": "BIOS", "Version": "1.10.2"},
+		"Previous-159-1.9.0":         {"Id": "Previous-159-1.9.0", "Name": "BIOS", "Version": "1.9.0"},
+		"Installed-25227-7.00.60.00": {"Id": "Installed-25227-7.00.60.00", "Name": "Integrated Dell Remote Access Controller", "Version": "7.00.60.00"},
+	} {
+		entry := entry
+		handlers[inventoryURI+"/"+id] = func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, entry)
+		}
+	}
+	handlers["/redfish/v1/UpdateService/Actions/UpdateService.SimpleUpdate"] = func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		imageURI = body["ImageURI"]
+		w.Header().Set("Location", jobURI)
+		w.WriteHeader(http.StatusAccepted)
+	}
+	handlers[jobURI] = func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		switch polls {
+		case 1:
+			writeJSON(w, map[string]interface{}{"Id": "JID_111", "JobState": "Downloading", "PercentComplete": 10})
+		case 2:
+			// The BMC web server restarting answers 503 for a while
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			writeJSON(w, map[string]interface{}{"Id": "JID_111", "JobState": "Scheduled", "PercentComplete": 0})
+		}
+	}
+	client := newDellTestClient(t, handlers)
+	client.resources.Root.UpdateService = ODataLink{ODataID: "/redfish/v1/UpdateService"}
+	ctx := context.Background()
+
+	inventory, err := client.GetFirmwareInventory(ctx)
+	if err != nil {
+		t.Fatalf("GetFirmwareInventory failed: %v", err)
+	}
+	if len(inventory) != 2 {
+		t.Fatalf("Expected previous images to be skipped, got %+v", inventory)
+	}
+
+	statuses := CompareFirmware(inventory, &FirmwareManifest{Components: []FirmwareComponent{
+		{Name: "BIOS", Version: "1.13.2"},
+		{Name: "iDRAC", Match: "Remote Access Controller", Version: "7.00.60.00"},
+		{Name: "PERC", Version: "52.26.0"},
+	}})
+	if statuses[0].Current() || !statuses[0].Found() {
+		t.Errorf("Expected BIOS to be outdated, got %+v", statuses[0])
+	}
+	if !statuses[1].Current() {
+		t.Errorf("Expected iDRAC to be current, got %+v", statuses[1])
+	}
+	if statuses[2].Found() {
+		t.Errorf("Expected PERC to be missing, got %+v", statuses[2])
+	}
+
+	change, err := client.SimpleUpdate(ctx, "http://192.168.1.21:8080/OSs/firmware/BIOS_1.13.2.EXE")
+	if err != nil {
+		t.Fatalf("SimpleUpdate failed: %v", err)
+	}
+	if imageURI != "http://192.168.1.21:8080/OSs/firmware/BIOS_1.13.2.EXE" || change.JobURI != jobURI {
+		t.Errorf("Unexpected update request: image %s, change %+v", imageURI, change)
+	}
+
+	task, err := client.WaitForTaskOrReset(ctx, change.JobURI)
+	if err != nil {
+		t.Fatalf("WaitForTaskOrReset failed: %v", err)
+	}
+	if !task.Scheduled() || polls != 3 {
+		t.Errorf("Expected job to be scheduled after 3 polls, got %s after %d", task.State(), polls)
+	}
+}
+
+func TestLogEntries(t *testing.T) {
+	const entriesURI = dellManagerURI + "/LogServices/Sel/Entries"
+
+	handlers := testHandlers{}
+	handlers[dellManagerURI] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"LogServices": map[string]string{"@odata.id": dellManagerURI + "/LogServices"}})
+	}
+	handlers[dellManagerURI+"/LogServices"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, collectionOf(dellManagerURI+"/LogServices/Lclog", dellManagerURI+"/LogServices/Sel"))
+	}
+	handlers[dellManagerURI+"/LogServices/Sel"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"Id": "Sel", "Entries": map[string]string{"@odata.id": entriesURI}})
+	}
+	handlers[entriesURI] = func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("$skip") == "2" {
+			writeJSON(w, map[string]interface{}{"Members": []map[string]string{
+				{"Id": "1", "Created": "2026-10-15T08:00:00-05:00", "Severity": "Critical", "MessageId": "IDRAC.2.9.PSU0003", "Message": "The power supply 1 is lost."},
+			}})
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"Members": []map[string]string{
+				{"Id": "3", "Created": "2026-10-16T10:00:00-05:00", "Severity": "OK", "MessageId": "IDRAC.2.9.SYS1003", "Message": "System CPU Resetting."},
+				{"Id": "2", "Created": "2026-10-16T09:00:00-05:00", "Severity": "Critical", "MessageId": "IDRAC.2.9.PR7", "Message": "Boot from virtual media failed."},
+			},
+			"Members@odata.nextLink": entriesURI + "?$skip=2",
+		})
+	}
+	client := newDellTestClient(t, handlers)
+	ctx := context.Background()
+
+	entries, err := client.GetLogEntries(ctx, LogServiceSEL, LogFilter{})
+	if err != nil {
+		t.Fatalf("GetLogEntries failed: %v", err)
+	}
+	if len(entries) != 3 || entries[2].MessageID != "IDRAC.2.9.PSU0003" {
+		t.Fatalf("Expected 3 entries across both pages, got %+v", entries)
+	}
+
+	since, _ := time.Parse(time.RFC3339, "2026-10-16T00:00:00-05:00")
+	entries, err = client.GetLogEntries(ctx, LogServiceSEL, LogFilter{Since: since, Severities: []string{"critical"}})
+	if err != nil {
+		t.Fatalf("GetLogEntries failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != "2" {
+		t.Errorf("Expected only entry 2 to match time and severity, got %+v", entries)
+	}
+
+	entries, err = client.GetLogEntries(ctx, LogServiceSEL, LogFilter{MessageIDs: []string{"sys1003", "PSU0003"}, Limit: 1})
+	if err != nil {
+		t.Fatalf("GetLogEntries failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != "3" {
+		t.Errorf("Expected the first SYS1003 entry, got %+v", entries)
+	}
+
+	if _, err := client.GetLogEntries(ctx, "IML", LogFilter{}); err == nil {
+		t.Error("Expected an error for a missing log service")
+	}
+}
+
+func TestEventSubscription(t *testing.T) {
+	const subscriptionsURI = "/redfish/v1/EventService/Subscriptions"
+	const destination = "https://192.168.1.10:8443/events"
+	var created map[string]interface{}
+	var deleted []string
+	powerPolls := 0
+
+	handlers := testHandlers{}
+	handlers["/redfish/v1/EventService"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"ServiceEnabled":            true,
+			"ServerSentEventUri":        "/redfish/v1/SSE",
+			"EventTypesForSubscription": []string{"Alert", "MetricReport"},
+			"Subscriptions":             map[string]string{"@odata.id": subscriptionsURI},
+		})
+	}
+	handlers[subscriptionsURI] = func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			writeJSON(w, collectionOf(subscriptionsURI+"/stale"))
+		case "POST":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.Header().Set("Location", subscriptionsURI+"/new")
+			w.WriteHeader(http.StatusCreated)
+		}
+	}
+	for _, id := range []string{"stale", "new"} {
+		uri := subscriptionsURI + "/" + id
+		handlers[uri] = func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "DELETE" {
+				deleted = append(deleted, uri)
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			writeJSON(w, map[string]string{"Destination": destination, "Context": eventContext})
+		}
+	}
+	handlers["/redfish/v1/SSE"] = func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "id: 1\ndata: {\"Events\":[{\"EventType\":\"Alert\",\"MessageId\":\"IDRAC.2.9.SYS1000\",\"Message\":\"System is turning on.\",\"Severity\":\"OK\",\n")
+		fmt.Fprint(w, "data: \"OriginOfCondition\":{\"@odata.id\":\"/redfish/v1/Systems/System.Embedded.1\"}}]}\n\n")
+		fmt.Fprint(w, "id: 2\ndata: {\"Events\":[{\"MessageId\":\"IDRAC.2.9.VRM0021\",\"Message\":\"Virtual Media is attached.\",\"OriginOfCondition\":\"/redfish/v1/Managers/iDRAC.Embedded.1/VirtualMedia/CD\"}]}\n\n")
+	}
+	handlers[dellSystemURI] = func(w http.ResponseWriter, r *http.Request) {
+		powerPolls++
+		state := "Off"
+		if powerPolls > 1 {
+			state = "On"
+		}
+		writeJSON(w, map[string]interface{}{"PowerState": state})
+	}
+	client := newDellTestClient(t, handlers)
+	ctx := context.Background()
+
+	uri, err := client.Subscribe(ctx, destination, "token")
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if uri != subscriptionsURI+"/new" || len(deleted) != 1 || deleted[0] != subscriptionsURI+"/stale" {
+		t.Errorf("Expected the stale subscription to be replaced, got %s, deleted %v", uri, deleted)
+	}
+	if created["Destination"] != destination || created["Context"] != EventContext("token") ||
+		fmt.Sprint(created["EventTypes"]) != "[Alert]" {
+		t.Errorf("Unexpected subscription request: %+v", created)
+	}
+	if err := client.Unsubscribe(ctx, uri); err != nil || len(deleted) != 2 {
+		t.Errorf("Unsubscribe failed: %v, deleted %v", err, deleted)
+	}
+
+	var streamed []Event
+	if err := client.StreamEvents(ctx, "/redfish/v1/SSE", func(e Event) { streamed = append(streamed, e) }); err == nil {
+		t.Error("Expected an error when the stream is closed")
+	}
+	if len(streamed) != 2 {
+		t.Fatalf("Expected 2 streamed events, got %+v", streamed)
+	}
+	if !IsPowerEvent(streamed[0]) || streamed[0].Origin() != dellSystemURI {
+		t.Errorf("Expected a power event on the system, got %+v", streamed[0])
+	}
+	if !IsVirtualMediaEvent(streamed[1]) || IsPowerEvent(streamed[1]) {
+		t.Errorf("Expected a virtual media event, got %+v", streamed[1])
+	}
+
+	// A power event cuts the 10 second poll interval short
+	hub := NewEventHub()
+	client.SetEventHub(hub)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		hub.Publish(streamed[0])
+	}()
+	start := time.Now()
+	if err := client.WaitForSystemPowerOn(ctx); err != nil {
+		t.Fatalf("WaitForSystemPowerOn failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second || powerPolls != 2 {
+		t.Errorf("Expected the power event to wake the wait, took %s and %d polls", elapsed, powerPolls)
+	}
+}
+
+func TestTelemetry(t *testing.T) {
+	const chassisURI = "/redfish/v1/Chassis/System.Embedded.1"
+	var sensorQuery string
+
+	handlers := testHandlers{}
+	handlers[dellSystemURI] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"Links": map[string]interface{}{"Chassis": []map[string]string{{"@odata.id": chassisURI}}},
+		})
+	}
+	handlers[chassisURI] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"Thermal": map[string]string{"@odata.id": chassisURI + "/Thermal"},
+			"Power":   map[string]string{"@odata.id": chassisURI + "/Power"},
+			"Sensors": map[string]string{"@odata.id": chassisURI + "/Sensors"},
+		})
+	}
+	handlers[chassisURI+"/Thermal"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"Fans": []map[string]interface{}{
+				{"MemberId": "0x17||Fan.Embedded.1A", "Name": "System Board Fan1A", "Reading": 5400, "ReadingUnits": "RPM"},
+				{"MemberId": "0x17||Fan.Embedded.2A", "FanName": "System Board Fan2A", "Reading": 6120, "ReadingUnits": "RPM"},
+			},
+			"Temperatures": []map[string]interface{}{
+				{"Name": "CPU1 Temp", "ReadingCelsius": 48, "PhysicalContext": "CPU"},
+				{"Name": "System Board Inlet Temp", "ReadingCelsius": 23, "PhysicalContext": "SystemBoard"},
+			},
+		})
+	}
+	handlers[chassisURI+"/Power"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"PowerControl": []map[string]interface{}{
+				{"Name": "System Power Control", "PowerConsumedWatts": 312, "PowerLimit": map[string]interface{}{"LimitInWatts": nil}},
+			},
+			"PowerSupplies": []map[string]interface{}{
+				{"Name": "PS1 Status", "PowerInputWatts": 160, "Status": map[string]string{"Health": "OK"}},
+			},
+		})
+	}
+	handlers[chassisURI+"/Sensors"] = func(w http.ResponseWriter, r *http.Request) {
+		sensorQuery = r.URL.RawQuery
+		// Members are not expanded, so each one is fetched
+		writeJSON(w, collectionOf(chassisURI+"/Sensors/SystemBoardCPUUsage"))
+	}
+	handlers[chassisURI+"/Sensors/SystemBoardCPUUsage"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"Id": "SystemBoardCPUUsage", "Name": "System Board CPU Usage", "Reading": 12, "ReadingUnits": "%"})
+	}
+	client := newDellTestClient(t, handlers)
+	client.config = &config.IDRACConfig{IP: "192.168.1.228", AuthMethod: "basic"}
+
+	telemetry, err := client.GetTelemetry(context.Background())
+	if err != nil {
+		t.Fatalf("GetTelemetry failed: %v", err)
+	}
+	if telemetry.Chassis != chassisURI || !strings.Contains(sensorQuery, "expand") {
+		t.Errorf("Unexpected chassis %s or sensor query %q", telemetry.Chassis, sensorQuery)
+	}
+	if inlet, ok := telemetry.InletTemperature(); !ok || inlet != 23 {
+		t.Errorf("Expected inlet temperature 23, got %v (%t)", inlet, ok)
+	}
+	if power, ok := telemetry.PowerConsumed(); !ok || power != 312 {
+		t.Errorf("Expected 312 W consumed, got %v (%t)", power, ok)
+	}
+	if fan, ok := telemetry.MaxFanSpeed(); !ok || fan != 6120 || telemetry.Thermal.Fans[1].DisplayName() != "System Board Fan2A" {
+		t.Errorf("Unexpected fans: %+v", telemetry.Thermal.Fans)
+	}
+	if telemetry.Power.PowerControl[0].PowerLimit.LimitInWatts != nil {
+		t.Error("Expected power capping to be disabled")
+	}
+	if len(telemetry.Sensors) != 1 || *telemetry.Sensors[0].Reading != 12 {
+		t.Errorf("Unexpected sensors: %+v", telemetry.Sensors)
+	}
+}
+
+func TestPowerPolicy(t *testing.T) {
+	const chassisURI = "/redfish/v1/Chassis/System.Embedded.1"
+	const attributesURI = "/redfish/v1/Managers/System.Embedded.1/Attributes"
+	var powerPatch, attributesPatch map[string]interface{}
+	taskPolls := 0
+
+	handlers := testHandlers{}
+	handlers[dellSystemURI] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"Links": map[string]interface{}{"Chassis": []map[string]string{{"@odata.id": chassisURI}}},
+		})
+	}
+	handlers[chassisURI] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"Power": map[string]string{"@odata.id": chassisURI + "/Power"}})
+	}
+	handlers[chassisURI+"/Power"] = func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			json.NewDecoder(r.Body).Decode(&powerPatch)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"PowerControl": []map[string]interface{}{{
+				"PowerConsumedWatts": 312,
+				"PowerCapacityWatts": 1100,
+				"PowerLimit":         map[string]interface{}{"LimitInWatts": 450, "LimitException": "LogEventOnly"},
+			}},
+			"Redundancy": []map[string]interface{}{{"Mode": "N+m"}},
+		})
+	}
+	handlers[attributesURI] = func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			json.NewDecoder(r.Body).Decode(&attributesPatch)
+			w.Header().Set("Location", "/redfish/v1/TaskService/Tasks/JID_PSU")
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"Attributes": map[string]interface{}{"ServerPwr.1.PSRedPolicy": "A/B Grid Redundant"},
+		})
+	}
+	handlers["/redfish/v1/TaskService/Tasks/JID_PSU"] = func(w http.ResponseWriter, r *http.Request) {
+		taskPolls++
+		writeJSON(w, map[string]interface{}{"Id": "JID_PSU", "TaskState": "Completed", "TaskStatus": "OK"})
+	}
+	client := newDellTestClient(t, handlers)
+	client.config = &config.IDRACConfig{IP: "192.168.1.228", AuthMethod: "basic"}
+
+	policy, err := client.GetPowerPolicy(context.Background())
+	if err != nil {
+		t.Fatalf("GetPowerPolicy failed: %v", err)
+	}
+	if policy.LimitWatts == nil || *policy.LimitWatts != 450 || policy.LimitException != "LogEventOnly" {
+		t.Errorf("Unexpected power cap: %+v", policy)
+	}
+	if *policy.ConsumedWatts != 312 || *policy.CapacityWatts != 1100 {
+		t.Errorf("Unexpected power readings: %+v", policy)
+	}
+	// iDRAC reports the policy as a system attribute rather than the Redfish Mode
+	if policy.PSURedundancy != "A/B Grid Redundant" {
+		t.Errorf("Expected the iDRAC PSU redundancy policy, got %q", policy.PSURedundancy)
+	}
+
+	if err := client.SetPowerLimit(context.Background(), nil, ""); err != nil {
+		t.Fatalf("SetPowerLimit failed: %v", err)
+	}
+	limit := powerPatch["PowerControl"].([]interface{})[0].(map[string]interface{})["PowerLimit"].(map[string]interface{})
+	if value, ok := limit["LimitInWatts"]; !ok || value != nil {
+		t.Errorf("Expected LimitInWatts null to remove the cap, got %v", limit)
+	}
+	if _, ok := limit["LimitException"]; ok {
+		t.Errorf("Expected LimitException to be left unchanged, got %v", limit)
+	}
+
+	if err := client.SetPSURedundancy(context.Background(), "Not Redundant"); err != nil {
+		t.Fatalf("SetPSURedundancy failed: %v", err)
+	}
+	if attributes := attributesPatch["Attributes"].(map[string]interface{}); attributes["ServerPwr.1.PSRedPolicy"] != "Not Redundant" {
+		t.Errorf("Unexpected system attributes patch: %v", attributesPatch)
+	}
+	if taskPolls != 1 {
+		t.Errorf("Expected the accepted patch to wait for its task, got %d polls", taskPolls)
+	}
+
+	if value := SystemProfileValue("PerformancePerWatt"); value != "PerfPerWattOptimizedDapc" {
+		t.Errorf("Expected PerfPerWattOptimizedDapc, got %s", value)
+	}
+	if value := SystemProfileValue("PerfOptimized"); value != "PerfOptimized" {
+		t.Errorf("Expected BIOS values to pass through, got %s", value)
+	}
+}
+
+func TestResetPolicy(t *testing.T) {
+	var resets []string
+	powerState := "On"
+	// ignoreGraceful simulates an operating system that does not react to ACPI
+	ignoreGraceful := true
+
+	handlers := testHandlers{}
+	handlers[dellSystemURI] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"PowerState": powerState})
+	}
+	handlers[dellSystemURI+"/Actions/ComputerSystem.Reset"] = func(w http.ResponseWriter, r *http.Request) {
+		var req ResetRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		resets = append(resets, req.ResetType)
+		switch req.ResetType {
+		case ResetForceOff:
+			powerState = "Off"
+		case ResetGracefulShutdown:
+			if !ignoreGraceful {
+				powerState = "Off"
+			}
+		case ResetOn:
+			powerState = "On"
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+	client := newDellTestClient(t, handlers)
+	client.SetResetPolicy(ResetPolicy{
+		OffType:         ResetGracefulShutdown,
+		GracefulTimeout: 50 * time.Millisecond,
+		Wait:            WaitPolicy{Interval: time.Millisecond, MaxInterval: 4 * time.Millisecond, Timeout: time.Second},
+	})
+	ctx := context.Background()
+
+	policy := client.resetPolicy()
+	if policy.RestartType != ResetForceRestart || policy.Wait.MaxInterval != 4*time.Millisecond {
+		t.Errorf("Unexpected reset policy defaults: %+v", policy)
+	}
+
+	if err := client.PowerOffSystem(ctx); err != nil {
+		t.Fatalf("PowerOffSystem failed: %v", err)
+	}
+	if fmt.Sprint(resets) != "[GracefulShutdown ForceOff]" {
+		t.Errorf("Expected ForceOff after the graceful timeout, got %v", resets)
+	}
+
+	// A graceful restart is a graceful shutdown followed by power on
+	resets, powerState, ignoreGraceful = nil, "On", false
+	if err := client.RebootSystem(ctx, ResetGracefulRestart, time.Second); err != nil {
+		t.Fatalf("RebootSystem failed: %v", err)
+	}
+	if fmt.Sprint(resets) != "[GracefulShutdown On]" || powerState != "On" {
+		t.Errorf("Expected a graceful shutdown and power on, got %v", resets)
+	}
+
+	resets = nil
+	if err := client.RebootSystem(ctx, ResetPowerCycle, 0); err != nil || fmt.Sprint(resets) != "[PowerCycle]" {
+		t.Errorf("Expected a single PowerCycle reset, got %v (%v)", resets, err)
+	}
+
+	powerState = "Off"
+	if err := client.waitForPowerState(ctx, "On", 10*time.Millisecond); !errors.Is(err, errWaitTimeout) {
+		t.Errorf("Expected a power state timeout, got %v", err)
+	}
+}
+
+func TestWaitForVirtualMedia(t *testing.T) {
+	const mediaURI = dellManagerURI + "/VirtualMedia/CD"
+	var polls []time.Time
+
+	handlers := testHandlers{}
+	handlers[mediaURI] = func(w http.ResponseWriter, r *http.Request) {
+		polls = append(polls, time.Now())
+		writeJSON(w, map[string]interface{}{"Inserted": len(polls) >= 4})
+	}
+	client := &EnhancedClient{Client: newDellTestClient(t, handlers)}
+	client.resources.VirtualMediaURI = mediaURI
+	client.SetResetPolicy(ResetPolicy{
+		Wait: WaitPolicy{Interval: 5 * time.Millisecond, MaxInterval: 20 * time.Millisecond, Timeout: time.Second},
+	})
+	ctx := context.Background()
+
+	if err := client.WaitForVirtualMedia(ctx, true); err != nil {
+		t.Fatalf("WaitForVirtualMedia failed: %v", err)
+	}
+	if len(polls) != 4 {
+		t.Fatalf("Expected 4 polls, got %d", len(polls))
+	}
+	// The delay between polls doubles: 5ms, 10ms, 20ms
+	if last := polls[3].Sub(polls[2]); last < 20*time.Millisecond {
+		t.Errorf("Expected the poll interval to back off to 20ms, got %s", last)
+	}
+
+	client.SetResetPolicy(ResetPolicy{Wait: WaitPolicy{Interval: time.Millisecond, Timeout: 10 * time.Millisecond}})
+	if err := client.WaitForVirtualMedia(ctx, false); !errors.Is(err, errWaitTimeout) {
+		t.Errorf("Expected a virtual media timeout, got %v", err)
+	}
+}
+
+func TestServerConfigurationProfile(t *testing.T) {
+	const exported = `<SystemConfiguration Model="PowerEdge R640" ServiceTag="ABC1234">
+<Component FQDD="BIOS.Setup.1-1">
+<Attribute Name="SysProfile">PerfOptimized</Attribute>
+<!-- <Attribute Name="SystemServiceTag">ABC1234</Attribute> -->
+</Component>
+<Component FQDD="RAID.Integrated.1-1">
+<Component FQDD="Disk.Virtual.0:RAID.Integrated.1-1">
+<Attribute Name="RAIDTypes">RAID 1</Attribute>
+</Component>
+</Component>
+</SystemConfiguration>`
+	var exportPolls int
+	var imported map[string]interface{}
+
+	handlers := testHandlers{}
+	handlers[dellManagerURI+"/Actions/Oem/EID_674_Manager.ExportSystemConfiguration"] = func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/redfish/v1/TaskService/Tasks/JID_EXPORT")
+		w.WriteHeader(http.StatusAccepted)
+	}
+	handlers["/redfish/v1/TaskService/Tasks/JID_EXPORT"] = func(w http.ResponseWriter, r *http.Request) {
+		exportPolls++
+		if exportPolls == 1 {
+			w.WriteHeader(http.StatusAccepted)
+			writeJSON(w, map[string]interface{}{"TaskState": "Running"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml;odata.metadata=minimal")
+		fmt.Fprint(w, exported)
+	}
+	handlers["/redfish/v1/TaskService/Tasks/JID_EXPORT_FAILED"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"TaskState": "Exception",
+			"Messages":  []map[string]string{{"MessageId": "SYS045", "Message": "Unable to export the SystemConfiguration."}},
+		})
+	}
+	handlers["/redfish/v1/TaskService/Tasks/JID_EXPORT_HTML"] = func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html>SystemConfiguration</html>")
+	}
+	handlers[dellManagerURI+"/Actions/Oem/EID_674_Manager.ImportSystemConfiguration"] = func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&imported)
+		w.Header().Set("Location", "/redfish/v1/TaskService/Tasks/JID_IMPORT")
+		w.WriteHeader(http.StatusAccepted)
+	}
+	handlers["/redfish/v1/TaskService/Tasks/JID_IMPORT"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"TaskState":  "Completed",
+			"TaskStatus": "OK",
+			"Messages":   []map[string]string{{"MessageId": "SYS053", "Message": "Successfully imported and applied Server Configuration Profile."}},
+		})
+	}
+	client := newDellTestClient(t, handlers)
+	ctx := context.Background()
+
+	profile, err := client.ExportSCP(ctx, SCPExport{Format: "XML", ExportUse: "Clone"})
+	if err != nil {
+		t.Fatalf("ExportSCP failed: %v", err)
+	}
+	if string(profile) != exported || exportPolls != 2 {
+		t.Errorf("Expected the profile after the export job completed, got %d polls", exportPolls)
+	}
+
+	// A failed job is told from a profile by its state, not by its content
+	var taskErr *TaskError
+	if _, err := client.waitForExport(ctx, "/redfish/v1/TaskService/Tasks/JID_EXPORT_FAILED"); !errors.As(err, &taskErr) {
+		t.Errorf("Expected a TaskError for the failed export, got %v", err)
+	}
+	if _, err := client.waitForExport(ctx, "/redfish/v1/TaskService/Tasks/JID_EXPORT_HTML"); err == nil {
+		t.Error("Expected an HTML page not to be taken for the profile")
+	}
+
+	current, err := ParseSCP(profile)
+	if err != nil {
+		t.Fatalf("ParseSCP failed: %v", err)
+	}
+	// Commented-out read-only attributes are not imported
+	if len(current) != 2 || current[1].Component != "Disk.Virtual.0:RAID.Integrated.1-1" || current[1].Value != "RAID 1" {
+		t.Errorf("Unexpected XML attributes: %+v", current)
+	}
+
+	desired, err := ParseSCP([]byte(`{"SystemConfiguration": {"Components": [{"FQDD": "BIOS.Setup.1-1", "Attributes": [
+		{"Name": "SysProfile", "Value": "PerfPerWattOptimizedDapc", "Set On Import": "True"},
+		{"Name": "ProcCores", "Value": "All", "Set On Import": "True"},
+		{"Name": "SystemServiceTag", "Value": "XYZ9876", "Set On Import": "False"}]}]}}`))
+	if err != nil {
+		t.Fatalf("ParseSCP failed: %v", err)
+	}
+	diffs := DiffSCP(current, desired)
+	if len(diffs) != 2 || diffs[0].Name != "ProcCores" || !diffs[0].Missing ||
+		diffs[1].Current != "PerfOptimized" || diffs[1].Desired != "PerfPerWattOptimizedDapc" {
+		t.Errorf("Unexpected differences: %+v", diffs)
+	}
+
+	task, err := client.ImportSCP(ctx, profile, SCPImport{Target: "BIOS"})
+	if err != nil {
+		t.Fatalf("ImportSCP failed: %v", err)
+	}
+	if imported["ImportBuffer"] != exported || imported["ShutdownType"] != "Graceful" ||
+		imported["ShareParameters"].(map[string]interface{})["Target"] != "BIOS" {
+		t.Errorf("Unexpected import request: %+v", imported)
+	}
+	if len(task.AllMessages()) != 1 {
+		t.Errorf("Expected the import job messages, got %+v", task)
+	}
+
+	data, _ := json.Marshal(imported)
+	if redacted := redactBody(data); strings.Contains(redacted, "ServiceTag") ||
+		!strings.Contains(redacted, fmt.Sprintf("[%d bytes of configuration profile]", len(exported))) {
+		t.Errorf("Expected the import buffer to be redacted, got %s", redacted)
+	}
+}
+
+func TestBMCManagement(t *testing.T) {
+	const accountsURI = "/redfish/v1/AccountService/Accounts"
+	const certificatesURI = dellManagerURI + "/NetworkProtocol/HTTPS/Certificates"
+	patches := make(map[string]map[string]interface{})
+	var csrBody, replaceBody map[string]interface{}
+
+	recordPatch := func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method != "PATCH" {
+			return false
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		patches[r.URL.Path] = body
+		w.WriteHeader(http.StatusOK)
+		return true
+	}
+
+	handlers := testHandlers{}
+	handlers["/redfish/v1/AccountService"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"Accounts": map[string]string{"@odata.id": accountsURI}})
+	}
+	handlers[accountsURI] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, collectionOf(accountsURI+"/1", accountsURI+"/2", accountsURI+"/3"))
+	}
+	for id, user := range map[string]string{"1": "", "2": "root", "3": ""} {
+		id, user := id, user
+		handlers[accountsURI+"/"+id] = func(w http.ResponseWriter, r *http.Request) {
+			if recordPatch(w, r) {
+				return
+			}
+			writeJSON(w, map[string]interface{}{
+				"@odata.id": accountsURI + "/" + id, "Id": id, "UserName": user, "RoleId": "Administrator", "Enabled": user != "",
+			})
+		}
+	}
+	handlers[dellManagerURI] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"EthernetInterfaces": map[string]string{"@odata.id": dellManagerURI + "/EthernetInterfaces"},
+			"NetworkProtocol":    map[string]string{"@odata.id": dellManagerURI + "/NetworkProtocol"},
+		})
+	}
+	handlers[dellManagerURI+"/EthernetInterfaces"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, collectionOf(dellManagerURI+"/EthernetInterfaces/NIC.1"))
+	}
+	handlers[dellManagerURI+"/EthernetInterfaces/NIC.1"] = func(w http.ResponseWriter, r *http.Request) {
+		if recordPatch(w, r) {
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"HostName":      "idrac-sno",
+			"IPv4Addresses": []map[string]string{{"Address": "192.168.1.228"}},
+			"NameServers":   []string{"192.168.1.1", "0.0.0.0"},
+			"VLAN":          map[string]interface{}{"VLANEnable": false, "VLANId": 1},
+		})
+	}
+	handlers[dellManagerURI+"/NetworkProtocol"] = func(w http.ResponseWriter, r *http.Request) {
+		if recordPatch(w, r) {
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"NTP":   map[string]interface{}{"ProtocolEnabled": true, "NTPServers": []string{"pool.ntp.org", ""}},
+			"HTTPS": map[string]interface{}{"Certificates": map[string]string{"@odata.id": certificatesURI}},
+		})
+	}
+	handlers[dellManagerURI+"/Attributes"] = func(w http.ResponseWriter, r *http.Request) {
+		recordPatch(w, r)
+	}
+	handlers[certificatesURI] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, collectionOf(certificatesURI+"/SecurityCertificate.1"))
+	}
+	handlers[certificatesURI+"/SecurityCertificate.1"] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"@odata.id": certificatesURI + "/SecurityCertificate.1",
+			"Subject":   map[string]string{"CommonName": "idrac-sno"},
+			"Issuer":    map[string]string{"CommonName": "idrac-sno"},
+		})
+	}
+	handlers["/redfish/v1/CertificateService/Actions/CertificateService.GenerateCSR"] = func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&csrBody)
+		writeJSON(w, map[string]string{"CSRString": "-----BEGIN CERTIFICATE REQUEST-----"})
+	}
+	handlers["/redfish/v1/CertificateService/Actions/CertificateService.ReplaceCertificate"] = func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&replaceBody)
+		w.WriteHeader(http.StatusNoContent)
+	}
+	client := newDellTestClient(t, handlers)
+	client.config = &config.IDRACConfig{IP: "192.168.1.228", Username: "root", Password: "calvin", AuthMethod: "basic"}
+	client.logger.SetLevelName("debug")
+	ctx := context.Background()
+
+	// Slot 1 is reserved on iDRAC, so the first free slot is 3
+	account, err := client.CreateAccount(ctx, "sno-installer", "s3cret", "Administrator")
+	if err != nil {
+		t.Fatalf("CreateAccount failed: %v", err)
+	}
+	if account.ODataID != accountsURI+"/3" || patches[accountsURI+"/3"]["UserName"] != "sno-installer" {
+		t.Errorf("Expected the account in slot 3, got %+v (patch %v)", account, patches[accountsURI+"/3"])
+	}
+	if _, ok := patches[accountsURI+"/1"]; ok {
+		t.Error("Expected reserved slot 1 to be left alone")
+	}
+
+	if err := client.SetAccountPassword(ctx, "root", "n3w-pass"); err != nil {
+		t.Fatalf("SetAccountPassword failed: %v", err)
+	}
+	if patches[accountsURI+"/2"]["Password"] != "n3w-pass" || client.config.Password != "n3w-pass" {
+		t.Errorf("Expected the client to switch to the new password, got patch %v", patches[accountsURI+"/2"])
+	}
+
+	// The debug log shows the request bodies, but not the passwords in them
+	logFile := filepath.Join(client.logger.Dir(), "openshift_sno_hub_install.log")
+	logged, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(logged), "sno-installer") ||
+		strings.Contains(string(logged), "s3cret") || strings.Contains(string(logged), "n3w-pass") {
+		t.Errorf("Expected the passwords to be masked in the debug log, got:\n%s", logged)
+	}
+	if info, err := os.Stat(logFile); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the log file to be readable by its owner only, got %v (%v)", info.Mode(), err)
+	}
+	if err := client.SetAccountEnabled(ctx, "root", false); err == nil {
+		t.Error("Expected disabling the installer account to be refused")
+	}
+
+	network, err := client.GetManagerNetwork(ctx)
+	if err != nil {
+		t.Fatalf("GetManagerNetwork failed: %v", err)
+	}
+	if len(network.NameServers) != 1 || len(network.NTPServers) != 1 || network.VLANEnabled {
+		t.Errorf("Unexpected BMC network: %+v", network)
+	}
+
+	if err := client.SetNTPServers(ctx, []string{"10.0.0.1", "10.0.0.2"}); err != nil {
+		t.Fatalf("SetNTPServers failed: %v", err)
+	}
+	ntp := patches[dellManagerURI+"/NetworkProtocol"]["NTP"].(map[string]interface{})
+	if ntp["ProtocolEnabled"] != true || len(ntp["NTPServers"].([]interface{})) != 2 {
+		t.Errorf("Unexpected NTP patch: %v", ntp)
+	}
+
+	if err := client.SetDNSServers(ctx, []string{"10.0.0.53"}); err != nil {
+		t.Fatalf("SetDNSServers failed: %v", err)
+	}
+	dns := patches[dellManagerURI+"/Attributes"]["Attributes"].(map[string]interface{})
+	if dns["IPv4Static.1.DNS1"] != "10.0.0.53" || dns["IPv4Static.1.DNS2"] != "0.0.0.0" || dns["IPv4.1.DNSFromDHCP"] != "Disabled" {
+		t.Errorf("Unexpected DNS attributes: %v", dns)
+	}
+	if err := client.SetDNSServers(ctx, []string{"a", "b", "c"}); err == nil {
+		t.Error("Expected more than two DNS servers to be rejected on iDRAC")
+	}
+
+	if err := client.SetVLAN(ctx, 120); err != nil {
+		t.Fatalf("SetVLAN failed: %v", err)
+	}
+	vlan := patches[dellManagerURI+"/EthernetInterfaces/NIC.1"]["VLAN"].(map[string]interface{})
+	if vlan["VLANEnable"] != true || vlan["VLANId"] != float64(120) {
+		t.Errorf("Unexpected VLAN patch: %v", vlan)
+	}
+
+	csr, err := client.GenerateCSR(ctx, CSRRequest{CommonName: "idrac-sno.example.com", Country: "US"})
+	if err != nil {
+		t.Fatalf("GenerateCSR failed: %v", err)
+	}
+	if csr == "" || csrBody["CertificateCollection"].(map[string]interface{})["@odata.id"] != certificatesURI {
+		t.Errorf("Unexpected CSR request: %v", csrBody)
+	}
+
+	if err := client.ReplaceHTTPSCertificate(ctx, "-----BEGIN CERTIFICATE-----"); err != nil {
+		t.Fatalf("ReplaceHTTPSCertificate failed: %v", err)
+	}
+	if replaceBody["CertificateType"] != "PEM" ||
+		replaceBody["CertificateUri"].(map[string]interface{})["@odata.id"] != certificatesURI+"/SecurityCertificate.1" {
+		t.Errorf("Unexpected ReplaceCertificate request: %v", replaceBody)
+	}
+}
+
+func TestTLSTrust(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"Vendor": "Dell"})
+	}))
+	defer server.Close()
+
+	log := logger.NewLogger()
+	defer log.Close()
+
+	dir := t.TempDir()
+	host := strings.TrimPrefix(server.URL, "https://")
+	served := Fingerprint(server.Certificate().Raw)
+	other := strings.Repeat("AB:", 31) + "AB"
+
+	connect := func(cfg config.IDRACConfig) (*Client, error) {
+		cfg.IP, cfg.AuthMethod = host, "basic"
+		client := NewClient(&cfg, log)
+		var root ServiceRoot
+		return client, client.getJSON(context.Background(), "/redfish/v1", &root)
+	}
+
+	// A pin matches regardless of case and colons and works without verify_ssl
+	if _, err := connect(config.IDRACConfig{Fingerprint: strings.ToLower(strings.ReplaceAll(served, ":", ""))}); err != nil {
+		t.Errorf("Expected the pinned certificate to be accepted, got %v", err)
+	}
+	_, err := connect(config.IDRACConfig{Fingerprint: other})
+	var mismatch *FingerprintMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Actual != served {
+		t.Errorf("Expected a fingerprint mismatch, got %v", err)
+	}
+
+	bundle := filepath.Join(dir, "ca.pem")
+	os.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0644)
+	if _, err := connect(config.IDRACConfig{CABundle: bundle}); err != nil {
+		t.Errorf("Expected the CA bundle to verify the certificate, got %v", err)
+	}
+	if _, err := connect(config.IDRACConfig{VerifySSL: true}); err == nil {
+		t.Error("Expected the self-signed certificate to be rejected by the system roots")
+	}
+	if _, err := connect(config.IDRACConfig{CABundle: filepath.Join(dir, "missing.pem")}); err == nil || !strings.Contains(err.Error(), "TLS trust configuration") {
+		t.Errorf("Expected a missing CA bundle to fail every request, got %v", err)
+	}
+
+	knownHosts := filepath.Join(dir, "known_hosts")
+	client, err := connect(config.IDRACConfig{TrustOnFirstUse: true, KnownHosts: knownHosts})
+	if err != nil {
+		t.Fatalf("Expected the first connection to be trusted, got %v", err)
+	}
+	if trusted, _ := client.TrustedFingerprint(); trusted != served {
+		t.Errorf("Expected %s to be recorded, got %q", served, trusted)
+	}
+	if fetched, err := client.FetchFingerprint(context.Background()); err != nil || fetched != served {
+		t.Errorf("Expected FetchFingerprint to return %s, got %q (%v)", served, fetched, err)
+	}
+	if _, err := connect(config.IDRACConfig{TrustOnFirstUse: true, KnownHosts: knownHosts}); err != nil {
+		t.Errorf("Expected the recorded certificate to be accepted, got %v", err)
+	}
+
+	if err := client.TrustFingerprint(other); err != nil {
+		t.Fatalf("TrustFingerprint failed: %v", err)
+	}
+	_, err = connect(config.IDRACConfig{TrustOnFirstUse: true, KnownHosts: knownHosts})
+	if !errors.As(err, &mismatch) || mismatch.Expected != other || mismatch.Source != knownHosts {
+		t.Errorf("Expected a changed certificate to be refused, got %v", err)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	var changes []string
+
+	handlers := testHandlers{}
+	handlers[dellSystemURI] = func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			changes = append(changes, r.Method+" "+r.URL.Path)
+		}
+		writeJSON(w, map[string]interface{}{"PowerState": "Off"})
+	}
+	handlers[dellSystemURI+"/Actions/ComputerSystem.Reset"] = func(w http.ResponseWriter, r *http.Request) {
+		changes = append(changes, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+	client := newDellTestClient(t, handlers)
+	client.config = &config.IDRACConfig{IP: "localhost", Username: "root", AuthMethod: "basic"}
+
+	var plan []string
+	client.SetDryRun(func(format string, args ...interface{}) {
+		plan = append(plan, fmt.Sprintf(format, args...))
+	})
+	ctx := context.Background()
+
+	t.Run("PowerOnPlanned", func(t *testing.T) {
+		if err := client.PowerOnSystem(ctx); err != nil {
+			t.Fatalf("PowerOnSystem failed: %v", err)
+		}
+		if len(changes) != 0 {
+			t.Errorf("Expected no request that changes the BMC, got %v", changes)
+		}
+
+		text := strings.Join(plan, "\n")
+		if !strings.Contains(text, "POST "+client.baseURL+dellSystemURI+"/Actions/ComputerSystem.Reset") {
+			t.Errorf("Expected the reset action in the plan, got:\n%s", text)
+		}
+		if !strings.Contains(text, `"ResetType": "On"`) {
+			t.Errorf("Expected the reset body in the plan, got:\n%s", text)
+		}
+		if !strings.Contains(text, "wait for") {
+			t.Errorf("Expected the power-on wait in the plan, got:\n%s", text)
+		}
+	})
+
+	t.Run("PasswordsMasked", func(t *testing.T) {
+		plan = nil
+		resp, err := client.makeRequest(ctx, "PATCH", dellSystemURI, map[string]interface{}{
+			"UserName": "admin",
+			"Password": "calvin",
+		})
+		if err != nil {
+			t.Fatalf("makeRequest failed: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("Expected a planned 204, got %d", resp.StatusCode)
+		}
+		text := strings.Join(plan, "\n")
+		if strings.Contains(text, "calvin") || !strings.Contains(text, `"Password": "********"`) {
+			t.Errorf("Expected the password to be masked, got:\n%s", text)
+		}
+		if len(changes) != 0 {
+			t.Errorf("Expected no request that changes the BMC, got %v", changes)
+		}
+	})
+}
+
+func TestIDRACClientErrorHandling(t *testing.T) {
+	// Create client with invalid configuration
+	cfg := &config.IDRACConfig{
+		IP:        "invalid-host",
+		Username:  "root",
+		Password:  "password",
+		VerifySSL: false,
+		Timeout:   1, // Very short timeout
+	}
+
+	log := logger.NewLogger()
+	defer log.Close()
+
+	client := NewClient(cfg, log)
+	ctx := context.Background()
+
+	t.Run("ConnectivityFailure", func(t *testing.T) {
+		err := client.CheckConnectivity(ctx)
+		if err == nil {
+			t.Error("Expected connectivity check to fail with invalid host")
+		}
+	})
+}
+
+// Benchmark tests
+func BenchmarkGetSystemInfo(b *testing.B) {
+	client := newTestClient(b, mockIDRACHandlers())
+
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := client.GetSystemInfo(ctx)
+		if err != nil {
+			b.Fatalf("GetSystemInfo failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package idrac
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// serviceRootURI is the well-known Redfish service root
+const serviceRootURI = "/redfish/v1"
+
+// ODataLink represents a Redfish navigation link
+type ODataLink struct {
+	ODataID string `json:"@odata.id"`
+}
+
+// Collection represents a Redfish resource collection
+type Collection struct {
+	Members      []ODataLink `json:"Members"`
+	MembersCount int         `json:"Members@odata.count"`
+	NextLink     string      `json:"Members@odata.nextLink"`
+}
+
+// ServiceRoot represents the Redfish service root resource
+type ServiceRoot struct {
+	Vendor         string    `json:"Vendor"`
+	Product        string    `json:"Product"`
+	RedfishVersion string    `json:"RedfishVersion"`
+	Systems        ODataLink `json:"Systems"`
+	Managers       ODataLink `json:"Managers"`
+	Chassis        ODataLink `json:"Chassis"`
+	SessionService ODataLink `json:"SessionService"`
+	TaskService    ODataLink `json:"TaskService"`
+	UpdateService  ODataLink `json:"UpdateService"`
+	EventService   ODataLink `json:"EventService"`
+	AccountService ODataLink `json:"AccountService"`
+	// CertificateService is not offered by iDRAC8
+	CertificateService ODataLink `json:"CertificateService"`
+}
+
+// Resources holds the Redfish resource URIs discovered on the BMC
+type Resources struct {
+	Root            ServiceRoot
+	Systems         []string
+	Managers        []string
+	VirtualMedia    []string
+	SystemURI       string
+	ManagerURI      string
+	VirtualMediaURI string
+}
+
+// managerResource holds the manager properties used during discovery
+type managerResource struct {
+	VirtualMedia ODataLink `json:"VirtualMedia"`
+}
+
+// systemResource holds the system properties used during discovery
+type systemResource struct {
+	VirtualMedia ODataLink `json:"VirtualMedia"`
+}
+
+// virtualMediaResource holds the virtual media properties used during discovery
+type virtualMediaResource struct {
+	MediaTypes []string `json:"MediaTypes"`
+}
+
+// getJSON fetches a Redfish resource and decodes it into out
+func (c *Client) getJSON(ctx context.Context, uri string, out interface{}) error {
+	resp, err := c.makeRequest(ctx, "GET", uri, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get %s: %w", uri, newRedfishError(resp))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %w", uri, err)
+	}
+
+	return nil
+}
+
+// getCollection fetches every member URI of a Redfish collection, following next links
+func (c *Client) getCollection(ctx context.Context, uri string) ([]string, error) {
+	var members []string
+	for uri != "" {
+		var coll Collection
+		if err := c.getJSON(ctx, uri, &coll); err != nil {
+			return nil, err
+		}
+		for _, m := range coll.Members {
+			members = append(members, m.ODataID)
+		}
+		uri = coll.NextLink
+	}
+	return members, nil
+}
+
+// Discover walks the Redfish service root and caches the system, manager
+// and virtual media URIs used by every other operation
+func (c *Client) Discover(ctx context.Context) (*Resources, error) {
+	c.discoverMu.Lock()
+	defer c.discoverMu.Unlock()
+
+	if c.resources != nil {
+		return c.resources, nil
+	}
+
+	c.logger.LogDebug("Discovering Redfish resources on %s...", c.config.IP)
+
+	res := &Resources{}
+	if err := c.getJSON(ctx, serviceRootURI, &res.Root); err != nil {
+		return nil, fmt.Errorf("failed to read Redfish service root: %w", err)
+	}
+
+	var err error
+	if res.Systems, err = c.getCollection(ctx, linkOrDefault(res.Root.Systems, "/Systems")); err != nil {
+		return nil, fmt.Errorf("failed to list systems: %w", err)
+	}
+	if res.SystemURI, err = selectMember(res.Systems, c.config.SystemID); err != nil {
+		return nil, fmt.Errorf("failed to select system: %w", err)
+	}
+
+	if res.Managers, err = c.getCollection(ctx, linkOrDefault(res.Root.Managers, "/Managers")); err != nil {
+		return nil, fmt.Errorf("failed to list managers: %w", err)
+	}
+	if res.ManagerURI, err = selectMember(res.Managers, c.config.ManagerID); err != nil {
+		return nil, fmt.Errorf("failed to select manager: %w", err)
+	}
+
+	// Older firmware exposes virtual media under the manager, newer iDRAC9
+	// releases and some other BMCs only expose it under the system
+	var manager managerResource
+	if err := c.getJSON(ctx, res.ManagerURI, &manager); err != nil {
+		return nil, fmt.Errorf("failed to read manager: %w", err)
+	}
+	vmCollection := manager.VirtualMedia.ODataID
+	if vmCollection == "" {
+		var system systemResource
+		if err := c.getJSON(ctx, res.SystemURI, &system); err != nil {
+			return nil, fmt.Errorf("failed to read system: %w", err)
+		}
+		vmCollection = system.VirtualMedia.ODataID
+	}
+
+	if vmCollection != "" {
+		if res.VirtualMedia, err = c.getCollection(ctx, vmCollection); err != nil {
+			return nil, fmt.Errorf("failed to list virtual media: %w", err)
+		}
+		res.VirtualMediaURI = c.selectVirtualMediaCD(ctx, res.VirtualMedia)
+	}
+
+	c.logger.LogDebug("Discovered system %s, manager %s, virtual media %s",
+		res.SystemURI, res.ManagerURI, res.VirtualMediaURI)
+
+	c.resources = res
+	return res, nil
+}
+
+// selectVirtualMediaCD picks the virtual media device that accepts CD/DVD images
+func (c *Client) selectVirtualMediaCD(ctx context.Context, members []string) string {
+	for _, uri := range members {
+		var vm virtualMediaResource
+		if err := c.getJSON(ctx, uri, &vm); err != nil {
+			c.logger.LogDebug("Skipping virtual media %s: %v", uri, err)
+			continue
+		}
+		for _, mediaType := range vm.MediaTypes {
+			if mediaType == "CD" || mediaType == "DVD" {
+				return uri
+			}
+		}
+	}
+
+	// Fall back to a member named like a CD device
+	for _, uri := range members {
+		if strings.Contains(strings.ToUpper(lastSegment(uri)), "CD") {
+			return uri
+		}
+	}
+	if len(members) > 0 {
+		return members[0]
+	}
+	return ""
+}
+
+// SystemURI returns the URI of the managed computer system
+func (c *Client) SystemURI(ctx context.Context) (string, error) {
+	res, err := c.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return res.SystemURI, nil
+}
+
+// ManagerURI returns the URI of the BMC manager
+func (c *Client) ManagerURI(ctx context.Context) (string, error) {
+	res, err := c.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return res.ManagerURI, nil
+}
+
+// VirtualMediaURI returns the URI of the virtual CD/DVD device
+func (c *Client) VirtualMediaURI(ctx context.Context) (string, error) {
+	res, err := c.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	if res.VirtualMediaURI == "" {
+		return "", fmt.Errorf("no virtual media device found on %s", res.ManagerURI)
+	}
+	return res.VirtualMediaURI, nil
+}
+
+// isDell reports whether the BMC is a Dell iDRAC, which needs explicit
+// configuration jobs to apply pending settings
+func (c *Client) isDell(ctx context.Context) bool {
+	res, err := c.Discover(ctx)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(res.Root.Vendor), "dell") ||
+		strings.Contains(res.ManagerURI, "iDRAC")
+}
+
+// selectMember returns the member whose last path segment matches id,
+// or the first member when id is empty
+func selectMember(members []string, id string) (string, error) {
+	if len(members) == 0 {
+		return "", fmt.Errorf("collection is empty")
+	}
+	if id == "" {
+		return members[0], nil
+	}
+	for _, uri := range members {
+		if lastSegment(uri) == id {
+			return uri, nil
+		}
+	}
+	return "", fmt.Errorf("member %q not found in %v", id, members)
+}
+
+// linkOrDefault returns the link target or a service root relative default
+func linkOrDefault(link ODataLink, suffix string) string {
+	if link.ODataID != "" {
+		return link.ODataID
+	}
+	return serviceRootURI + suffix
+}
+
+// lastSegment returns the final path segment of a Redfish URI
+func lastSegment(uri string) string {
+	uri = strings.TrimSuffix(uri, "/")
+	if idx := strings.LastIndex(uri, "/"); idx >= 0 {
+		return uri[idx+1:]
+	}
+	return uri
+}
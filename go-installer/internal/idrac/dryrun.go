@@ -0,0 +1,84 @@
+package idrac
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// SetDryRun makes the client pass every request that would change the BMC to
+// plan instead of sending it, and return from waits at once. GET requests and
+// the session login still run, so the plan follows the current state of the
+// BMC. A nil plan turns dry-run mode off.
+func (c *Client) SetDryRun(plan func(format string, args ...interface{})) {
+	c.dryRun = plan
+}
+
+// planRequest records a request in the dry-run plan and returns the
+// 204 No Content response the BMC would send for a synchronous action
+func (c *Client) planRequest(method, endpoint, contentType string, data []byte) *http.Response {
+	switch {
+	case len(data) == 0:
+		c.dryRun("%s %s", method, c.baseURL+endpoint)
+	case strings.HasPrefix(contentType, "application/json"):
+		c.dryRun("%s %s\n%s", method, c.baseURL+endpoint, redactBody(data))
+	default:
+		c.dryRun("%s %s (%d bytes of %s)", method, c.baseURL+endpoint, len(data), contentType)
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusNoContent,
+		Status:     "204 No Content",
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+}
+
+// planWait records a wait that a dry run skips
+func (c *Client) planWait(what string) bool {
+	if c.dryRun == nil {
+		return false
+	}
+	c.dryRun("wait for %s", what)
+	return true
+}
+
+// redactBody indents a JSON request body, masks password values and replaces
+// configuration profiles, which carry the BMC credentials, by their size
+func redactBody(data []byte) string {
+	var body interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return "  " + string(data)
+	}
+	redactPasswords(body)
+	indented, err := json.MarshalIndent(body, "  ", "  ")
+	if err != nil {
+		return "  " + string(data)
+	}
+	return "  " + string(indented)
+}
+
+// redactPasswords replaces the value of every key naming a password and of
+// every SCP ImportBuffer
+func redactPasswords(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if strings.Contains(strings.ToLower(key), "password") {
+				v[key] = "********"
+				continue
+			}
+			if profile, ok := item.(string); ok && key == "ImportBuffer" {
+				v[key] = fmt.Sprintf("[%d bytes of configuration profile]", len(profile))
+				continue
+			}
+			redactPasswords(item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactPasswords(item)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package idrac
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// RedfishError represents an error response returned by a Redfish service
+type RedfishError struct {
+	StatusCode   int
+	Method       string
+	URI          string
+	Code         string
+	Message      string
+	ExtendedInfo []Message
+}
+
+// redfishErrorBody is the standard Redfish error payload
+type redfishErrorBody struct {
+	Error struct {
+		Code         string    `json:"code"`
+		Message      string    `json:"message"`
+		ExtendedInfo []Message `json:"@Message.ExtendedInfo"`
+	} `json:"error"`
+}
+
+// Error implements the error interface
+func (e *RedfishError) Error() string {
+	msg := fmt.Sprintf("%s %s returned status code %d", e.Method, e.URI, e.StatusCode)
+	if len(e.ExtendedInfo) == 0 && e.Message != "" {
+		return msg + ": " + e.Message
+	}
+	for _, info := range e.ExtendedInfo {
+		msg += fmt.Sprintf("; %s: %s", info.MessageID, info.Message)
+		if info.Resolution != "" {
+			msg += " (" + info.Resolution + ")"
+		}
+	}
+	return msg
+}
+
+// HasMessage reports whether any extended message matches id. The id may be a
+// full MessageId such as "IDRAC.2.8.SYS403" or just the message key ("SYS403",
+// "PropertyValueNotInList"), which matches every registry version.
+func (e *RedfishError) HasMessage(id string) bool {
+	for _, info := range e.ExtendedInfo {
+		if info.MessageID == id || messageKey(info.MessageID) == id {
+			return true
+		}
+	}
+	return messageKey(e.Code) == id
+}
+
+// newRedfishError reads and parses an unsuccessful response
+func newRedfishError(resp *http.Response) *RedfishError {
+	body, _ := io.ReadAll(resp.Body)
+	return parseRedfishError(resp, body)
+}
+
+// parseRedfishError builds a RedfishError from an already read response body
+func parseRedfishError(resp *http.Response, body []byte) *RedfishError {
+	rfErr := &RedfishError{StatusCode: resp.StatusCode}
+	if resp.Request != nil {
+		rfErr.Method = resp.Request.Method
+		rfErr.URI = resp.Request.URL.Path
+	}
+
+	var payload redfishErrorBody
+	if err := json.Unmarshal(body, &payload); err == nil {
+		rfErr.Code = payload.Error.Code
+		rfErr.Message = payload.Error.Message
+		rfErr.ExtendedInfo = payload.Error.ExtendedInfo
+	} else if text := strings.TrimSpace(string(body)); text != "" && len(text) < 512 {
+		rfErr.Message = text
+	}
+
+	return rfErr
+}
+
+// messageKey strips the registry prefix and version from a MessageId
+func messageKey(messageID string) string {
+	if idx := strings.LastIndex(messageID, "."); idx >= 0 {
+		return messageID[idx+1:]
+	}
+	return messageID
+}
+
+// IsUnsupportedValue reports whether err is a Redfish rejection of a property
+// value that the service does not allow
+func IsUnsupportedValue(err error) bool {
+	var rfErr *RedfishError
+	if !errors.As(err, &rfErr) {
+		return false
+	}
+	return rfErr.HasMessage("PropertyValueNotInList") ||
+		rfErr.HasMessage("ActionParameterNotSupported") ||
+		rfErr.HasMessage("SYS403")
+}
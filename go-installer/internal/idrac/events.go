@@ -0,0 +1,352 @@
+package idrac
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// eventContext tags the subscriptions created by the installer, so stale ones
+// left behind by an interrupted run can be recognised and removed
+const eventContext = "openshift-sno-hub-installer"
+
+// EventContext returns the Context of a push subscription: the installer tag
+// followed by token. The BMC sends it back with every event, so the listener
+// can tell the events of its subscription from forged ones.
+func EventContext(token string) string {
+	return eventContext + " " + token
+}
+
+// Event represents a single record of a Redfish Event resource
+type Event struct {
+	EventType         string          `json:"EventType"`
+	EventID           string          `json:"EventId"`
+	EventTimestamp    string          `json:"EventTimestamp"`
+	Severity          string          `json:"Severity"`
+	MessageSeverity   string          `json:"MessageSeverity"`
+	Message           string          `json:"Message"`
+	MessageID         string          `json:"MessageId"`
+	OriginOfCondition json.RawMessage `json:"OriginOfCondition"`
+	// Context is the subscription context in the records of older firmware
+	Context string `json:"Context"`
+}
+
+// eventPayload is the Event resource pushed to subscribers or streamed over SSE
+type eventPayload struct {
+	Context string  `json:"Context"`
+	Events  []Event `json:"Events"`
+}
+
+// EventServiceInfo holds the EventService properties used to receive events
+type EventServiceInfo struct {
+	ServiceEnabled            bool      `json:"ServiceEnabled"`
+	ServerSentEventURI        string    `json:"ServerSentEventUri"`
+	EventTypesForSubscription []string  `json:"EventTypesForSubscription"`
+	Subscriptions             ODataLink `json:"Subscriptions"`
+}
+
+// eventSubscription holds the EventDestination properties used for cleanup
+type eventSubscription struct {
+	Destination string `json:"Destination"`
+	Context     string `json:"Context"`
+}
+
+// Level returns the severity of the event, preferring MessageSeverity
+func (e Event) Level() string {
+	return valueOr(e.MessageSeverity, e.Severity)
+}
+
+// Origin returns the URI of the resource the event is about. Redfish sends
+// OriginOfCondition as a link, some firmware as a plain string.
+func (e Event) Origin() string {
+	var link ODataLink
+	if err := json.Unmarshal(e.OriginOfCondition, &link); err == nil && link.ODataID != "" {
+		return link.ODataID
+	}
+	var uri string
+	if err := json.Unmarshal(e.OriginOfCondition, &uri); err == nil {
+		return uri
+	}
+	return ""
+}
+
+// ParseEvents decodes the events of a pushed or streamed Event resource
+func ParseEvents(data []byte) ([]Event, error) {
+	_, events, err := ParsePushedEvents(data)
+	return events, err
+}
+
+// ParsePushedEvents decodes a pushed Event resource and returns the Context
+// of the subscription it was sent for along with its events
+func ParsePushedEvents(data []byte) (string, []Event, error) {
+	var payload eventPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return "", nil, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+	subscription := payload.Context
+	if subscription == "" && len(payload.Events) > 0 {
+		subscription = payload.Events[0].Context
+	}
+	return subscription, payload.Events, nil
+}
+
+// powerMessageIDs are the message IDs iDRAC reports for power state changes
+// (system turning on, turning off, CPU reset) and the standard registry ones
+var powerMessageIDs = []string{"SYS1000", "SYS1001", "SYS1003", "PowerStateChanged", "ResourcePowerStateChanged"}
+
+// IsPowerEvent reports whether the event signals a power state change
+func IsPowerEvent(e Event) bool {
+	for _, id := range powerMessageIDs {
+		if strings.HasSuffix(e.MessageID, id) {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(e.Message), "power")
+}
+
+// IsVirtualMediaEvent reports whether the event concerns the virtual media
+func IsVirtualMediaEvent(e Event) bool {
+	// iDRAC reports virtual media attach and detach in the VRM registry
+	id := e.MessageID[strings.LastIndex(e.MessageID, ".")+1:]
+	return strings.HasPrefix(id, "VRM") ||
+		strings.Contains(e.Origin(), "VirtualMedia") ||
+		strings.Contains(strings.ToLower(e.Message), "virtual media")
+}
+
+// EventHub fans the events received from the BMC out to every subscriber.
+// A nil hub is valid and never delivers events.
+type EventHub struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+}
+
+// NewEventHub creates an event hub without subscribers
+func NewEventHub() *EventHub {
+	return &EventHub{subscribers: make(map[chan Event]struct{})}
+}
+
+// Publish delivers an event to every subscriber. Subscribers that are not
+// keeping up miss the event rather than blocking the receiver.
+func (h *EventHub) Publish(event Event) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving the published events and a function
+// that ends the subscription
+func (h *EventHub) Subscribe() (<-chan Event, func()) {
+	if h == nil {
+		return nil, func() {}
+	}
+
+	ch := make(chan Event, 16)
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers, ch)
+		h.mu.Unlock()
+	}
+}
+
+// SetEventHub makes power and virtual media waits react to the events
+// published on hub instead of only polling
+func (c *Client) SetEventHub(hub *EventHub) {
+	c.events = hub
+}
+
+// waitForEvent sleeps for interval, returning early when an event matching
+// wake arrives on events
+func (c *Client) waitForEvent(ctx context.Context, events <-chan Event, interval time.Duration, wake func(Event) bool) error {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		case event := <-events:
+			if wake(event) {
+				c.logger.LogDebug("Woken up by BMC event %s: %s", event.MessageID, event.Message)
+				return nil
+			}
+		}
+	}
+}
+
+// GetEventService reads the EventService resource
+func (c *Client) GetEventService(ctx context.Context) (*EventServiceInfo, error) {
+	res, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var service EventServiceInfo
+	if err := c.getJSON(ctx, linkOrDefault(res.Root.EventService, "/EventService"), &service); err != nil {
+		return nil, fmt.Errorf("failed to get event service: %w", err)
+	}
+	if service.Subscriptions.ODataID == "" {
+		service.Subscriptions.ODataID = linkOrDefault(res.Root.EventService, "/EventService") + "/Subscriptions"
+	}
+	return &service, nil
+}
+
+// Subscribe registers destination as a push event subscription with the
+// context EventContext(token) and returns the subscription URI. Subscriptions
+// left behind by earlier runs for the same destination are removed first.
+func (c *Client) Subscribe(ctx context.Context, destination, token string) (string, error) {
+	service, err := c.GetEventService(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	members, err := c.getCollection(ctx, service.Subscriptions.ODataID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list event subscriptions: %w", err)
+	}
+	for _, uri := range members {
+		var existing eventSubscription
+		if err := c.getJSON(ctx, uri, &existing); err != nil {
+			continue
+		}
+		if existing.Destination == destination && strings.HasPrefix(existing.Context, eventContext) {
+			c.logger.LogInfo("Removing stale event subscription %s", uri)
+			if err := c.Unsubscribe(ctx, uri); err != nil {
+				c.logger.LogWarn("Failed to remove stale event subscription: %v", err)
+			}
+		}
+	}
+
+	body := map[string]interface{}{
+		"Destination": destination,
+		"Protocol":    "Redfish",
+		"Context":     EventContext(token),
+	}
+	var eventTypes []string
+	for _, eventType := range service.EventTypesForSubscription {
+		if eventType == "Alert" || eventType == "StatusChange" {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	if len(eventTypes) > 0 {
+		body["EventTypes"] = eventTypes
+	}
+
+	resp, err := c.makeRequest(ctx, "POST", service.Subscriptions.ODataID, body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) && resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("failed to subscribe %s to BMC events: %w", destination, newRedfishError(resp))
+	}
+
+	uri := uriPath(resp.Header.Get("Location"))
+	if uri == "" {
+		var created struct {
+			ODataID string `json:"@odata.id"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&created); err != nil || created.ODataID == "" {
+			return "", fmt.Errorf("the BMC did not return the URI of the event subscription")
+		}
+		uri = created.ODataID
+	}
+
+	c.logger.LogSuccess("Subscribed %s to BMC events (%s)", destination, uri)
+	return uri, nil
+}
+
+// Unsubscribe removes an event subscription
+func (c *Client) Unsubscribe(ctx context.Context, uri string) error {
+	resp, err := c.makeRequest(ctx, "DELETE", uri, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to remove event subscription %s: %w", uri, newRedfishError(resp))
+	}
+	return nil
+}
+
+// StreamEvents reads the server-sent event stream at uri and passes every
+// event to handle until the stream ends or ctx is cancelled
+func (c *Client) StreamEvents(ctx context.Context, uri string, handle func(Event)) error {
+	req, _, err := c.newRequest(ctx, "GET", uri, "application/json", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The stream stays open indefinitely, so the request timeout of the API
+	// client must not apply
+	stream := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := stream.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to open event stream: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to open event stream: %w", newRedfishError(resp))
+	}
+
+	c.logger.LogInfo("Receiving BMC events from %s", uri)
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "data:") {
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			continue
+		}
+		if line != "" || data.Len() == 0 {
+			continue
+		}
+
+		// A blank line ends the SSE message
+		events, err := ParseEvents([]byte(data.String()))
+		data.Reset()
+		if err != nil {
+			c.logger.LogDebug("Skipping server-sent event: %v", err)
+			continue
+		}
+		for _, event := range events {
+			handle(event)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("event stream failed: %w", err)
+	}
+	return fmt.Errorf("event stream closed by the BMC")
+}
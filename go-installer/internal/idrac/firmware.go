@@ -0,0 +1,245 @@
+package idrac
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FirmwareManifest declares the firmware baseline of a server
+type FirmwareManifest struct {
+	Name       string              `yaml:"name"`
+	Components []FirmwareComponent `yaml:"components"`
+}
+
+// FirmwareComponent declares the expected version of a firmware component
+type FirmwareComponent struct {
+	// Name identifies the component in reports
+	Name string `yaml:"name"`
+	// Match is matched case-insensitively against the name and ID of the
+	// FirmwareInventory entries; Name is used when empty
+	Match   string `yaml:"match,omitempty"`
+	Version string `yaml:"version"`
+	// File is the update package (Dell DUP) relative to the firmware base URL
+	// or, for HTTP push updates, to the manifest directory
+	File string `yaml:"file,omitempty"`
+	// URL is the full image URL and takes precedence over File
+	URL string `yaml:"url,omitempty"`
+}
+
+// SoftwareInventory represents a Redfish SoftwareInventory resource
+type SoftwareInventory struct {
+	ID         string         `json:"id" yaml:"id"`
+	Name       string         `json:"name" yaml:"name"`
+	Version    string         `json:"version" yaml:"version"`
+	Updateable bool           `json:"updateable" yaml:"updateable"`
+	Status     ResourceStatus `json:"status" yaml:"status"`
+}
+
+// FirmwareStatus is the result of comparing a component with the inventory
+type FirmwareStatus struct {
+	Component FirmwareComponent
+	// Installed lists the versions of the matching inventory entries
+	Installed []string
+}
+
+// Found reports whether the component is present on the server
+func (s FirmwareStatus) Found() bool {
+	return len(s.Installed) > 0
+}
+
+// Current reports whether every matching entry runs the baseline version
+func (s FirmwareStatus) Current() bool {
+	for _, version := range s.Installed {
+		if version != s.Component.Version {
+			return false
+		}
+	}
+	return s.Found()
+}
+
+// updateService holds the UpdateService properties used for firmware updates
+type updateService struct {
+	FirmwareInventory    ODataLink `json:"FirmwareInventory"`
+	MultipartHTTPPushURI string    `json:"MultipartHttpPushUri"`
+	Actions              struct {
+		SimpleUpdate struct {
+			Target string `json:"target"`
+		} `json:"#UpdateService.SimpleUpdate"`
+	} `json:"Actions"`
+}
+
+// LoadFirmwareManifest reads a firmware baseline manifest YAML file
+func LoadFirmwareManifest(path string) (*FirmwareManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read firmware manifest: %w", err)
+	}
+
+	var manifest FirmwareManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal firmware manifest: %w", err)
+	}
+	for _, component := range manifest.Components {
+		if component.Name == "" || component.Version == "" {
+			return nil, fmt.Errorf("firmware manifest %s: every component needs a name and a version", path)
+		}
+	}
+
+	return &manifest, nil
+}
+
+// getUpdateService reads the UpdateService resource
+func (c *Client) getUpdateService(ctx context.Context) (*updateService, error) {
+	res, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var service updateService
+	if err := c.getJSON(ctx, linkOrDefault(res.Root.UpdateService, "/UpdateService"), &service); err != nil {
+		return nil, fmt.Errorf("failed to get update service: %w", err)
+	}
+	return &service, nil
+}
+
+// GetFirmwareInventory retrieves the installed firmware. Entries for previous
+// and available (staged) images reported by iDRAC are skipped.
+func (c *Client) GetFirmwareInventory(ctx context.Context) ([]SoftwareInventory, error) {
+	service, err := c.getUpdateService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	link := service.FirmwareInventory
+	if link.ODataID == "" {
+		link.ODataID = serviceRootURI + "/UpdateService/FirmwareInventory"
+	}
+
+	var entries []SoftwareInventory
+	if err := collectMembers(ctx, c, link, &entries); err != nil {
+		return nil, fmt.Errorf("failed to collect firmware inventory: %w", err)
+	}
+
+	var installed []SoftwareInventory
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.ID, "Previous-") || strings.HasPrefix(entry.ID, "Available-") {
+			continue
+		}
+		installed = append(installed, entry)
+	}
+	return installed, nil
+}
+
+// CompareFirmware matches every manifest component against the firmware inventory
+func CompareFirmware(inventory []SoftwareInventory, manifest *FirmwareManifest) []FirmwareStatus {
+	var statuses []FirmwareStatus
+	for _, component := range manifest.Components {
+		match := strings.ToLower(valueOr(component.Match, component.Name))
+		status := FirmwareStatus{Component: component}
+		for _, entry := range inventory {
+			if strings.Contains(strings.ToLower(entry.Name), match) || strings.Contains(strings.ToLower(entry.ID), match) {
+				status.Installed = append(status.Installed, entry.Version)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// SimpleUpdate asks the BMC to download and install the image at imageURI.
+// The returned change carries the task or job tracking the update.
+func (c *Client) SimpleUpdate(ctx context.Context, imageURI string) (*PendingChange, error) {
+	c.logger.LogInfo("Requesting firmware update from %s", imageURI)
+
+	service, err := c.getUpdateService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := valueOr(service.Actions.SimpleUpdate.Target,
+		serviceRootURI+"/UpdateService/Actions/UpdateService.SimpleUpdate")
+	body := map[string]string{"ImageURI": imageURI}
+	if strings.HasPrefix(imageURI, "https://") {
+		body["TransferProtocol"] = "HTTPS"
+	} else {
+		body["TransferProtocol"] = "HTTP"
+	}
+
+	resp, err := c.makeRequest(ctx, "POST", target, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) {
+		return nil, fmt.Errorf("firmware update from %s failed: %w", imageURI, newRedfishError(resp))
+	}
+
+	return &PendingChange{SettingsURI: target, JobURI: taskMonitorURI(resp), Immediate: true}, nil
+}
+
+// MultipartUpdate pushes a local update package to the BMC through the
+// multipart HTTP push URI
+func (c *Client) MultipartUpdate(ctx context.Context, path string) (*PendingChange, error) {
+	c.logger.LogInfo("Pushing firmware update %s", path)
+
+	service, err := c.getUpdateService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if service.MultipartHTTPPushURI == "" {
+		return nil, fmt.Errorf("the BMC does not support multipart HTTP push updates")
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	params, err := json.Marshal(map[string]interface{}{
+		"Targets":                     []string{},
+		"@Redfish.OperationApplyTime": "OnReset",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal update parameters: %w", err)
+	}
+	if err := writer.WriteField("UpdateParameters", string(params)); err != nil {
+		return nil, fmt.Errorf("failed to write update parameters: %w", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open update package: %w", err)
+	}
+	defer file.Close()
+
+	part, err := writer.CreateFormFile("UpdateFile", filepath.Base(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create update file part: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, fmt.Errorf("failed to read update package: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish multipart body: %w", err)
+	}
+
+	resp, err := c.makeRawRequest(ctx, "POST", service.MultipartHTTPPushURI, writer.FormDataContentType(), buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) {
+		return nil, fmt.Errorf("firmware push of %s failed: %w", path, newRedfishError(resp))
+	}
+
+	return &PendingChange{SettingsURI: service.MultipartHTTPPushURI, JobURI: taskMonitorURI(resp), Immediate: true}, nil
+}
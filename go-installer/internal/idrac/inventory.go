@@ -0,0 +1,330 @@
+package idrac
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The inventory types below use the same camelCase names for the JSON and YAML
+// reports. encoding/json matches the Redfish property names case-insensitively,
+// so the same types decode the Redfish resources.
+
+// ResourceStatus represents the Redfish Status of a resource
+type ResourceStatus struct {
+	State  string `json:"state,omitempty" yaml:"state,omitempty"`
+	Health string `json:"health,omitempty" yaml:"health,omitempty"`
+}
+
+// Processor represents a Redfish Processor resource
+type Processor struct {
+	ID           string         `json:"id" yaml:"id"`
+	Socket       string         `json:"socket,omitempty" yaml:"socket,omitempty"`
+	Manufacturer string         `json:"manufacturer,omitempty" yaml:"manufacturer,omitempty"`
+	Model        string         `json:"model,omitempty" yaml:"model,omitempty"`
+	TotalCores   int            `json:"totalCores,omitempty" yaml:"totalCores,omitempty"`
+	TotalThreads int            `json:"totalThreads,omitempty" yaml:"totalThreads,omitempty"`
+	MaxSpeedMHz  int            `json:"maxSpeedMHz,omitempty" yaml:"maxSpeedMHz,omitempty"`
+	Status       ResourceStatus `json:"status" yaml:"status"`
+}
+
+// MemoryModule represents a Redfish Memory resource
+type MemoryModule struct {
+	ID                string         `json:"id" yaml:"id"`
+	Name              string         `json:"name,omitempty" yaml:"name,omitempty"`
+	CapacityMiB       int            `json:"capacityMiB,omitempty" yaml:"capacityMiB,omitempty"`
+	MemoryDeviceType  string         `json:"memoryDeviceType,omitempty" yaml:"memoryDeviceType,omitempty"`
+	OperatingSpeedMhz int            `json:"operatingSpeedMhz,omitempty" yaml:"operatingSpeedMhz,omitempty"`
+	Manufacturer      string         `json:"manufacturer,omitempty" yaml:"manufacturer,omitempty"`
+	PartNumber        string         `json:"partNumber,omitempty" yaml:"partNumber,omitempty"`
+	SerialNumber      string         `json:"serialNumber,omitempty" yaml:"serialNumber,omitempty"`
+	Status            ResourceStatus `json:"status" yaml:"status"`
+}
+
+// StorageController represents an entry of the StorageControllers array of a Storage resource
+type StorageController struct {
+	Name            string `json:"name,omitempty" yaml:"name,omitempty"`
+	Manufacturer    string `json:"manufacturer,omitempty" yaml:"manufacturer,omitempty"`
+	Model           string `json:"model,omitempty" yaml:"model,omitempty"`
+	FirmwareVersion string `json:"firmwareVersion,omitempty" yaml:"firmwareVersion,omitempty"`
+}
+
+// DriveIdentifier represents a durable name of a drive, such as its NAA WWN
+type DriveIdentifier struct {
+	DurableName       string `json:"durableName" yaml:"durableName"`
+	DurableNameFormat string `json:"durableNameFormat" yaml:"durableNameFormat"`
+}
+
+// Drive represents a Redfish Drive resource
+type Drive struct {
+	ID            string            `json:"id" yaml:"id"`
+	URI           string            `json:"uri" yaml:"uri"`
+	Name          string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Manufacturer  string            `json:"manufacturer,omitempty" yaml:"manufacturer,omitempty"`
+	Model         string            `json:"model,omitempty" yaml:"model,omitempty"`
+	SerialNumber  string            `json:"serialNumber,omitempty" yaml:"serialNumber,omitempty"`
+	CapacityBytes int64             `json:"capacityBytes,omitempty" yaml:"capacityBytes,omitempty"`
+	MediaType     string            `json:"mediaType,omitempty" yaml:"mediaType,omitempty"`
+	Protocol      string            `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	Identifiers   []DriveIdentifier `json:"identifiers,omitempty" yaml:"identifiers,omitempty"`
+	Status        ResourceStatus    `json:"status" yaml:"status"`
+	// DiskByPath is the /dev/disk/by-path name of a non-RAID drive, when known
+	DiskByPath string `json:"diskByPath,omitempty" yaml:"diskByPath,omitempty"`
+}
+
+// Storage represents a Redfish Storage resource with its drives
+type Storage struct {
+	ID          string              `json:"id" yaml:"id"`
+	URI         string              `json:"uri" yaml:"uri"`
+	Name        string              `json:"name,omitempty" yaml:"name,omitempty"`
+	Controllers []StorageController `json:"storageControllers,omitempty" yaml:"storageControllers,omitempty"`
+	// PCIAddress is the PCI address of the controller, when the BMC reports it
+	PCIAddress string   `json:"pciAddress,omitempty" yaml:"pciAddress,omitempty"`
+	Drives     []Drive  `json:"drives,omitempty" yaml:"drives,omitempty"`
+	Volumes    []Volume `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+}
+
+// EthernetInterface represents a Redfish EthernetInterface resource of the system
+type EthernetInterface struct {
+	ID                  string         `json:"id" yaml:"id"`
+	Name                string         `json:"name,omitempty" yaml:"name,omitempty"`
+	MACAddress          string         `json:"macAddress,omitempty" yaml:"macAddress,omitempty"`
+	PermanentMACAddress string         `json:"permanentMACAddress,omitempty" yaml:"permanentMACAddress,omitempty"`
+	SpeedMbps           int            `json:"speedMbps,omitempty" yaml:"speedMbps,omitempty"`
+	LinkStatus          string         `json:"linkStatus,omitempty" yaml:"linkStatus,omitempty"`
+	Status              ResourceStatus `json:"status" yaml:"status"`
+}
+
+// NetworkDeviceFunction represents a Redfish NetworkDeviceFunction of a network adapter
+type NetworkDeviceFunction struct {
+	ID       string `json:"id" yaml:"id"`
+	Ethernet struct {
+		MACAddress          string `json:"macAddress,omitempty" yaml:"macAddress,omitempty"`
+		PermanentMACAddress string `json:"permanentMACAddress,omitempty" yaml:"permanentMACAddress,omitempty"`
+	} `json:"ethernet" yaml:"ethernet"`
+}
+
+// NetworkAdapter represents a Redfish NetworkAdapter resource of the chassis
+type NetworkAdapter struct {
+	ID              string                  `json:"id" yaml:"id"`
+	Manufacturer    string                  `json:"manufacturer,omitempty" yaml:"manufacturer,omitempty"`
+	Model           string                  `json:"model,omitempty" yaml:"model,omitempty"`
+	PartNumber      string                  `json:"partNumber,omitempty" yaml:"partNumber,omitempty"`
+	SerialNumber    string                  `json:"serialNumber,omitempty" yaml:"serialNumber,omitempty"`
+	DeviceFunctions []NetworkDeviceFunction `json:"deviceFunctions,omitempty" yaml:"deviceFunctions,omitempty"`
+}
+
+// storageResource adds the links of a Storage resource. The outer fields
+// take the exact Redfish properties away from the report fields of Storage.
+type storageResource struct {
+	Storage
+	ODataID     string                      `json:"@odata.id"`
+	DriveLinks  []ODataLink                 `json:"Drives"`
+	VolumesLink ODataLink                   `json:"Volumes"`
+	Controllers []storageControllerResource `json:"StorageControllers"`
+}
+
+// storageControllerResource adds the PCIe function links of a storage controller
+type storageControllerResource struct {
+	StorageController
+	Links struct {
+		PCIeFunctions []ODataLink `json:"PCIeFunctions"`
+	} `json:"Links"`
+}
+
+// networkAdapterResource adds the device function link of a NetworkAdapter resource
+type networkAdapterResource struct {
+	NetworkAdapter
+	FunctionsLink ODataLink `json:"NetworkDeviceFunctions"`
+}
+
+// Inventory is the hardware inventory report of a system
+type Inventory struct {
+	CollectedAt        time.Time           `json:"collectedAt" yaml:"collectedAt"`
+	BMC                string              `json:"bmc" yaml:"bmc"`
+	System             InventorySystem     `json:"system" yaml:"system"`
+	Processors         []Processor         `json:"processors" yaml:"processors"`
+	Memory             []MemoryModule      `json:"memory" yaml:"memory"`
+	Storage            []Storage           `json:"storage" yaml:"storage"`
+	EthernetInterfaces []EthernetInterface `json:"ethernetInterfaces" yaml:"ethernetInterfaces"`
+	NetworkAdapters    []NetworkAdapter    `json:"networkAdapters" yaml:"networkAdapters"`
+}
+
+// InventorySystem summarizes the computer system in an inventory report
+type InventorySystem struct {
+	URI          string `json:"uri" yaml:"uri"`
+	Manufacturer string `json:"manufacturer" yaml:"manufacturer"`
+	Model        string `json:"model" yaml:"model"`
+	SerialNumber string `json:"serialNumber" yaml:"serialNumber"`
+	BiosVersion  string `json:"biosVersion" yaml:"biosVersion"`
+	PowerState   string `json:"powerState" yaml:"powerState"`
+	Health       string `json:"health" yaml:"health"`
+}
+
+// inventorySystem holds the system properties and links used to collect the inventory
+type inventorySystem struct {
+	SystemInfo
+	Processors         ODataLink `json:"Processors"`
+	Memory             ODataLink `json:"Memory"`
+	Storage            ODataLink `json:"Storage"`
+	EthernetInterfaces ODataLink `json:"EthernetInterfaces"`
+	Links              struct {
+		Chassis []ODataLink `json:"Chassis"`
+	} `json:"Links"`
+}
+
+// chassisResource holds the chassis properties used to collect the inventory
+type chassisResource struct {
+	NetworkAdapters ODataLink `json:"NetworkAdapters"`
+}
+
+// GetInventory walks the Processors, Memory, Storage, EthernetInterfaces and
+// NetworkAdapters collections of the system. Collections the BMC does not
+// expose are left empty.
+func (c *Client) GetInventory(ctx context.Context) (*Inventory, error) {
+	c.logger.LogInfo("Collecting hardware inventory...")
+
+	systemURI, err := c.SystemURI(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var system inventorySystem
+	if err := c.getJSON(ctx, systemURI, &system); err != nil {
+		return nil, fmt.Errorf("failed to get system: %w", err)
+	}
+
+	inventory := &Inventory{
+		CollectedAt: time.Now().UTC(),
+		BMC:         c.config.IP,
+		System: InventorySystem{
+			URI:          systemURI,
+			Manufacturer: system.Manufacturer,
+			Model:        system.Model,
+			SerialNumber: system.SerialNumber,
+			BiosVersion:  system.BiosVersion,
+			PowerState:   system.PowerState,
+			Health:       system.Status.Health,
+		},
+	}
+
+	if err := collectMembers(ctx, c, system.Processors, &inventory.Processors); err != nil {
+		return nil, fmt.Errorf("failed to collect processors: %w", err)
+	}
+
+	var memory []MemoryModule
+	if err := collectMembers(ctx, c, system.Memory, &memory); err != nil {
+		return nil, fmt.Errorf("failed to collect memory: %w", err)
+	}
+	for _, module := range memory {
+		if module.Status.State != "Absent" {
+			inventory.Memory = append(inventory.Memory, module)
+		}
+	}
+
+	if inventory.Storage, err = c.collectStorage(ctx, system.Storage); err != nil {
+		return nil, err
+	}
+
+	if err := collectMembers(ctx, c, system.EthernetInterfaces, &inventory.EthernetInterfaces); err != nil {
+		return nil, fmt.Errorf("failed to collect ethernet interfaces: %w", err)
+	}
+
+	if len(system.Links.Chassis) > 0 {
+		var chassis chassisResource
+		if err := c.getJSON(ctx, system.Links.Chassis[0].ODataID, &chassis); err != nil {
+			return nil, fmt.Errorf("failed to get chassis: %w", err)
+		}
+		var adapters []networkAdapterResource
+		if err := collectMembers(ctx, c, chassis.NetworkAdapters, &adapters); err != nil {
+			return nil, fmt.Errorf("failed to collect network adapters: %w", err)
+		}
+		for _, adapter := range adapters {
+			if err := collectMembers(ctx, c, adapter.FunctionsLink, &adapter.DeviceFunctions); err != nil {
+				return nil, fmt.Errorf("failed to collect device functions of %s: %w", adapter.ID, err)
+			}
+			inventory.NetworkAdapters = append(inventory.NetworkAdapters, adapter.NetworkAdapter)
+		}
+	}
+
+	c.logger.LogSuccess("Inventory collected: %d processors, %d DIMMs, %d storage, %d ethernet interfaces, %d network adapters",
+		len(inventory.Processors), len(inventory.Memory), len(inventory.Storage),
+		len(inventory.EthernetInterfaces), len(inventory.NetworkAdapters))
+	return inventory, nil
+}
+
+// collectMembers fetches every member of the collection at link into out.
+// A missing link leaves out untouched.
+func collectMembers[T any](ctx context.Context, c *Client, link ODataLink, out *[]T) error {
+	if link.ODataID == "" {
+		return nil
+	}
+
+	members, err := c.getCollection(ctx, link.ODataID)
+	if err != nil {
+		return err
+	}
+	for _, uri := range members {
+		var member T
+		if err := c.getJSON(ctx, uri, &member); err != nil {
+			return err
+		}
+		*out = append(*out, member)
+	}
+	return nil
+}
+
+// MACAddresses returns every MAC address reported by the inventory, upper-cased
+func (inv *Inventory) MACAddresses() []string {
+	var macs []string
+	add := func(mac string) {
+		if mac == "" {
+			return
+		}
+		mac = strings.ToUpper(mac)
+		if !containsString(macs, mac) {
+			macs = append(macs, mac)
+		}
+	}
+
+	for _, iface := range inv.EthernetInterfaces {
+		add(iface.MACAddress)
+		add(iface.PermanentMACAddress)
+	}
+	for _, adapter := range inv.NetworkAdapters {
+		for _, function := range adapter.DeviceFunctions {
+			add(function.Ethernet.MACAddress)
+			add(function.Ethernet.PermanentMACAddress)
+		}
+	}
+	return macs
+}
+
+// Drives returns every drive of every storage subsystem in the inventory
+func (inv *Inventory) Drives() []Drive {
+	var drives []Drive
+	for _, storage := range inv.Storage {
+		drives = append(drives, storage.Drives...)
+	}
+	return drives
+}
+
+// DiskPaths returns the /dev/disk/by-path names derived for the drives and
+// volumes of the inventory
+func (inv *Inventory) DiskPaths() []string {
+	var paths []string
+	for _, storage := range inv.Storage {
+		for _, drive := range storage.Drives {
+			if drive.DiskByPath != "" {
+				paths = append(paths, drive.DiskByPath)
+			}
+		}
+		for _, volume := range storage.Volumes {
+			if volume.DiskByPath != "" {
+				paths = append(paths, volume.DiskByPath)
+			}
+		}
+	}
+	return paths
+}
@@ -25,7 +25,13 @@ type LifecycleControllerInfo struct {
 func (c *Client) GetLifecycleControllerInfo(ctx context.Context) (*LifecycleControllerInfo, error) {
 	c.logger.LogInfo("Getting iDRAC lifecycle controller information...")
 
-	resp, err := c.makeRequest(ctx, "GET", "/redfish/v1/Managers/iDRAC.Embedded.1", nil)
+	managerURI, err := c.ManagerURI(ctx)
+	if err != nil {
+		c.logger.LogError("Failed to get lifecycle controller info: %v", err)
+		return nil, err
+	}
+
+	resp, err := c.makeRequest(ctx, "GET", managerURI, nil)
 	if err != nil {
 		c.logger.LogError("Failed to get lifecycle controller info: %v", err)
 		return nil, err
@@ -33,7 +39,7 @@ func (c *Client) GetLifecycleControllerInfo(ctx context.Context) (*LifecycleCont
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get lifecycle controller info, status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("failed to get lifecycle controller info: %w", newRedfishError(resp))
 	}
 
 	body, err := io.ReadAll(resp.Body)
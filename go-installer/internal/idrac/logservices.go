@@ -0,0 +1,180 @@
+package idrac
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Log service IDs of the iDRAC System Event Log and Lifecycle Controller log
+const (
+	LogServiceSEL = "Sel"
+	LogServiceLC  = "Lclog"
+)
+
+// LogEntry represents a Redfish LogEntry resource
+type LogEntry struct {
+	ID         string `json:"id" yaml:"id"`
+	Created    string `json:"created" yaml:"created"`
+	Severity   string `json:"severity" yaml:"severity"`
+	MessageID  string `json:"messageId,omitempty" yaml:"messageId,omitempty"`
+	Message    string `json:"message" yaml:"message"`
+	EntryType  string `json:"entryType,omitempty" yaml:"entryType,omitempty"`
+	SensorType string `json:"sensorType,omitempty" yaml:"sensorType,omitempty"`
+}
+
+// LogFilter selects log entries. Zero values match every entry.
+type LogFilter struct {
+	Since time.Time
+	Until time.Time
+	// Severities are matched case-insensitively (OK, Warning, Critical)
+	Severities []string
+	// MessageIDs are matched case-insensitively as suffixes, so PR7 matches
+	// IDRAC.2.8.PR7
+	MessageIDs []string
+	// Limit stops paging once that many matching entries were collected
+	Limit int
+}
+
+// logService holds the LogService properties used to read entries
+type logService struct {
+	ID      string    `json:"Id"`
+	Entries ODataLink `json:"Entries"`
+}
+
+// logServicesResource holds the LogServices link of a manager or system
+type logServicesResource struct {
+	LogServices ODataLink `json:"LogServices"`
+}
+
+// logEntryMember is a LogEntry collection member, expanded or a bare link
+type logEntryMember struct {
+	LogEntry
+	ODataID string `json:"@odata.id"`
+}
+
+// logEntryPage is one page of a LogEntry collection
+type logEntryPage struct {
+	Members  []logEntryMember `json:"Members"`
+	NextLink string           `json:"Members@odata.nextLink"`
+}
+
+// Matches reports whether the entry passes the filter
+func (f LogFilter) Matches(entry LogEntry) bool {
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		created, err := time.Parse(time.RFC3339, entry.Created)
+		if err != nil {
+			return false
+		}
+		if !f.Since.IsZero() && created.Before(f.Since) {
+			return false
+		}
+		if !f.Until.IsZero() && created.After(f.Until) {
+			return false
+		}
+	}
+
+	if len(f.Severities) > 0 {
+		found := false
+		for _, severity := range f.Severities {
+			if strings.EqualFold(entry.Severity, severity) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(f.MessageIDs) > 0 {
+		found := false
+		for _, id := range f.MessageIDs {
+			if strings.HasSuffix(strings.ToLower(entry.MessageID), strings.ToLower(id)) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// findLogService returns the entries URI of the log service with the given ID,
+// looking at the manager first and then at the system
+func (c *Client) findLogService(ctx context.Context, id string) (string, error) {
+	res, err := c.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var available []string
+	for _, owner := range []string{res.ManagerURI, res.SystemURI} {
+		var resource logServicesResource
+		if err := c.getJSON(ctx, owner, &resource); err != nil || resource.LogServices.ODataID == "" {
+			continue
+		}
+		members, err := c.getCollection(ctx, resource.LogServices.ODataID)
+		if err != nil {
+			return "", fmt.Errorf("failed to list log services of %s: %w", owner, err)
+		}
+		for _, uri := range members {
+			if !strings.EqualFold(lastSegment(uri), id) {
+				available = append(available, lastSegment(uri))
+				continue
+			}
+			var service logService
+			if err := c.getJSON(ctx, uri, &service); err != nil {
+				return "", fmt.Errorf("failed to read log service %s: %w", id, err)
+			}
+			if service.Entries.ODataID == "" {
+				return uri + "/Entries", nil
+			}
+			return service.Entries.ODataID, nil
+		}
+	}
+
+	return "", fmt.Errorf("log service %s not found, the BMC offers %v", id, available)
+}
+
+// GetLogEntries pages through the entries of a log service and returns the
+// ones matching the filter, in the order reported by the BMC
+func (c *Client) GetLogEntries(ctx context.Context, serviceID string, filter LogFilter) ([]LogEntry, error) {
+	uri, err := c.findLogService(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []LogEntry
+	pages := 0
+	for uri != "" {
+		var page logEntryPage
+		if err := c.getJSON(ctx, uri, &page); err != nil {
+			return nil, fmt.Errorf("failed to read %s entries: %w", serviceID, err)
+		}
+		pages++
+
+		for _, member := range page.Members {
+			entry := member.LogEntry
+			// Most BMCs expand the members inline, others only link them
+			if entry.Created == "" && entry.Message == "" && member.ODataID != "" {
+				if err := c.getJSON(ctx, member.ODataID, &entry); err != nil {
+					return nil, err
+				}
+			}
+			if !filter.Matches(entry) {
+				continue
+			}
+			entries = append(entries, entry)
+			if filter.Limit > 0 && len(entries) == filter.Limit {
+				return entries, nil
+			}
+		}
+		uri = page.NextLink
+	}
+
+	c.logger.LogDebug("Read %d page(s) of %s, %d matching entries", pages, serviceID, len(entries))
+	return entries, nil
+}
@@ -0,0 +1,184 @@
+package idrac
+
+import (
+	"context"
+	"fmt"
+)
+
+// SystemProfileAttribute is the Dell BIOS attribute holding the system profile
+const SystemProfileAttribute = "SysProfile"
+
+// dellPSURedundancyAttribute is the iDRAC system attribute holding the PSU redundancy policy
+const dellPSURedundancyAttribute = "ServerPwr.1.PSRedPolicy"
+
+// systemProfiles maps the vendor-neutral system profile names to SysProfile values
+var systemProfiles = map[string]string{
+	"Performance":          "PerfOptimized",
+	"PerformancePerWatt":   "PerfPerWattOptimizedDapc",
+	"PerformancePerWattOS": "PerfPerWattOptimizedOs",
+	"Custom":               "Custom",
+}
+
+// PowerPolicy is the power cap and PSU redundancy policy of the chassis
+type PowerPolicy struct {
+	// LimitWatts is nil when power capping is disabled
+	LimitWatts     *float64
+	LimitException string
+	ConsumedWatts  *float64
+	CapacityWatts  *float64
+	PSURedundancy  string
+}
+
+// attributesResource is a Dell manager attributes resource
+type attributesResource struct {
+	Attributes map[string]interface{} `json:"Attributes"`
+}
+
+// SystemProfileValue returns the SysProfile BIOS value of a system profile
+// name; unknown names are taken as the BIOS value itself
+func SystemProfileValue(name string) string {
+	return valueOr(systemProfiles[name], name)
+}
+
+// powerURI returns the URI of the Power resource of the chassis
+func (c *Client) powerURI(ctx context.Context) (string, error) {
+	chassisURI, err := c.ChassisURI(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var chassis chassisTelemetry
+	if err := c.getJSON(ctx, chassisURI, &chassis); err != nil {
+		return "", fmt.Errorf("failed to get chassis: %w", err)
+	}
+	return valueOr(chassis.Power.ODataID, chassisURI+"/Power"), nil
+}
+
+// GetPower reads the Power resource of the chassis
+func (c *Client) GetPower(ctx context.Context) (*Power, error) {
+	uri, err := c.powerURI(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var power Power
+	if err := c.getJSON(ctx, uri, &power); err != nil {
+		return nil, fmt.Errorf("failed to get power: %w", err)
+	}
+	return &power, nil
+}
+
+// GetPowerPolicy reads the power cap of the first PowerControl and the PSU
+// redundancy policy
+func (c *Client) GetPowerPolicy(ctx context.Context) (*PowerPolicy, error) {
+	power, err := c.GetPower(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &PowerPolicy{}
+	if len(power.PowerControl) > 0 {
+		control := power.PowerControl[0]
+		policy.LimitWatts = control.PowerLimit.LimitInWatts
+		policy.LimitException = control.PowerLimit.LimitException
+		policy.ConsumedWatts = control.PowerConsumedWatts
+		policy.CapacityWatts = control.PowerCapacityWatts
+	}
+
+	if c.isDell(ctx) {
+		attributes, err := c.getSystemAttributes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if value, ok := attributes[dellPSURedundancyAttribute]; ok {
+			policy.PSURedundancy = fmt.Sprint(value)
+		}
+	} else if len(power.Redundancy) > 0 {
+		policy.PSURedundancy = power.Redundancy[0].Mode
+	}
+
+	return policy, nil
+}
+
+// SetPowerLimit sets the power cap of the first PowerControl. A nil limit
+// disables capping; an empty exception leaves the exception action unchanged.
+func (c *Client) SetPowerLimit(ctx context.Context, limitWatts *float64, exception string) error {
+	uri, err := c.powerURI(ctx)
+	if err != nil {
+		return err
+	}
+
+	limit := map[string]interface{}{"LimitInWatts": limitWatts}
+	if exception != "" {
+		limit["LimitException"] = exception
+	}
+	body := map[string]interface{}{
+		"PowerControl": []map[string]interface{}{{"PowerLimit": limit}},
+	}
+
+	resp, err := c.makeRequest(ctx, "PATCH", uri, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) {
+		return fmt.Errorf("failed to set power limit: %w", newRedfishError(resp))
+	}
+	return c.completeAction(ctx, resp)
+}
+
+// SetPSURedundancy sets the PSU redundancy policy: the ServerPwr.1.PSRedPolicy
+// attribute on iDRAC, the Mode of the first Redundancy group elsewhere
+func (c *Client) SetPSURedundancy(ctx context.Context, policy string) error {
+	var uri string
+	var body map[string]interface{}
+	if c.isDell(ctx) {
+		var err error
+		if uri, err = c.systemAttributesURI(ctx); err != nil {
+			return err
+		}
+		body = map[string]interface{}{"Attributes": map[string]string{dellPSURedundancyAttribute: policy}}
+	} else {
+		var err error
+		if uri, err = c.powerURI(ctx); err != nil {
+			return err
+		}
+		body = map[string]interface{}{"Redundancy": []map[string]string{{"Mode": policy}}}
+	}
+
+	resp, err := c.makeRequest(ctx, "PATCH", uri, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) {
+		return fmt.Errorf("failed to set PSU redundancy policy: %w", newRedfishError(resp))
+	}
+	return c.completeAction(ctx, resp)
+}
+
+// systemAttributesURI returns the Dell attributes resource of the system
+// (ServerPwr, ServerTopology, ...), which iDRAC exposes as a manager
+func (c *Client) systemAttributesURI(ctx context.Context) (string, error) {
+	systemURI, err := c.SystemURI(ctx)
+	if err != nil {
+		return "", err
+	}
+	return serviceRootURI + "/Managers/" + lastSegment(systemURI) + "/Attributes", nil
+}
+
+// getSystemAttributes reads the Dell system attributes
+func (c *Client) getSystemAttributes(ctx context.Context) (map[string]interface{}, error) {
+	uri, err := c.systemAttributesURI(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var resource attributesResource
+	if err := c.getJSON(ctx, uri, &resource); err != nil {
+		return nil, fmt.Errorf("failed to get system attributes: %w", err)
+	}
+	return resource.Attributes, nil
+}
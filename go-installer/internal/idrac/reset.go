@@ -0,0 +1,238 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Reset types of the ComputerSystem.Reset action
+const (
+	ResetOn               = "On"
+	ResetForceOff         = "ForceOff"
+	ResetGracefulShutdown = "GracefulShutdown"
+	ResetGracefulRestart  = "GracefulRestart"
+	ResetForceRestart     = "ForceRestart"
+	ResetPushPowerButton  = "PushPowerButton"
+	ResetPowerCycle       = "PowerCycle"
+	ResetNmi              = "Nmi"
+)
+
+// ResetTypes lists the reset types accepted by ResetSystem
+var ResetTypes = []string{
+	ResetOn, ResetForceOff, ResetGracefulShutdown, ResetGracefulRestart,
+	ResetForceRestart, ResetPushPowerButton, ResetPowerCycle, ResetNmi,
+}
+
+// errWaitTimeout is returned when the system does not reach a power or
+// virtual media state in time
+var errWaitTimeout = errors.New("timed out waiting for state")
+
+// WaitPolicy controls the power state and virtual media waits. The poll
+// interval starts at Interval and doubles up to MaxInterval until Timeout has
+// passed.
+type WaitPolicy struct {
+	Interval    time.Duration
+	MaxInterval time.Duration
+	Timeout     time.Duration
+}
+
+// ResetPolicy selects the reset types used to power off and restart the system
+type ResetPolicy struct {
+	// OffType is GracefulShutdown (the default), PushPowerButton or
+	// ForceOff; a graceful type is followed by ForceOff after GracefulTimeout
+	OffType string
+	// RestartType is ForceRestart (the default), GracefulRestart or PowerCycle
+	RestartType string
+	// GracefulTimeout is how long a graceful reset may take before the
+	// system is forced off
+	GracefulTimeout time.Duration
+	Wait            WaitPolicy
+}
+
+// DefaultResetPolicy returns the reset policy used when none is configured
+func DefaultResetPolicy() ResetPolicy {
+	return ResetPolicy{
+		OffType:         ResetGracefulShutdown,
+		RestartType:     ResetForceRestart,
+		GracefulTimeout: 5 * time.Minute,
+		Wait: WaitPolicy{
+			Interval:    10 * time.Second,
+			MaxInterval: time.Minute,
+			Timeout:     5 * time.Minute,
+		},
+	}
+}
+
+// SetResetPolicy sets the reset types and waits used by PowerOffSystem,
+// RestartSystem and the power state waits. Zero fields keep their defaults.
+func (c *Client) SetResetPolicy(policy ResetPolicy) {
+	c.reset = policy
+}
+
+// resetPolicy returns the configured reset policy with defaults filled in
+func (c *Client) resetPolicy() ResetPolicy {
+	policy, defaults := c.reset, DefaultResetPolicy()
+	policy.OffType = valueOr(policy.OffType, defaults.OffType)
+	policy.RestartType = valueOr(policy.RestartType, defaults.RestartType)
+	if policy.GracefulTimeout <= 0 {
+		policy.GracefulTimeout = defaults.GracefulTimeout
+	}
+	if policy.Wait.Interval <= 0 {
+		policy.Wait.Interval = defaults.Wait.Interval
+	}
+	if policy.Wait.MaxInterval < policy.Wait.Interval {
+		policy.Wait.MaxInterval = maxDuration(defaults.Wait.MaxInterval, policy.Wait.Interval)
+	}
+	if policy.Wait.Timeout <= 0 {
+		policy.Wait.Timeout = defaults.Wait.Timeout
+	}
+	return policy
+}
+
+// ShutdownSystem powers the system off with resetType. When a graceful reset
+// is rejected, or has not turned the system off after gracefulTimeout, the
+// system is forced off.
+func (c *Client) ShutdownSystem(ctx context.Context, resetType string, gracefulTimeout time.Duration) error {
+	powerState, err := c.GetSystemPowerState(ctx)
+	if err != nil {
+		return err
+	}
+	if powerState == "Off" {
+		c.logger.LogInfo("System is already powered off")
+		return nil
+	}
+
+	if resetType != ResetForceOff {
+		if err := c.gracefulReset(ctx, resetType, gracefulTimeout); !errors.Is(err, errWaitTimeout) {
+			return err
+		}
+	}
+
+	if err := c.ResetSystem(ctx, ResetForceOff); err != nil {
+		c.logger.LogError("Failed to power off system: %v", err)
+		return err
+	}
+	c.logger.LogSuccess("System power off command sent successfully")
+	return c.WaitForSystemPowerOff(ctx)
+}
+
+// gracefulReset sends resetType and waits up to timeout for the system to
+// turn off. errWaitTimeout means the system has to be forced off.
+func (c *Client) gracefulReset(ctx context.Context, resetType string, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = c.resetPolicy().GracefulTimeout
+	}
+
+	if err := c.ResetSystem(ctx, resetType); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		c.logger.LogWarn("%s rejected (%v), forcing power off", resetType, err)
+		return errWaitTimeout
+	}
+
+	c.logger.LogInfo("%s sent, waiting up to %s for the operating system to shut down...", resetType, timeout)
+	err := c.waitForPowerState(ctx, "Off", timeout)
+	if errors.Is(err, errWaitTimeout) {
+		c.logger.LogWarn("System still on %s after %s, forcing power off", timeout, resetType)
+	}
+	return err
+}
+
+// RebootSystem restarts the system with resetType. GracefulRestart is carried
+// out as a graceful shutdown followed by power on, since the power state does
+// not show whether an in-place restart happened; the system is forced off
+// when the shutdown takes longer than gracefulTimeout.
+func (c *Client) RebootSystem(ctx context.Context, resetType string, gracefulTimeout time.Duration) error {
+	if resetType != ResetGracefulRestart {
+		if err := c.ResetSystem(ctx, resetType); err != nil {
+			c.logger.LogError("Failed to restart system: %v", err)
+			return err
+		}
+		c.logger.LogSuccess("System restart command sent successfully")
+		return nil
+	}
+
+	if err := c.ShutdownSystem(ctx, ResetGracefulShutdown, gracefulTimeout); err != nil {
+		return err
+	}
+	if err := c.ResetSystem(ctx, ResetOn); err != nil {
+		c.logger.LogError("Failed to power on system: %v", err)
+		return err
+	}
+	c.logger.LogSuccess("System restarted gracefully")
+	return c.WaitForSystemPowerOn(ctx)
+}
+
+// waitForPowerState polls the power state until it is state, backing off
+// exponentially between polls and waking up early on power events
+func (c *Client) waitForPowerState(ctx context.Context, state string, timeout time.Duration) error {
+	if c.planWait("the system to power " + state) {
+		return nil
+	}
+
+	err := c.waitForState(ctx, "power state "+state, timeout, IsPowerEvent, func() (string, bool, error) {
+		powerState, err := c.GetSystemPowerState(ctx)
+		if err != nil {
+			c.logger.LogWarn("Failed to get power state: %v", err)
+			return "unknown", false, nil
+		}
+		return powerState, powerState == state, nil
+	})
+	if err != nil {
+		return err
+	}
+	c.logger.LogSuccess("System is now powered %s", state)
+	return nil
+}
+
+// waitForState calls check until it reports that the state named what is
+// reached or timeout has passed. The poll interval follows the wait policy,
+// and events matching wake cut a wait short. check returns the current state
+// for the log; an error from it ends the wait.
+func (c *Client) waitForState(ctx context.Context, what string, timeout time.Duration, wake func(Event) bool, check func() (string, bool, error)) error {
+	wait := c.resetPolicy().Wait
+	events, unsubscribe := c.events.Subscribe()
+	defer unsubscribe()
+
+	deadline := time.Now().Add(timeout)
+	interval := wait.Interval
+	for {
+		current, reached, err := check()
+		if err != nil {
+			return err
+		}
+		if reached {
+			return nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("%s not reached after %s: %w", what, timeout, errWaitTimeout)
+		}
+
+		c.logger.LogInfo("Waiting for %s, currently %s... (next check in %s)", what, current, minDuration(interval, remaining))
+		if err := c.waitForEvent(ctx, events, minDuration(interval, remaining), wake); err != nil {
+			return err
+		}
+		interval = minDuration(2*interval, wait.MaxInterval)
+	}
+}
+
+// minDuration returns the shorter of two durations
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// maxDuration returns the longer of two durations
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,313 @@
+package idrac
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SCPExport selects what ExportSystemConfiguration captures
+type SCPExport struct {
+	// Format is XML or JSON
+	Format string
+	// Target is ALL or a comma-separated list of BIOS, IDRAC, NIC, RAID, ...
+	Target string
+	// ExportUse is Default, Clone (for replaying on other hardware) or Replace
+	ExportUse string
+}
+
+// SCPImport controls how ImportSystemConfiguration applies a profile
+type SCPImport struct {
+	// Target is ALL or a comma-separated list of components to import
+	Target string
+	// ShutdownType is Graceful, Forced or NoReboot
+	ShutdownType string
+}
+
+// SCPAttribute is a single attribute of a Server Configuration Profile
+type SCPAttribute struct {
+	// Component is the FQDD of the component the attribute belongs to
+	Component string
+	Name      string
+	Value     string
+}
+
+// SCPDifference describes a profile attribute whose value differs from the
+// current configuration
+type SCPDifference struct {
+	Component string
+	Name      string
+	Current   string
+	Desired   string
+	// Missing is set when the current configuration does not export the
+	// attribute, e.g. passwords or settings of absent hardware
+	Missing bool
+}
+
+// scpXMLComponent is a component of an XML profile
+type scpXMLComponent struct {
+	FQDD       string `xml:"FQDD,attr"`
+	Attributes []struct {
+		Name  string `xml:"Name,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"Attribute"`
+	Components []scpXMLComponent `xml:"Component"`
+}
+
+// scpXMLProfile is an XML profile; read-only attributes are exported as XML
+// comments and therefore skipped
+type scpXMLProfile struct {
+	XMLName    xml.Name          `xml:"SystemConfiguration"`
+	Components []scpXMLComponent `xml:"Component"`
+}
+
+// scpJSONComponent is a component of a JSON profile
+type scpJSONComponent struct {
+	FQDD       string `json:"FQDD"`
+	Attributes []struct {
+		Name        string      `json:"Name"`
+		Value       interface{} `json:"Value"`
+		SetOnImport string      `json:"Set On Import"`
+	} `json:"Attributes"`
+	Components []scpJSONComponent `json:"Components"`
+}
+
+// scpJSONProfile is a JSON profile
+type scpJSONProfile struct {
+	SystemConfiguration struct {
+		Components []scpJSONComponent `json:"Components"`
+	} `json:"SystemConfiguration"`
+}
+
+// ParseSCP returns the attributes of an XML or JSON Server Configuration
+// Profile that are applied on import
+func ParseSCP(data []byte) ([]SCPAttribute, error) {
+	var attributes []SCPAttribute
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var profile scpJSONProfile
+		if err := json.Unmarshal(trimmed, &profile); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON profile: %w", err)
+		}
+		var walk func([]scpJSONComponent)
+		walk = func(components []scpJSONComponent) {
+			for _, component := range components {
+				for _, attr := range component.Attributes {
+					if strings.EqualFold(attr.SetOnImport, "False") {
+						continue
+					}
+					attributes = append(attributes, SCPAttribute{Component: component.FQDD, Name: attr.Name, Value: fmt.Sprint(attr.Value)})
+				}
+				walk(component.Components)
+			}
+		}
+		walk(profile.SystemConfiguration.Components)
+		return attributes, nil
+	}
+
+	var profile scpXMLProfile
+	if err := xml.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse XML profile: %w", err)
+	}
+	var walk func([]scpXMLComponent)
+	walk = func(components []scpXMLComponent) {
+		for _, component := range components {
+			for _, attr := range component.Attributes {
+				attributes = append(attributes, SCPAttribute{Component: component.FQDD, Name: attr.Name, Value: strings.TrimSpace(attr.Value)})
+			}
+			walk(component.Components)
+		}
+	}
+	walk(profile.Components)
+	return attributes, nil
+}
+
+// DiffSCP returns the desired attributes whose value differs from current,
+// sorted by component and name
+func DiffSCP(current, desired []SCPAttribute) []SCPDifference {
+	values := make(map[string]string, len(current))
+	for _, attr := range current {
+		values[attr.Component+"|"+attr.Name] = attr.Value
+	}
+
+	var diffs []SCPDifference
+	for _, attr := range desired {
+		value, ok := values[attr.Component+"|"+attr.Name]
+		if ok && value == attr.Value {
+			continue
+		}
+		diffs = append(diffs, SCPDifference{
+			Component: attr.Component,
+			Name:      attr.Name,
+			Current:   value,
+			Desired:   attr.Value,
+			Missing:   !ok,
+		})
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Component != diffs[j].Component {
+			return diffs[i].Component < diffs[j].Component
+		}
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs
+}
+
+// ExportSCP exports the Server Configuration Profile through the Dell OEM
+// ExportSystemConfiguration action and returns its content once the export
+// job completes
+func (c *Client) ExportSCP(ctx context.Context, export SCPExport) ([]byte, error) {
+	body := map[string]interface{}{
+		"ExportFormat":    strings.ToUpper(valueOr(export.Format, "XML")),
+		"ShareParameters": map[string]string{"Target": valueOr(export.Target, "ALL")},
+		"ExportUse":       valueOr(export.ExportUse, "Default"),
+		"IncludeInExport": "Default",
+	}
+
+	uri, err := c.scpAction(ctx, "ExportSystemConfiguration", body)
+	if err != nil {
+		return nil, err
+	}
+	return c.waitForExport(ctx, uri)
+}
+
+// PreviewSCP runs the Dell OEM ImportSystemConfigurationPreview action, which
+// validates profile against the server without changing it, and returns the
+// finished preview job
+func (c *Client) PreviewSCP(ctx context.Context, profile []byte, target string) (*Task, error) {
+	body := map[string]interface{}{
+		"ImportBuffer":    string(profile),
+		"ShareParameters": map[string]string{"Target": valueOr(target, "ALL")},
+	}
+
+	uri, err := c.scpAction(ctx, "ImportSystemConfigurationPreview", body)
+	if err != nil {
+		return nil, err
+	}
+	return c.WaitForTask(ctx, uri)
+}
+
+// ImportSCP applies profile through the Dell OEM ImportSystemConfiguration
+// action and waits for the import job. With ShutdownType NoReboot it returns
+// once the job waits for the next reset.
+func (c *Client) ImportSCP(ctx context.Context, profile []byte, opts SCPImport) (*Task, error) {
+	shutdown := valueOr(opts.ShutdownType, "Graceful")
+	body := map[string]interface{}{
+		"ImportBuffer":    string(profile),
+		"ShareParameters": map[string]string{"Target": valueOr(opts.Target, "ALL")},
+		"ShutdownType":    shutdown,
+		"HostPowerState":  "On",
+	}
+
+	uri, err := c.scpAction(ctx, "ImportSystemConfiguration", body)
+	if err != nil {
+		return nil, err
+	}
+	if shutdown == "NoReboot" {
+		return c.WaitForTaskOrReset(ctx, uri)
+	}
+	return c.WaitForTask(ctx, uri)
+}
+
+// scpAction invokes an EID_674_Manager action and returns the URI of the job it created
+func (c *Client) scpAction(ctx context.Context, action string, body map[string]interface{}) (string, error) {
+	if !c.isDell(ctx) {
+		return "", fmt.Errorf("%s is only supported on Dell iDRAC", action)
+	}
+
+	managerURI, err := c.ManagerURI(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.logger.LogInfo("Running %s...", action)
+	resp, err := c.makeRequest(ctx, "POST", managerURI+"/Actions/Oem/EID_674_Manager."+action, body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) {
+		return "", fmt.Errorf("%s failed: %w", action, newRedfishError(resp))
+	}
+
+	uri := taskMonitorURI(resp)
+	if uri == "" {
+		return "", fmt.Errorf("%s started without a job URI", action)
+	}
+	return uri, nil
+}
+
+// waitForExport polls the task monitor of an export job. While the job runs
+// the monitor returns the task; once it completes it returns the profile.
+func (c *Client) waitForExport(ctx context.Context, uri string) ([]byte, error) {
+	c.logger.LogInfo("Waiting for export job %s...", uri)
+
+	interval := c.pollInterval
+	if interval == 0 {
+		interval = defaultPollInterval
+	}
+
+	for {
+		resp, err := c.makeRequest(ctx, "GET", uri, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusAccepted {
+			if !isSuccess(resp.StatusCode) {
+				return nil, fmt.Errorf("failed to get export job %s: %w", uri, parseRedfishError(resp, body))
+			}
+
+			// A task resource carries a state, the exported profile does not
+			contentType := resp.Header.Get("Content-Type")
+			var task Task
+			if isMediaType(contentType, "application/json") && json.Unmarshal(body, &task) == nil && task.State() != "" {
+				if task.Done() {
+					if !task.Succeeded() {
+						return nil, &TaskError{URI: uri, State: task.State(), Status: task.TaskStatus, Messages: task.AllMessages()}
+					}
+					return nil, fmt.Errorf("export job %s completed without returning the profile", uri)
+				}
+			} else if isMediaType(contentType, "application/json", "application/xml", "text/xml") {
+				c.logger.LogSuccess("Export job %s completed", uri)
+				return body, nil
+			} else {
+				return nil, fmt.Errorf("export job %s returned %q instead of a configuration profile", uri, contentType)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// isMediaType reports whether contentType names one of the media types
+func isMediaType(contentType string, mediaTypes ...string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, want := range mediaTypes {
+		if mediaType == want {
+			return true
+		}
+	}
+	return false
+}
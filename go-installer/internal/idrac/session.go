@@ -0,0 +1,141 @@
+package idrac
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// sessionsURI is the Redfish session collection mandated by the specification
+const sessionsURI = "/redfish/v1/SessionService/Sessions"
+
+// SessionRequest represents a Redfish session creation request
+type SessionRequest struct {
+	UserName string `json:"UserName"`
+	Password string `json:"Password"`
+}
+
+// sessionToken returns the current X-Auth-Token, logging in on first use.
+// An empty token means requests fall back to HTTP Basic auth.
+func (c *Client) sessionToken(ctx context.Context) (string, error) {
+	if c.config.AuthMethod == "basic" {
+		return "", nil
+	}
+
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+
+	if c.authToken != "" || c.sessionUnsupported {
+		return c.authToken, nil
+	}
+
+	if err := c.login(ctx); err != nil {
+		return "", err
+	}
+	return c.authToken, nil
+}
+
+// login creates a Redfish session; the caller must hold sessionMu
+func (c *Client) login(ctx context.Context) error {
+	c.logger.LogDebug("Creating Redfish session on %s...", c.config.IP)
+
+	jsonData, err := json.Marshal(SessionRequest{
+		UserName: c.config.Username,
+		Password: c.config.Password,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal session request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+sessionsURI, bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create session request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		c.logger.LogWarn("BMC does not support Redfish sessions (status %d), using basic authentication", resp.StatusCode)
+		c.sessionUnsupported = true
+		return nil
+	default:
+		return fmt.Errorf("failed to create session: %w", newRedfishError(resp))
+	}
+
+	token := resp.Header.Get("X-Auth-Token")
+	if token == "" {
+		return fmt.Errorf("session created without an X-Auth-Token header")
+	}
+
+	sessionURI := resp.Header.Get("Location")
+	if sessionURI == "" {
+		var session ODataLink
+		body, _ := io.ReadAll(resp.Body)
+		if err := json.Unmarshal(body, &session); err == nil {
+			sessionURI = session.ODataID
+		}
+	}
+	if u, err := url.Parse(sessionURI); err == nil && u.IsAbs() {
+		sessionURI = u.Path
+	}
+
+	c.authToken = token
+	c.sessionURI = sessionURI
+	c.logger.LogDebug("Redfish session created: %s", sessionURI)
+	return nil
+}
+
+// invalidateSession forgets a token the BMC rejected so the next request logs in again
+func (c *Client) invalidateSession(token string) {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+
+	if c.authToken == token {
+		c.authToken = ""
+		c.sessionURI = ""
+	}
+}
+
+// Logout deletes the Redfish session, if one is open
+func (c *Client) Logout(ctx context.Context) error {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+
+	if c.authToken == "" || c.sessionURI == "" {
+		return nil
+	}
+
+	token, sessionURI := c.authToken, c.sessionURI
+	c.authToken = ""
+	c.sessionURI = ""
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+sessionURI, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create logout request: %w", err)
+	}
+	req.Header.Set("X-Auth-Token", token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete session: %w", newRedfishError(resp))
+	}
+
+	c.logger.LogDebug("Redfish session %s deleted", sessionURI)
+	return nil
+}
@@ -0,0 +1,329 @@
+package idrac
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Volume represents a Redfish Volume (virtual disk) resource
+type Volume struct {
+	ID            string            `json:"id" yaml:"id"`
+	URI           string            `json:"uri" yaml:"uri"`
+	Name          string            `json:"name,omitempty" yaml:"name,omitempty"`
+	RAIDType      string            `json:"raidType,omitempty" yaml:"raidType,omitempty"`
+	VolumeType    string            `json:"volumeType,omitempty" yaml:"volumeType,omitempty"`
+	CapacityBytes int64             `json:"capacityBytes,omitempty" yaml:"capacityBytes,omitempty"`
+	Identifiers   []DriveIdentifier `json:"identifiers,omitempty" yaml:"identifiers,omitempty"`
+	Status        ResourceStatus    `json:"status" yaml:"status"`
+	Drives        []string          `json:"drives,omitempty" yaml:"drives,omitempty"`
+	// DiskByPath is the /dev/disk/by-path name of the volume, when known
+	DiskByPath string `json:"diskByPath,omitempty" yaml:"diskByPath,omitempty"`
+}
+
+// volumeResource adds the links of a Volume resource
+type volumeResource struct {
+	Volume
+	ODataID string `json:"@odata.id"`
+	Links   struct {
+		Drives []ODataLink `json:"Drives"`
+	} `json:"Links"`
+}
+
+// volumeCollection holds the apply times supported when creating volumes
+type volumeCollection struct {
+	ApplyTimeSupport struct {
+		SupportedValues []string `json:"SupportedValues"`
+	} `json:"@Redfish.OperationApplyTimeSupport"`
+}
+
+// VolumeSpec declares a virtual disk to create
+type VolumeSpec struct {
+	Name string
+	// RAIDType is RAID0 or RAID1
+	RAIDType string
+	// Drives are the IDs of the member drives
+	Drives []string
+}
+
+// volumeTypes maps RAID types to the VolumeType of older Redfish implementations
+var volumeTypes = map[string]string{
+	"RAID0": "NonRedundant",
+	"RAID1": "Mirrored",
+}
+
+// ListStorage retrieves the storage controllers of the system with their drives and volumes
+func (c *Client) ListStorage(ctx context.Context) ([]Storage, error) {
+	systemURI, err := c.SystemURI(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var system inventorySystem
+	if err := c.getJSON(ctx, systemURI, &system); err != nil {
+		return nil, fmt.Errorf("failed to get system: %w", err)
+	}
+
+	return c.collectStorage(ctx, system.Storage)
+}
+
+// collectStorage fetches every Storage member with its drives and volumes
+func (c *Client) collectStorage(ctx context.Context, link ODataLink) ([]Storage, error) {
+	var storages []storageResource
+	if err := collectMembers(ctx, c, link, &storages); err != nil {
+		return nil, fmt.Errorf("failed to collect storage: %w", err)
+	}
+
+	var result []Storage
+	for _, res := range storages {
+		storage := res.Storage
+		storage.URI = res.ODataID
+		storage.Controllers = nil
+		storage.Drives = nil
+
+		for _, controller := range res.Controllers {
+			storage.Controllers = append(storage.Controllers, controller.StorageController)
+			for _, function := range controller.Links.PCIeFunctions {
+				if address := pciAddressFromFunctionID(lastSegment(function.ODataID)); address != "" && storage.PCIAddress == "" {
+					storage.PCIAddress = address
+				}
+			}
+		}
+
+		for _, driveLink := range res.DriveLinks {
+			var drive Drive
+			if err := c.getJSON(ctx, driveLink.ODataID, &drive); err != nil {
+				return nil, fmt.Errorf("failed to collect drives of %s: %w", storage.ID, err)
+			}
+			drive.URI = driveLink.ODataID
+			drive.DiskByPath = DiskByPath(storage.PCIAddress, drive.ID)
+			storage.Drives = append(storage.Drives, drive)
+		}
+
+		var volumes []volumeResource
+		if err := collectMembers(ctx, c, res.VolumesLink, &volumes); err != nil {
+			return nil, fmt.Errorf("failed to collect volumes of %s: %w", storage.ID, err)
+		}
+		storage.Volumes = nil
+		for _, volume := range volumes {
+			volume.URI = volume.ODataID
+			volume.Volume.Drives = nil
+			for _, driveLink := range volume.Links.Drives {
+				volume.Volume.Drives = append(volume.Volume.Drives, lastSegment(driveLink.ODataID))
+			}
+			volume.DiskByPath = DiskByPath(storage.PCIAddress, volume.ID)
+			storage.Volumes = append(storage.Volumes, volume.Volume)
+		}
+
+		result = append(result, storage)
+	}
+	return result, nil
+}
+
+// GetStorage returns the storage controller with the given ID, or the first
+// controller with drives when id is empty
+func (c *Client) GetStorage(ctx context.Context, id string) (*Storage, error) {
+	storages, err := c.ListStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, storage := range storages {
+		if (id == "" && len(storage.Drives) > 0) || storage.ID == id {
+			return &storages[i], nil
+		}
+	}
+	if id == "" {
+		return nil, fmt.Errorf("no storage controller with drives found")
+	}
+	return nil, fmt.Errorf("storage controller %q not found", id)
+}
+
+// CreateVolume creates a virtual disk on the storage controller. The returned
+// change carries the job that builds the volume; Immediate reports whether it
+// runs without a reset.
+func (c *Client) CreateVolume(ctx context.Context, storage *Storage, spec VolumeSpec) (*PendingChange, error) {
+	c.logger.LogInfo("Creating %s volume %s on %s from %s", spec.RAIDType, spec.Name, storage.ID, strings.Join(spec.Drives, ", "))
+
+	volumeType, ok := volumeTypes[spec.RAIDType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported RAID type %q (expected RAID0 or RAID1)", spec.RAIDType)
+	}
+	if spec.RAIDType == "RAID1" && len(spec.Drives) != 2 {
+		return nil, fmt.Errorf("RAID1 needs exactly 2 drives, got %d", len(spec.Drives))
+	}
+	if len(spec.Drives) == 0 {
+		return nil, fmt.Errorf("no drives given for volume %s", spec.Name)
+	}
+
+	var links []ODataLink
+	for _, id := range spec.Drives {
+		uri := ""
+		for _, drive := range storage.Drives {
+			if drive.ID == id {
+				uri = drive.URI
+			}
+		}
+		if uri == "" {
+			return nil, fmt.Errorf("drive %q not found on %s", id, storage.ID)
+		}
+		links = append(links, ODataLink{ODataID: uri})
+	}
+
+	volumesURI := storage.URI + "/Volumes"
+	var collection volumeCollection
+	if err := c.getJSON(ctx, volumesURI, &collection); err != nil {
+		return nil, fmt.Errorf("failed to get volumes of %s: %w", storage.ID, err)
+	}
+	change := &PendingChange{SettingsURI: volumesURI, Immediate: true}
+
+	body := map[string]interface{}{
+		"Name":     spec.Name,
+		"RAIDType": spec.RAIDType,
+		"Links":    map[string]interface{}{"Drives": links},
+	}
+	if supported := collection.ApplyTimeSupport.SupportedValues; len(supported) > 0 && !containsString(supported, "Immediate") {
+		body["@Redfish.OperationApplyTime"] = "OnReset"
+		change.Immediate = false
+	}
+
+	resp, err := c.makeRequest(ctx, "POST", volumesURI, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Implementations before Redfish 2019.1 only know VolumeType
+	if resp.StatusCode == http.StatusBadRequest {
+		redfishErr := newRedfishError(resp)
+		if !redfishErr.HasMessage("PropertyUnknown") && !redfishErr.HasMessage("PropertyValueNotInList") {
+			return nil, fmt.Errorf("failed to create volume %s: %w", spec.Name, redfishErr)
+		}
+		delete(body, "RAIDType")
+		body["VolumeType"] = volumeType
+		c.logger.LogDebug("RAIDType rejected, retrying with VolumeType %s", volumeType)
+
+		retry, err := c.makeRequest(ctx, "POST", volumesURI, body)
+		if err != nil {
+			return nil, err
+		}
+		defer retry.Body.Close()
+		resp = retry
+	}
+
+	if !isSuccess(resp.StatusCode) && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to create volume %s: %w", spec.Name, newRedfishError(resp))
+	}
+
+	change.JobURI = taskMonitorURI(resp)
+	c.logger.LogSuccess("Volume %s requested on %s", spec.Name, storage.ID)
+	return change, nil
+}
+
+// DeleteVolume deletes a virtual disk
+func (c *Client) DeleteVolume(ctx context.Context, volume *Volume) (*PendingChange, error) {
+	c.logger.LogInfo("Deleting volume %s", volume.ID)
+
+	resp, err := c.makeRequest(ctx, "DELETE", volume.URI, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) {
+		return nil, fmt.Errorf("failed to delete volume %s: %w", volume.ID, newRedfishError(resp))
+	}
+
+	return &PendingChange{SettingsURI: volume.URI, JobURI: taskMonitorURI(resp), Immediate: true}, nil
+}
+
+// ClearForeignConfig removes foreign RAID configurations imported from other
+// controllers. It uses the Dell RAID service and is only available on iDRAC.
+func (c *Client) ClearForeignConfig(ctx context.Context, storage *Storage) (*PendingChange, error) {
+	return c.dellRAIDAction(ctx, "ClearForeignConfig", map[string]interface{}{"TargetFQDD": storage.ID})
+}
+
+// ConvertToNonRAID switches drives to non-RAID (pass-through) mode. It uses
+// the Dell RAID service and is only available on iDRAC.
+func (c *Client) ConvertToNonRAID(ctx context.Context, drives []string) (*PendingChange, error) {
+	return c.dellRAIDAction(ctx, "ConvertToNonRAID", map[string]interface{}{"PDArray": drives})
+}
+
+// dellRAIDAction invokes a DellRaidService action and returns the job it created
+func (c *Client) dellRAIDAction(ctx context.Context, action string, body map[string]interface{}) (*PendingChange, error) {
+	if !c.isDell(ctx) {
+		return nil, fmt.Errorf("%s is only supported on Dell iDRAC", action)
+	}
+
+	systemURI, err := c.SystemURI(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.LogInfo("Running DellRaidService.%s...", action)
+	uri := systemURI + "/Oem/Dell/DellRaidService/Actions/DellRaidService." + action
+	resp, err := c.makeRequest(ctx, "POST", uri, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) {
+		return nil, fmt.Errorf("DellRaidService.%s failed: %w", action, newRedfishError(resp))
+	}
+
+	return &PendingChange{SettingsURI: uri, JobURI: taskMonitorURI(resp), Immediate: true}, nil
+}
+
+// DiskByPath derives the /dev/disk/by-path name Linux gives a PERC volume or
+// non-RAID drive. The megaraid_sas driver exposes virtual disks on SCSI
+// channel 2 with the virtual disk number as target, and non-RAID drives on
+// channel 0 with the bay number as target. It returns "" when the controller
+// PCI address is unknown or the ID does not follow the iDRAC FQDD format.
+func DiskByPath(pciAddress, id string) string {
+	if pciAddress == "" {
+		return ""
+	}
+
+	var channel, prefix string
+	switch {
+	case strings.HasPrefix(id, "Disk.Virtual."):
+		channel, prefix = "2", "Disk.Virtual."
+	case strings.HasPrefix(id, "Disk.Bay."):
+		channel, prefix = "0", "Disk.Bay."
+	default:
+		return ""
+	}
+
+	number := strings.TrimPrefix(id, prefix)
+	if idx := strings.Index(number, ":"); idx >= 0 {
+		number = number[:idx]
+	}
+	target, err := strconv.Atoi(number)
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("/dev/disk/by-path/pci-%s-scsi-0:%s:%d:0", pciAddress, channel, target)
+}
+
+// pciAddressFromFunctionID converts an iDRAC PCIe function ID of the form
+// bus-device-function (decimal) into a PCI address such as 0000:02:00.0
+func pciAddressFromFunctionID(id string) string {
+	parts := strings.Split(id, "-")
+	if len(parts) != 3 {
+		return ""
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return ""
+		}
+		numbers[i] = n
+	}
+	return fmt.Sprintf("0000:%02x:%02x.%x", numbers[0], numbers[1], numbers[2])
+}
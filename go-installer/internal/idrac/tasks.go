@@ -0,0 +1,372 @@
+package idrac
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// defaultPollInterval is used when the client has no poll interval configured
+const defaultPollInterval = 5 * time.Second
+
+// unreachableTimeout is how long a task is polled through BMC or network
+// errors, which happen while the BMC itself restarts for a firmware update
+const unreachableTimeout = 10 * time.Minute
+
+// defaultTaskTimeout is how long a task may run when idrac.task_timeout is
+// not set; firmware updates and storage initialization take the longest
+const defaultTaskTimeout = 2 * time.Hour
+
+// errTaskTimeout is returned when a task does not finish within the task
+// timeout
+var errTaskTimeout = errors.New("timed out waiting for task")
+
+// Message represents a Redfish message object
+type Message struct {
+	MessageID   string   `json:"MessageId"`
+	Message     string   `json:"Message"`
+	MessageArgs []string `json:"MessageArgs,omitempty"`
+	Resolution  string   `json:"Resolution,omitempty"`
+	Severity    string   `json:"Severity,omitempty"`
+}
+
+// Task represents a Redfish task or an iDRAC job
+type Task struct {
+	ID              string    `json:"Id"`
+	Name            string    `json:"Name"`
+	TaskState       string    `json:"TaskState"`
+	TaskStatus      string    `json:"TaskStatus"`
+	PercentComplete int       `json:"PercentComplete"`
+	Messages        []Message `json:"Messages"`
+
+	// iDRAC job properties
+	JobState  string `json:"JobState"`
+	JobType   string `json:"JobType"`
+	Message   string `json:"Message"`
+	MessageID string `json:"MessageId"`
+}
+
+// State returns the task state, or the job state for iDRAC jobs
+func (t *Task) State() string {
+	if t.TaskState != "" {
+		return t.TaskState
+	}
+	return t.JobState
+}
+
+// Done reports whether the task reached a terminal state
+func (t *Task) Done() bool {
+	switch t.State() {
+	case "Completed", "Killed", "Exception", "Cancelled",
+		"Failed", "CompletedWithErrors":
+		return true
+	}
+	return false
+}
+
+// Scheduled reports whether the task or iDRAC job waits for a system reset
+func (t *Task) Scheduled() bool {
+	return t.JobState == "Scheduled" || (t.JobState == "" && t.TaskState == "Pending")
+}
+
+// Succeeded reports whether the task completed without errors
+func (t *Task) Succeeded() bool {
+	if t.State() != "Completed" {
+		return false
+	}
+	return t.TaskStatus == "" || t.TaskStatus == "OK" || t.TaskStatus == "Warning"
+}
+
+// AllMessages returns the task messages including the single iDRAC job message
+func (t *Task) AllMessages() []Message {
+	messages := t.Messages
+	if t.Message != "" {
+		messages = append(messages, Message{MessageID: t.MessageID, Message: t.Message})
+	}
+	return messages
+}
+
+// TaskError is returned when a Redfish task or iDRAC job does not complete successfully
+type TaskError struct {
+	URI      string
+	State    string
+	Status   string
+	Messages []Message
+}
+
+// Error implements the error interface
+func (e *TaskError) Error() string {
+	msg := fmt.Sprintf("task %s ended in state %s", e.URI, e.State)
+	if e.Status != "" {
+		msg += fmt.Sprintf(" (status %s)", e.Status)
+	}
+	for _, m := range e.Messages {
+		if m.MessageID != "" {
+			msg += fmt.Sprintf("; %s: %s", m.MessageID, m.Message)
+		} else if m.Message != "" {
+			msg += "; " + m.Message
+		}
+	}
+	return msg
+}
+
+// taskMonitorURI returns the task monitor for a 202 Accepted response, taken
+// from the Location header or the @odata.id of the returned task
+func taskMonitorURI(resp *http.Response) string {
+	if location := resp.Header.Get("Location"); location != "" {
+		return uriPath(location)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ""
+	}
+	var task ODataLink
+	if err := json.Unmarshal(body, &task); err != nil {
+		return ""
+	}
+	return task.ODataID
+}
+
+// uriPath reduces an absolute URL returned by the BMC to its path
+func uriPath(location string) string {
+	if u, err := url.Parse(location); err == nil && u.IsAbs() {
+		return u.Path
+	}
+	return location
+}
+
+// completeAction waits for the task behind a 202 Accepted response; any
+// other success code means the operation already completed
+func (c *Client) completeAction(ctx context.Context, resp *http.Response) error {
+	if resp.StatusCode != http.StatusAccepted {
+		return nil
+	}
+
+	uri := taskMonitorURI(resp)
+	if uri == "" {
+		c.logger.LogDebug("Request accepted without a task monitor, assuming completion")
+		return nil
+	}
+
+	_, err := c.WaitForTask(ctx, uri)
+	return err
+}
+
+// WaitForTask polls a task monitor, task or iDRAC job until it finishes.
+// It returns a *TaskError when the task ends in any state but Completed.
+func (c *Client) WaitForTask(ctx context.Context, uri string) (*Task, error) {
+	return c.waitForTask(ctx, uri, false)
+}
+
+// WaitForTaskOrReset polls a task like WaitForTask but also returns once the
+// task is scheduled to run on the next reset
+func (c *Client) WaitForTaskOrReset(ctx context.Context, uri string) (*Task, error) {
+	return c.waitForTask(ctx, uri, true)
+}
+
+// taskTimeout returns how long a task may run before waitForTask gives up
+func (c *Client) taskTimeout() time.Duration {
+	if c.config != nil && c.config.TaskTimeout > 0 {
+		return time.Duration(c.config.TaskTimeout) * time.Second
+	}
+	return defaultTaskTimeout
+}
+
+// waitForTask polls a task until it finishes, or until it waits for a reset
+// when untilScheduled is set. Errors reaching the BMC are retried for up to
+// unreachableTimeout, and the task is given up after the task timeout.
+func (c *Client) waitForTask(ctx context.Context, uri string, untilScheduled bool) (*Task, error) {
+	if c.planWait("task " + uri) {
+		return &Task{TaskState: "Completed", TaskStatus: "OK"}, nil
+	}
+
+	c.logger.LogInfo("Waiting for task %s...", uri)
+
+	interval := c.pollInterval
+	if interval == 0 {
+		interval = defaultPollInterval
+	}
+
+	timeout := c.taskTimeout()
+	deadline := time.Now().Add(timeout)
+	lastState := ""
+	var unreachableSince time.Time
+	for {
+		task, finished, err := c.pollTask(ctx, uri)
+		if err != nil {
+			if ctx.Err() != nil || !isTransient(err) {
+				return nil, err
+			}
+			if unreachableSince.IsZero() {
+				unreachableSince = time.Now()
+			}
+			if time.Since(unreachableSince) > unreachableTimeout {
+				return nil, fmt.Errorf("task %s unreachable for %s: %w", uri, unreachableTimeout, err)
+			}
+			c.logger.LogWarn("Task %s not reachable, retrying: %v", uri, err)
+		} else if err := c.taskResult(uri, task, finished, untilScheduled); err != errTaskRunning {
+			return task, err
+		} else {
+			unreachableSince = time.Time{}
+			if state := task.State(); state != lastState {
+				c.logger.LogInfo("Task %s: %s (%d%%)", uri, state, task.PercentComplete)
+				lastState = state
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("task %s did not finish within %s: %w", uri, timeout, errTaskTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// errTaskRunning is returned by taskResult while the task is still running
+var errTaskRunning = errors.New("task running")
+
+// taskResult turns a polled task into the result of waitForTask
+func (c *Client) taskResult(uri string, task *Task, finished, untilScheduled bool) error {
+	if untilScheduled && task.Scheduled() {
+		c.logger.LogInfo("Task %s is scheduled and waits for a reset", uri)
+		return nil
+	}
+	if !finished {
+		return errTaskRunning
+	}
+
+	if !task.Succeeded() && task.State() != "" {
+		c.logger.LogError("Task %s ended in state %s", uri, task.State())
+		return &TaskError{
+			URI:      uri,
+			State:    task.State(),
+			Status:   task.TaskStatus,
+			Messages: task.AllMessages(),
+		}
+	}
+	c.logger.LogSuccess("Task %s completed", uri)
+	return nil
+}
+
+// isTransient reports whether a polling error may resolve by itself: refused,
+// reset or timed out connections and 5xx answers while the BMC or its web
+// server restarts. Certificate, decoding and request errors are permanent.
+func isTransient(err error) bool {
+	var redfishErr *RedfishError
+	if errors.As(err, &redfishErr) {
+		return redfishErr.StatusCode >= 500
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	// url.Error is a net.Error whatever it wraps, so look at its cause
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// pollTask reads a task once and reports whether it is finished
+func (c *Client) pollTask(ctx context.Context, uri string) (*Task, bool, error) {
+	resp, err := c.makeRequest(ctx, "GET", uri, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	task := &Task{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, task); err != nil && resp.StatusCode == http.StatusAccepted {
+			return nil, false, fmt.Errorf("failed to unmarshal task %s: %w", uri, err)
+		}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusAccepted:
+		// A task monitor answers 202 while the task is still running
+		return task, false, nil
+	case isSuccess(resp.StatusCode) || resp.StatusCode == http.StatusCreated:
+		// A task monitor returns the operation result once complete;
+		// task and job resources carry their own state
+		if task.State() == "" {
+			return task, true, nil
+		}
+		return task, task.Done(), nil
+	default:
+		return nil, false, fmt.Errorf("failed to get task %s: %w", uri, parseRedfishError(resp, body))
+	}
+}
+
+// JobURI returns the URI of an iDRAC job on the discovered manager
+func (c *Client) JobURI(ctx context.Context, jobID string) (string, error) {
+	managerURI, err := c.ManagerURI(ctx)
+	if err != nil {
+		return "", err
+	}
+	return managerURI + "/Jobs/" + jobID, nil
+}
+
+// CreateConfigJob schedules an iDRAC configuration job that applies the
+// pending settings of targetSettingsURI on the next reset. It returns the job URI.
+func (c *Client) CreateConfigJob(ctx context.Context, targetSettingsURI string) (string, error) {
+	managerURI, err := c.ManagerURI(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	jobReq := map[string]string{"TargetSettingsURI": targetSettingsURI}
+	resp, err := c.makeRequest(ctx, "POST", managerURI+"/Jobs", jobReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp.StatusCode) && resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("failed to create configuration job for %s: %w", targetSettingsURI, newRedfishError(resp))
+	}
+
+	jobURI := taskMonitorURI(resp)
+	if jobURI == "" {
+		return "", fmt.Errorf("configuration job for %s created without a job URI", targetSettingsURI)
+	}
+
+	c.logger.LogSuccess("Configuration job scheduled: %s", jobURI)
+	return jobURI, nil
+}
+
+// WaitForJob polls an iDRAC job by ID until it finishes
+func (c *Client) WaitForJob(ctx context.Context, jobID string) (*Task, error) {
+	if strings.HasPrefix(jobID, "/") {
+		return c.WaitForTask(ctx, jobID)
+	}
+	uri, err := c.JobURI(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return c.WaitForTask(ctx, uri)
+}
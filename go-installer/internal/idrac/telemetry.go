@@ -0,0 +1,242 @@
+package idrac
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Fan represents a fan of the Redfish Thermal resource
+type Fan struct {
+	MemberID string `json:"MemberId"`
+	Name     string `json:"Name"`
+	// FanName is the name reported by older firmware (iDRAC8)
+	FanName      string         `json:"FanName"`
+	Reading      *float64       `json:"Reading"`
+	ReadingUnits string         `json:"ReadingUnits"`
+	Status       ResourceStatus `json:"Status"`
+}
+
+// Temperature represents a temperature sensor of the Redfish Thermal resource
+type Temperature struct {
+	MemberID               string         `json:"MemberId"`
+	Name                   string         `json:"Name"`
+	ReadingCelsius         *float64       `json:"ReadingCelsius"`
+	PhysicalContext        string         `json:"PhysicalContext"`
+	UpperThresholdCritical *float64       `json:"UpperThresholdCritical"`
+	Status                 ResourceStatus `json:"Status"`
+}
+
+// Thermal represents the Redfish Thermal resource of a chassis
+type Thermal struct {
+	Fans         []Fan         `json:"Fans"`
+	Temperatures []Temperature `json:"Temperatures"`
+}
+
+// PowerLimit is the power cap of a PowerControl; LimitInWatts is nil when
+// capping is disabled
+type PowerLimit struct {
+	LimitInWatts   *float64 `json:"LimitInWatts"`
+	LimitException string   `json:"LimitException"`
+	CorrectionInMs *int     `json:"CorrectionInMs"`
+}
+
+// PowerControl represents a power control of the Redfish Power resource
+type PowerControl struct {
+	MemberID           string     `json:"MemberId"`
+	Name               string     `json:"Name"`
+	PowerConsumedWatts *float64   `json:"PowerConsumedWatts"`
+	PowerCapacityWatts *float64   `json:"PowerCapacityWatts"`
+	PowerLimit         PowerLimit `json:"PowerLimit"`
+}
+
+// PowerSupply represents a power supply of the Redfish Power resource
+type PowerSupply struct {
+	MemberID             string         `json:"MemberId"`
+	Name                 string         `json:"Name"`
+	PowerInputWatts      *float64       `json:"PowerInputWatts"`
+	LastPowerOutputWatts *float64       `json:"LastPowerOutputWatts"`
+	PowerCapacityWatts   *float64       `json:"PowerCapacityWatts"`
+	Status               ResourceStatus `json:"Status"`
+}
+
+// Redundancy represents a redundancy group of the Redfish Power resource
+type Redundancy struct {
+	MemberID     string         `json:"MemberId"`
+	Name         string         `json:"Name"`
+	Mode         string         `json:"Mode"`
+	MinNumNeeded *int           `json:"MinNumNeeded"`
+	Status       ResourceStatus `json:"Status"`
+}
+
+// Power represents the Redfish Power resource of a chassis
+type Power struct {
+	PowerControl  []PowerControl `json:"PowerControl"`
+	PowerSupplies []PowerSupply  `json:"PowerSupplies"`
+	Redundancy    []Redundancy   `json:"Redundancy"`
+}
+
+// Sensor represents a Redfish Sensor resource
+type Sensor struct {
+	ID              string         `json:"Id"`
+	Name            string         `json:"Name"`
+	Reading         *float64       `json:"Reading"`
+	ReadingUnits    string         `json:"ReadingUnits"`
+	ReadingType     string         `json:"ReadingType"`
+	PhysicalContext string         `json:"PhysicalContext"`
+	Status          ResourceStatus `json:"Status"`
+}
+
+// Telemetry is a single sample of the chassis thermal, power and sensor readings
+type Telemetry struct {
+	CollectedAt time.Time
+	BMC         string
+	Chassis     string
+	Thermal     Thermal
+	Power       Power
+	Sensors     []Sensor
+}
+
+// chassisTelemetry holds the chassis links used to collect telemetry
+type chassisTelemetry struct {
+	Thermal ODataLink `json:"Thermal"`
+	Power   ODataLink `json:"Power"`
+	Sensors ODataLink `json:"Sensors"`
+}
+
+// chassisLinks holds the chassis links of the system
+type chassisLinks struct {
+	Links struct {
+		Chassis []ODataLink `json:"Chassis"`
+	} `json:"Links"`
+}
+
+// sensorMember is a Sensors collection member, expanded or a bare link
+type sensorMember struct {
+	Sensor
+	ODataID string `json:"@odata.id"`
+}
+
+// sensorPage is one page of the Sensors collection
+type sensorPage struct {
+	Members  []sensorMember `json:"Members"`
+	NextLink string         `json:"Members@odata.nextLink"`
+}
+
+// DisplayName returns the fan name, falling back to its member ID
+func (f Fan) DisplayName() string {
+	return valueOr(f.Name, valueOr(f.FanName, f.MemberID))
+}
+
+// InletTemperature returns the intake temperature, if the BMC reports one
+func (t *Telemetry) InletTemperature() (float64, bool) {
+	for _, temperature := range t.Thermal.Temperatures {
+		if temperature.ReadingCelsius == nil {
+			continue
+		}
+		if temperature.PhysicalContext == "Intake" || strings.Contains(strings.ToLower(temperature.Name), "inlet") {
+			return *temperature.ReadingCelsius, true
+		}
+	}
+	return 0, false
+}
+
+// PowerConsumed returns the power drawn by the chassis, if the BMC reports it
+func (t *Telemetry) PowerConsumed() (float64, bool) {
+	for _, control := range t.Power.PowerControl {
+		if control.PowerConsumedWatts != nil {
+			return *control.PowerConsumedWatts, true
+		}
+	}
+	return 0, false
+}
+
+// MaxFanSpeed returns the fastest fan reading in RPM, if the BMC reports one
+func (t *Telemetry) MaxFanSpeed() (float64, bool) {
+	max, found := 0.0, false
+	for _, fan := range t.Thermal.Fans {
+		if fan.Reading != nil && !strings.EqualFold(fan.ReadingUnits, "Percent") && *fan.Reading > max {
+			max, found = *fan.Reading, true
+		}
+	}
+	return max, found
+}
+
+// ChassisURI returns the URI of the chassis containing the system
+func (c *Client) ChassisURI(ctx context.Context) (string, error) {
+	res, err := c.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var system chassisLinks
+	if err := c.getJSON(ctx, res.SystemURI, &system); err != nil {
+		return "", fmt.Errorf("failed to get system: %w", err)
+	}
+	if len(system.Links.Chassis) > 0 {
+		return system.Links.Chassis[0].ODataID, nil
+	}
+
+	chassis, err := c.getCollection(ctx, linkOrDefault(res.Root.Chassis, "/Chassis"))
+	if err != nil {
+		return "", fmt.Errorf("failed to list chassis: %w", err)
+	}
+	return selectMember(chassis, "")
+}
+
+// GetTelemetry reads the Thermal, Power and Sensors resources of the chassis.
+// Sensors are optional, older firmware only offers Thermal and Power.
+func (c *Client) GetTelemetry(ctx context.Context) (*Telemetry, error) {
+	chassisURI, err := c.ChassisURI(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var chassis chassisTelemetry
+	if err := c.getJSON(ctx, chassisURI, &chassis); err != nil {
+		return nil, fmt.Errorf("failed to get chassis: %w", err)
+	}
+
+	telemetry := &Telemetry{
+		CollectedAt: time.Now().UTC(),
+		BMC:         c.config.IP,
+		Chassis:     chassisURI,
+	}
+	if err := c.getJSON(ctx, valueOr(chassis.Thermal.ODataID, chassisURI+"/Thermal"), &telemetry.Thermal); err != nil {
+		return nil, fmt.Errorf("failed to get thermal: %w", err)
+	}
+	if err := c.getJSON(ctx, valueOr(chassis.Power.ODataID, chassisURI+"/Power"), &telemetry.Power); err != nil {
+		return nil, fmt.Errorf("failed to get power: %w", err)
+	}
+	if chassis.Sensors.ODataID != "" {
+		if telemetry.Sensors, err = c.getSensors(ctx, chassis.Sensors.ODataID); err != nil {
+			return nil, err
+		}
+	}
+
+	return telemetry, nil
+}
+
+// getSensors reads the Sensors collection, asking the BMC to expand the
+// members and fetching them one by one when it does not
+func (c *Client) getSensors(ctx context.Context, uri string) ([]Sensor, error) {
+	var sensors []Sensor
+	for uri = uri + "?$expand=.($levels=1)"; uri != ""; {
+		var page sensorPage
+		if err := c.getJSON(ctx, uri, &page); err != nil {
+			return nil, fmt.Errorf("failed to get sensors: %w", err)
+		}
+		for _, member := range page.Members {
+			sensor := member.Sensor
+			if sensor.ID == "" && member.ODataID != "" {
+				if err := c.getJSON(ctx, member.ODataID, &sensor); err != nil {
+					return nil, fmt.Errorf("failed to get sensor: %w", err)
+				}
+			}
+			sensors = append(sensors, sensor)
+		}
+		uri = page.NextLink
+	}
+	return sensors, nil
+}
@@ -0,0 +1,271 @@
+package idrac
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"openshift-sno-hub-installer/internal/config"
+	"openshift-sno-hub-installer/internal/logger"
+)
+
+// DefaultKnownHostsFile records the BMC certificate fingerprints trusted on
+// first use when idrac.known_hosts is not set
+const DefaultKnownHostsFile = "bmc_known_hosts"
+
+// FingerprintMismatchError is returned when the BMC presents a certificate
+// other than the pinned or previously trusted one
+type FingerprintMismatchError struct {
+	Host     string
+	Expected string
+	Actual   string
+	// Source is where the expected fingerprint comes from: the configured
+	// pin or the known hosts file
+	Source string
+}
+
+// Error implements the error interface
+func (e *FingerprintMismatchError) Error() string {
+	return fmt.Sprintf("certificate of %s has fingerprint %s, expected %s from %s; refusing to connect",
+		e.Host, e.Actual, e.Expected, e.Source)
+}
+
+// Fingerprint returns the SHA-256 fingerprint of a DER certificate in the
+// colon-separated upper case form printed by openssl x509 -fingerprint
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return formatFingerprint(sum[:])
+}
+
+// formatFingerprint formats a digest as colon-separated upper case hex
+func formatFingerprint(sum []byte) string {
+	pairs := make([]string, 0, len(sum))
+	for _, b := range sum {
+		pairs = append(pairs, fmt.Sprintf("%02X", b))
+	}
+	return strings.Join(pairs, ":")
+}
+
+// PEMFingerprint returns the fingerprint of the first certificate of a PEM file
+func PEMFingerprint(data []byte) (string, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return "", fmt.Errorf("no PEM certificate found")
+		}
+		if block.Type == "CERTIFICATE" {
+			return Fingerprint(block.Bytes), nil
+		}
+	}
+}
+
+// NormalizeFingerprint parses a SHA-256 fingerprint given as hex with or
+// without colons and returns it in the form of Fingerprint
+func NormalizeFingerprint(fingerprint string) (string, error) {
+	raw, err := hex.DecodeString(strings.ReplaceAll(strings.TrimPrefix(strings.TrimSpace(fingerprint), "sha256:"), ":", ""))
+	if err != nil || len(raw) != sha256.Size {
+		return "", fmt.Errorf("invalid SHA-256 fingerprint %q", fingerprint)
+	}
+	return formatFingerprint(raw), nil
+}
+
+// knownHostsMu serializes the reads and writes of known hosts files, which
+// clients of several BMCs, such as the sites of a fleet install, share
+var knownHostsMu sync.Mutex
+
+// knownHosts is the file of BMC certificate fingerprints trusted on first use,
+// one "<host> <fingerprint>" line per BMC
+type knownHosts struct {
+	path string
+}
+
+// lookup returns the fingerprint recorded for host
+func (k *knownHosts) lookup(host string) (string, error) {
+	knownHostsMu.Lock()
+	defer knownHostsMu.Unlock()
+
+	entries, err := k.read()
+	if err != nil {
+		return "", err
+	}
+	return entries[host], nil
+}
+
+// record stores fingerprint as the trusted certificate of host
+func (k *knownHosts) record(host, fingerprint string) error {
+	knownHostsMu.Lock()
+	defer knownHostsMu.Unlock()
+
+	entries, err := k.read()
+	if err != nil {
+		return err
+	}
+	entries[host] = fingerprint
+
+	hosts := make([]string, 0, len(entries))
+	for h := range entries {
+		hosts = append(hosts, h)
+	}
+	sort.Strings(hosts)
+	var content strings.Builder
+	for _, h := range hosts {
+		fmt.Fprintf(&content, "%s %s\n", h, entries[h])
+	}
+	if dir := filepath.Dir(k.path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create known hosts directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(k.path, []byte(content.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write known hosts file: %w", err)
+	}
+	return nil
+}
+
+// read parses the known hosts file; a missing file holds no entries
+func (k *knownHosts) read() (map[string]string, error) {
+	entries := make(map[string]string)
+	file, err := os.Open(k.path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known hosts file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if fingerprint, err := NormalizeFingerprint(fields[1]); err == nil {
+			entries[fields[0]] = fingerprint
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// newTLSConfig returns the TLS configuration that verifies the BMC according
+// to cfg: the certificate chain against the system roots or ca_bundle, the
+// leaf certificate against the fingerprint pin or the fingerprint trusted on
+// first use, or nothing when none of these is configured
+func newTLSConfig(cfg *config.IDRACConfig, trust *knownHosts, log *logger.Logger) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: !cfg.VerifySSL && cfg.CABundle == ""}
+
+	if cfg.CABundle != "" {
+		bundle, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", cfg.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	var pin string
+	if cfg.Fingerprint != "" {
+		var err error
+		if pin, err = NormalizeFingerprint(cfg.Fingerprint); err != nil {
+			return nil, err
+		}
+	}
+	if pin == "" && !cfg.TrustOnFirstUse {
+		return tlsConfig, nil
+	}
+
+	tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+		if len(state.PeerCertificates) == 0 {
+			return fmt.Errorf("%s presented no certificate", cfg.IP)
+		}
+		actual := Fingerprint(state.PeerCertificates[0].Raw)
+		if pin != "" {
+			if actual != pin {
+				return &FingerprintMismatchError{Host: cfg.IP, Expected: pin, Actual: actual, Source: "idrac.fingerprint"}
+			}
+			return nil
+		}
+
+		known, err := trust.lookup(cfg.IP)
+		if err != nil {
+			return err
+		}
+		if known == "" {
+			if err := trust.record(cfg.IP, actual); err != nil {
+				return err
+			}
+			log.LogWarn("Trusting certificate %s of %s on first use (recorded in %s)", actual, cfg.IP, trust.path)
+			return nil
+		}
+		if actual != known {
+			return &FingerprintMismatchError{Host: cfg.IP, Expected: known, Actual: actual, Source: trust.path}
+		}
+		return nil
+	}
+	return tlsConfig, nil
+}
+
+// TrustFingerprint records fingerprint as the trusted certificate of the BMC
+// in the known hosts file, e.g. after its certificate was replaced
+func (c *Client) TrustFingerprint(fingerprint string) error {
+	normalized, err := NormalizeFingerprint(fingerprint)
+	if err != nil {
+		return err
+	}
+	return c.trust.record(c.config.IP, normalized)
+}
+
+// TrustedFingerprint returns the fingerprint the client expects from the BMC:
+// the configured pin, the one trusted on first use, or "" when the
+// certificate is not pinned
+func (c *Client) TrustedFingerprint() (string, error) {
+	if c.config.Fingerprint != "" {
+		return NormalizeFingerprint(c.config.Fingerprint)
+	}
+	if !c.config.TrustOnFirstUse {
+		return "", nil
+	}
+	return c.trust.lookup(c.config.IP)
+}
+
+// FetchFingerprint connects to the BMC without verifying it and returns the
+// fingerprint of the certificate it presents
+func (c *Client) FetchFingerprint(ctx context.Context) (string, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid BMC URL: %w", err)
+	}
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	dialer := &tls.Dialer{Config: &tls.Config{InsecureSkipVerify: true}}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	certificates := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return "", fmt.Errorf("%s presented no certificate", address)
+	}
+	return Fingerprint(certificates[0].Raw), nil
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -12,10 +13,17 @@ import (
 type Logger struct {
 	*logrus.Logger
 	logFile *os.File
+	logDir  string
 }
 
 // NewLogger creates a new logger instance
 func NewLogger() *Logger {
+	return NewFileLogger("logs")
+}
+
+// NewFileLogger creates a logger that writes to stdout and to a log file in
+// logDir
+func NewFileLogger(logDir string) *Logger {
 	logger := logrus.New()
 	
 	// Set log format
@@ -29,19 +37,23 @@ func NewLogger() *Logger {
 	logger.SetLevel(logrus.InfoLevel)
 
 	// Create log file
-	logDir := "logs"
 	if err := os.MkdirAll(logDir, 0755); err != nil {
 		logger.Warnf("Failed to create log directory: %v", err)
 	}
 
+	// The log records BMC requests and command output, keep it private;
+	// Chmod also tightens a log file created by an earlier version
 	logFile, err := os.OpenFile(
 		filepath.Join(logDir, "openshift_sno_hub_install.log"),
 		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
-		0666,
+		0600,
 	)
 	if err != nil {
 		logger.Warnf("Failed to open log file: %v", err)
 	} else {
+		if err := logFile.Chmod(0600); err != nil {
+			logger.Warnf("Failed to restrict log file permissions: %v", err)
+		}
 		// Write to both stdout and file
 		multiWriter := io.MultiWriter(os.Stdout, logFile)
 		logger.SetOutput(multiWriter)
@@ -50,9 +62,15 @@ func NewLogger() *Logger {
 	return &Logger{
 		Logger:  logger,
 		logFile: logFile,
+		logDir:  logDir,
 	}
 }
 
+// Dir returns the directory the log file is written to
+func (l *Logger) Dir() string {
+	return l.logDir
+}
+
 // Close closes the log file
 func (l *Logger) Close() error {
 	if l.logFile != nil {
@@ -100,4 +118,53 @@ func (l *Logger) LogSuccess(message string, args ...interface{}) {
 // LogDebug logs a debug message
 func (l *Logger) LogDebug(message string, args ...interface{}) {
 	l.LogWithLevel(logrus.DebugLevel, message, args...)
-}
\ No newline at end of file
+}
+
+// SetLevelName sets the log level by name: debug, info, warn or error
+func (l *Logger) SetLevelName(name string) error {
+	level, err := logrus.ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	l.SetLevel(level)
+	return nil
+}
+
+// SetJSONFormat writes log entries as JSON lines
+func (l *Logger) SetJSONFormat() {
+	l.SetFormatter(&logrus.JSONFormatter{TimestampFormat: time.RFC3339})
+}
+
+// UseStderr writes log entries to stderr instead of stdout, besides the log
+// file, leaving stdout to the output of commands
+func (l *Logger) UseStderr() {
+	var out io.Writer = os.Stderr
+	if l.logFile != nil {
+		out = io.MultiWriter(os.Stderr, l.logFile)
+	}
+	l.SetOutput(out)
+}
+
+// SetField adds key=value to every entry, e.g. the site of a fleet install
+func (l *Logger) SetField(key string, value interface{}) {
+	l.AddHook(fieldHook{key: key, value: value})
+}
+
+// fieldHook adds a fixed field to the entries of a logger
+type fieldHook struct {
+	key   string
+	value interface{}
+}
+
+// Levels implements logrus.Hook
+func (h fieldHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook
+func (h fieldHook) Fire(entry *logrus.Entry) error {
+	if _, ok := entry.Data[h.key]; !ok {
+		entry.Data[h.key] = h.value
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"openshift-sno-hub-installer/internal/idrac"
+	"openshift-sno-hub-installer/internal/logger"
+)
+
+// Source provides telemetry samples, implemented by the Redfish client
+type Source interface {
+	GetTelemetry(ctx context.Context) (*idrac.Telemetry, error)
+}
+
+// Poller samples the BMC telemetry at a fixed interval and keeps the latest sample
+type Poller struct {
+	source   Source
+	logger   *logger.Logger
+	interval time.Duration
+	bmc      string
+
+	mu       sync.Mutex
+	latest   *idrac.Telemetry
+	lastErr  error
+	failures int
+}
+
+// NewPoller creates a poller sampling source every interval
+func NewPoller(source Source, bmc string, interval time.Duration, log *logger.Logger) *Poller {
+	return &Poller{
+		source:   source,
+		logger:   log,
+		interval: interval,
+		bmc:      bmc,
+	}
+}
+
+// Run polls until ctx is cancelled, passing every successful sample to
+// onSample when it is not nil
+func (p *Poller) Run(ctx context.Context, onSample func(*idrac.Telemetry)) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		telemetry, err := p.source.GetTelemetry(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		p.mu.Lock()
+		p.lastErr = err
+		if err != nil {
+			p.failures++
+		} else {
+			p.latest = telemetry
+		}
+		p.mu.Unlock()
+
+		if err != nil {
+			p.logger.LogWarn("Failed to poll BMC telemetry: %v", err)
+		} else if onSample != nil {
+			onSample(telemetry)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ServeHTTP writes the latest sample in the Prometheus text exposition format
+func (p *Poller) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	latest, lastErr, failures := p.latest, p.lastErr, p.failures
+	p.mu.Unlock()
+
+	var buf bytes.Buffer
+	Write(&buf, p.bmc, latest, lastErr == nil && latest != nil, failures)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// Write writes a telemetry sample in the Prometheus text exposition format.
+// up reports whether the last poll succeeded; a stale sample is still written.
+func Write(w io.Writer, bmc string, t *idrac.Telemetry, up bool, failures int) {
+	e := &exposition{w: w, bmc: bmc, seen: make(map[string]bool)}
+
+	e.gauge("bmc_up", "Whether the last poll of the BMC succeeded.", boolValue(up))
+	e.counter("bmc_poll_failures_total", "Number of failed BMC telemetry polls.", float64(failures))
+	if t == nil {
+		return
+	}
+	e.gauge("bmc_last_poll_timestamp_seconds", "Time of the last successful BMC telemetry poll.",
+		float64(t.CollectedAt.Unix()))
+
+	for _, fan := range t.Thermal.Fans {
+		if fan.Reading != nil && !strings.EqualFold(fan.ReadingUnits, "Percent") {
+			e.gauge("bmc_fan_speed_rpm", "Fan speed in revolutions per minute.", *fan.Reading, "fan", fan.DisplayName())
+		}
+	}
+	for _, fan := range t.Thermal.Fans {
+		if fan.Reading != nil && strings.EqualFold(fan.ReadingUnits, "Percent") {
+			e.gauge("bmc_fan_speed_percent", "Fan speed in percent of its maximum.", *fan.Reading, "fan", fan.DisplayName())
+		}
+	}
+
+	for _, temperature := range t.Thermal.Temperatures {
+		if temperature.ReadingCelsius != nil {
+			e.gauge("bmc_temperature_celsius", "Temperature sensor reading.", *temperature.ReadingCelsius,
+				"sensor", valueOr(temperature.Name, temperature.MemberID), "context", temperature.PhysicalContext)
+		}
+	}
+	if inlet, ok := t.InletTemperature(); ok {
+		e.gauge("bmc_inlet_temperature_celsius", "Chassis inlet (intake) temperature.", inlet)
+	}
+
+	for _, psu := range t.Power.PowerSupplies {
+		if psu.PowerInputWatts != nil {
+			e.gauge("bmc_psu_input_watts", "Power supply input power.", *psu.PowerInputWatts,
+				"psu", valueOr(psu.Name, psu.MemberID))
+		}
+	}
+	for _, psu := range t.Power.PowerSupplies {
+		e.gauge("bmc_psu_healthy", "Whether the power supply reports OK health.", boolValue(psu.Status.Health == "OK"),
+			"psu", valueOr(psu.Name, psu.MemberID))
+	}
+
+	for _, control := range t.Power.PowerControl {
+		if control.PowerConsumedWatts != nil {
+			e.gauge("bmc_power_consumed_watts", "Power consumed by the chassis.", *control.PowerConsumedWatts,
+				"control", valueOr(control.Name, control.MemberID))
+		}
+	}
+	for _, control := range t.Power.PowerControl {
+		e.gauge("bmc_power_cap_enabled", "Whether a power cap is set.", boolValue(control.PowerLimit.LimitInWatts != nil),
+			"control", valueOr(control.Name, control.MemberID))
+	}
+	for _, control := range t.Power.PowerControl {
+		if control.PowerLimit.LimitInWatts != nil {
+			e.gauge("bmc_power_cap_watts", "Power cap limit.", *control.PowerLimit.LimitInWatts,
+				"control", valueOr(control.Name, control.MemberID), "exception", control.PowerLimit.LimitException)
+		}
+	}
+
+	for _, sensor := range t.Sensors {
+		if sensor.Reading != nil {
+			e.gauge("bmc_sensor_reading", "Reading of a Redfish Sensor resource.", *sensor.Reading,
+				"sensor", valueOr(sensor.Name, sensor.ID), "type", sensor.ReadingType, "units", sensor.ReadingUnits)
+		}
+	}
+}
+
+// exposition writes metric samples, emitting HELP and TYPE once per metric.
+// Samples of one metric must be written consecutively.
+type exposition struct {
+	w    io.Writer
+	bmc  string
+	seen map[string]bool
+}
+
+// labelEscaper escapes label values as required by the text format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// gauge writes a gauge sample with the given label name/value pairs
+func (e *exposition) gauge(name, help string, value float64, labels ...string) {
+	e.sample(name, "gauge", help, value, labels)
+}
+
+// counter writes a counter sample with the given label name/value pairs
+func (e *exposition) counter(name, help string, value float64, labels ...string) {
+	e.sample(name, "counter", help, value, labels)
+}
+
+// sample writes a sample, preceded by HELP and TYPE the first time name is seen
+func (e *exposition) sample(name, metricType, help string, value float64, labels []string) {
+	if !e.seen[name] {
+		e.seen[name] = true
+		fmt.Fprintf(e.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+	}
+
+	pairs := []string{fmt.Sprintf(`bmc="%s"`, labelEscaper.Replace(e.bmc))}
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], labelEscaper.Replace(labels[i+1])))
+	}
+	fmt.Fprintf(e.w, "%s{%s} %s\n", name, strings.Join(pairs, ","), strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// boolValue returns 1 for true and 0 for false
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// valueOr returns value, or fallback when value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
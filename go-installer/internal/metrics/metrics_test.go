@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"openshift-sno-hub-installer/internal/idrac"
+)
+
+func TestWrite(t *testing.T) {
+	rpm, percent, inlet, watts, limit := 5400.0, 30.0, 23.0, 312.0, 400.0
+	telemetry := &idrac.Telemetry{
+		CollectedAt: time.Unix(1760000000, 0),
+		Thermal: idrac.Thermal{
+			Fans: []idrac.Fan{
+				{Name: "Fan1A", Reading: &rpm, ReadingUnits: "RPM"},
+				{Name: "Fan \"2\"", Reading: &percent, ReadingUnits: "Percent"},
+			},
+			Temperatures: []idrac.Temperature{
+				{Name: "System Board Inlet Temp", ReadingCelsius: &inlet, PhysicalContext: "Intake"},
+			},
+		},
+		Power: idrac.Power{
+			PowerControl: []idrac.PowerControl{
+				{Name: "System Power Control", PowerConsumedWatts: &watts, PowerLimit: idrac.PowerLimit{LimitInWatts: &limit, LimitException: "LogEventOnly"}},
+			},
+			PowerSupplies: []idrac.PowerSupply{
+				{Name: "PS1", PowerInputWatts: &watts, Status: idrac.ResourceStatus{Health: "Critical"}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	Write(&buf, "192.168.1.228", telemetry, true, 2)
+	out := buf.String()
+
+	for _, want := range []string{
+		`bmc_up{bmc="192.168.1.228"} 1`,
+		`bmc_poll_failures_total{bmc="192.168.1.228"} 2`,
+		`bmc_last_poll_timestamp_seconds{bmc="192.168.1.228"} 1.76e+09`,
+		`bmc_fan_speed_rpm{bmc="192.168.1.228",fan="Fan1A"} 5400`,
+		`bmc_fan_speed_percent{bmc="192.168.1.228",fan="Fan \"2\""} 30`,
+		`bmc_inlet_temperature_celsius{bmc="192.168.1.228"} 23`,
+		`bmc_psu_input_watts{bmc="192.168.1.228",psu="PS1"} 312`,
+		`bmc_psu_healthy{bmc="192.168.1.228",psu="PS1"} 0`,
+		`bmc_power_cap_enabled{bmc="192.168.1.228",control="System Power Control"} 1`,
+		`bmc_power_cap_watts{bmc="192.168.1.228",control="System Power Control",exception="LogEventOnly"} 400`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("Expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "# TYPE bmc_temperature_celsius gauge") != 1 {
+		t.Errorf("Expected a single TYPE line per metric:\n%s", out)
+	}
+
+	buf.Reset()
+	Write(&buf, "192.168.1.228", nil, false, 3)
+	if !strings.Contains(buf.String(), `bmc_up{bmc="192.168.1.228"} 0`) || strings.Contains(buf.String(), "bmc_fan") {
+		t.Errorf("Expected only up and failures without a sample:\n%s", buf.String())
+	}
+}
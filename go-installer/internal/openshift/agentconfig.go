@@ -0,0 +1,79 @@
+package openshift
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AgentConfig represents the parts of agent-config.yaml used by the installer
+type AgentConfig struct {
+	Metadata struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	RendezvousIP string      `yaml:"rendezvousIP"`
+	Hosts        []AgentHost `yaml:"hosts"`
+}
+
+// AgentHost represents a host entry in agent-config.yaml
+type AgentHost struct {
+	Hostname        string           `yaml:"hostname"`
+	Role            string           `yaml:"role"`
+	RootDeviceHints RootDeviceHints  `yaml:"rootDeviceHints"`
+	Interfaces      []AgentInterface `yaml:"interfaces"`
+}
+
+// RootDeviceHints selects the installation disk of a host
+type RootDeviceHints struct {
+	DeviceName         string `yaml:"deviceName"`
+	HCTL               string `yaml:"hctl"`
+	Model              string `yaml:"model"`
+	Vendor             string `yaml:"vendor"`
+	SerialNumber       string `yaml:"serialNumber"`
+	MinSizeGigabytes   int    `yaml:"minSizeGigabytes"`
+	WWN                string `yaml:"wwn"`
+	WWNWithExtension   string `yaml:"wwnWithExtension"`
+	WWNVendorExtension string `yaml:"wwnVendorExtension"`
+	Rotational         *bool  `yaml:"rotational"`
+}
+
+// AgentInterface maps a host interface name to its MAC address
+type AgentInterface struct {
+	Name       string `yaml:"name"`
+	MACAddress string `yaml:"macAddress"`
+}
+
+// LoadAgentConfig reads and parses an agent-config.yaml file
+func LoadAgentConfig(path string) (*AgentConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent config: %w", err)
+	}
+
+	var agentConfig AgentConfig
+	if err := yaml.Unmarshal(data, &agentConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal agent config: %w", err)
+	}
+
+	return &agentConfig, nil
+}
+
+// LoadAgentConfig reads agent-config.yaml from the source directory
+func (i *Installer) LoadAgentConfig() (*AgentConfig, error) {
+	return LoadAgentConfig(filepath.Join(i.config.Paths.SourceDir, "agent-config.yaml"))
+}
+
+// Hints returns the non-empty identifying strings of the root device hints,
+// suitable for matching against BMC disk and boot option names. deviceName
+// is left out: a host device path never appears in those names.
+func (h RootDeviceHints) Hints() []string {
+	var hints []string
+	for _, hint := range []string{h.SerialNumber, h.Model, h.WWN} {
+		if hint != "" {
+			hints = append(hints, hint)
+		}
+	}
+	return hints
+}
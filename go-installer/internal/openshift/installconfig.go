@@ -0,0 +1,74 @@
+package openshift
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// InstallConfig represents the parts of install-config.yaml used by the installer
+type InstallConfig struct {
+	Metadata struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	BaseDomain   string        `yaml:"baseDomain"`
+	ControlPlane MachinePool   `yaml:"controlPlane"`
+	Compute      []MachinePool `yaml:"compute"`
+	Networking   struct {
+		MachineNetwork []MachineNetwork `yaml:"machineNetwork"`
+	} `yaml:"networking"`
+}
+
+// MachinePool represents a controlPlane or compute machine pool
+type MachinePool struct {
+	Name     string `yaml:"name"`
+	Replicas *int   `yaml:"replicas"`
+}
+
+// MachineNetwork represents an entry of networking.machineNetwork
+type MachineNetwork struct {
+	CIDR string `yaml:"cidr"`
+}
+
+// LoadInstallConfig reads and parses an install-config.yaml file
+func LoadInstallConfig(path string) (*InstallConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read install config: %w", err)
+	}
+
+	var installConfig InstallConfig
+	if err := yaml.Unmarshal(data, &installConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal install config: %w", err)
+	}
+
+	return &installConfig, nil
+}
+
+// LoadInstallConfig reads install-config.yaml from the source directory
+func (i *Installer) LoadInstallConfig() (*InstallConfig, error) {
+	return LoadInstallConfig(filepath.Join(i.config.Paths.SourceDir, "install-config.yaml"))
+}
+
+// ExpectedHosts returns the number of hosts the cluster is installed on. The
+// installer defaults to 3 control plane and 3 compute replicas when unset.
+func (c *InstallConfig) ExpectedHosts() int {
+	hosts := replicasOr(c.ControlPlane.Replicas, 3)
+	if len(c.Compute) == 0 {
+		return hosts + 3
+	}
+	for _, pool := range c.Compute {
+		hosts += replicasOr(pool.Replicas, 3)
+	}
+	return hosts
+}
+
+// replicasOr returns the replica count, or fallback when it is unset
+func replicasOr(replicas *int, fallback int) int {
+	if replicas == nil {
+		return fallback
+	}
+	return *replicas
+}
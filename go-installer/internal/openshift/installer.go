@@ -7,22 +7,28 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"openshift-sno-hub-installer/internal/config"
 	"openshift-sno-hub-installer/internal/logger"
+	"openshift-sno-hub-installer/internal/runner"
 )
 
 // Installer handles OpenShift installation operations
 type Installer struct {
 	config *config.Config
 	logger *logger.Logger
+	// runner runs oc and openshift-install and changes the work directory,
+	// or prints what it would do in dry-run mode
+	runner *runner.Runner
 }
 
 // NewInstaller creates a new OpenShift installer
-func NewInstaller(cfg *config.Config, log *logger.Logger) *Installer {
+func NewInstaller(cfg *config.Config, log *logger.Logger, run *runner.Runner) *Installer {
 	return &Installer{
 		config: cfg,
 		logger: log,
+		runner: run,
 	}
 }
 
@@ -43,15 +49,16 @@ func (i *Installer) ExtractInstaller(ctx context.Context) error {
 
 	i.logger.LogInfo("Release digest: %s", releaseDigest)
 
-	// Extract openshift-install command
+	// Extract openshift-install command next to paths.installer_path
 	cmd := exec.CommandContext(ctx, "oc", "adm", "release", "extract",
 		"-a", i.config.OpenShift.RegistryAuthFile,
 		"--command=openshift-install",
+		"--to", filepath.Dir(i.config.Paths.InstallerPath),
 		releaseDigest)
 
 	i.logger.LogInfo("Running: %s", strings.Join(cmd.Args, " "))
 	
-	output, err := cmd.CombinedOutput()
+	output, err := i.runner.CombinedOutput(cmd)
 	if err != nil {
 		i.logger.LogError("Failed to extract installer: %s", string(output))
 		return fmt.Errorf("failed to extract installer: %w", err)
@@ -63,11 +70,18 @@ func (i *Installer) ExtractInstaller(ctx context.Context) error {
 
 // getReleaseDigest gets the release digest for the specified version
 func (i *Installer) getReleaseDigest(ctx context.Context) (string, error) {
+	release := "quay.io/openshift-release-dev/ocp-release:" + i.config.OpenShift.Version + "-x86_64"
 	cmd := exec.CommandContext(ctx, "oc", "adm", "release", "info",
-		"quay.io/openshift-release-dev/ocp-release:"+i.config.OpenShift.Version+"-x86_64",
+		release,
 		"--registry-config", i.config.OpenShift.RegistryAuthFile)
 
-	output, err := cmd.CombinedOutput()
+	// The dry-run plan names the release instead of its digest
+	if i.runner.DryRun() {
+		i.runner.Plan("exec: %s", runner.CommandLine(cmd))
+		return release, nil
+	}
+
+	output, err := i.runner.CombinedOutput(cmd)
 	if err != nil {
 		return "", fmt.Errorf("failed to get release info: %w", err)
 	}
@@ -90,13 +104,14 @@ func (i *Installer) getReleaseDigest(ctx context.Context) (string, error) {
 func (i *Installer) PrepareWorkDir(ctx context.Context) error {
 	i.logger.LogInfo("Preparing work directory...")
 
-	// Clean existing workdir if it exists
-	if err := i.cleanWorkDir(); err != nil {
-		return fmt.Errorf("failed to clean work directory: %w", err)
+	// Keep the workdir of a previous run, it holds its kubeconfig and
+	// installer state
+	if err := i.archiveWorkDir(); err != nil {
+		return fmt.Errorf("failed to keep previous work directory: %w", err)
 	}
 
 	// Create workdir
-	if err := os.MkdirAll(i.config.Paths.WorkDir, 0755); err != nil {
+	if err := i.runner.MkdirAll(i.config.Paths.WorkDir, 0755); err != nil {
 		return fmt.Errorf("failed to create work directory: %w", err)
 	}
 
@@ -114,14 +129,20 @@ func (i *Installer) PrepareWorkDir(ctx context.Context) error {
 	return nil
 }
 
-// cleanWorkDir cleans the existing work directory
-func (i *Installer) cleanWorkDir() error {
-	if _, err := os.Stat(i.config.Paths.WorkDir); os.IsNotExist(err) {
-		return nil // Directory doesn't exist, nothing to clean
+// archiveWorkDir moves a non-empty work directory aside to
+// <workdir>-<timestamp>, so no generated artifact is ever deleted
+func (i *Installer) archiveWorkDir() error {
+	entries, err := os.ReadDir(i.config.Paths.WorkDir)
+	if os.IsNotExist(err) || (err == nil && len(entries) == 0) {
+		return nil
+	}
+	if err != nil {
+		return err
 	}
 
-	i.logger.LogInfo("Cleaning existing work directory...")
-	return os.RemoveAll(i.config.Paths.WorkDir)
+	archive := filepath.Clean(i.config.Paths.WorkDir) + "-" + time.Now().Format("20060102-150405")
+	i.logger.LogWarn("Moving previous work directory to %s", archive)
+	return i.runner.Rename(i.config.Paths.WorkDir, archive)
 }
 
 // copyOpenshiftDir copies the openshift directory from source
@@ -136,7 +157,7 @@ func (i *Installer) copyOpenshiftDir() error {
 	i.logger.LogInfo("Copying %s -> %s", sourceOpenshiftDir, destOpenshiftDir)
 	
 	cmd := exec.Command("cp", "-r", sourceOpenshiftDir, i.config.Paths.WorkDir)
-	output, err := cmd.CombinedOutput()
+	output, err := i.runner.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to copy openshift directory: %s", string(output))
 	}
@@ -159,7 +180,7 @@ func (i *Installer) copyConfigFiles() error {
 		i.logger.LogInfo("Copying %s -> %s", sourceFile, destFile)
 		
 		cmd := exec.Command("cp", sourceFile, destFile)
-		output, err := cmd.CombinedOutput()
+		output, err := i.runner.CombinedOutput(cmd)
 		if err != nil {
 			return fmt.Errorf("failed to copy %s: %s", filename, string(output))
 		}
@@ -173,12 +194,12 @@ func (i *Installer) CreateAgentImage(ctx context.Context) error {
 	i.logger.LogInfo("Creating agent image...")
 
 	// Check if installer exists and is executable
-	if _, err := os.Stat(i.config.Paths.InstallerPath); os.IsNotExist(err) {
-		return fmt.Errorf("openshift-install not found: %s", i.config.Paths.InstallerPath)
+	if err := i.checkGenerated(i.config.Paths.InstallerPath, "openshift-install"); err != nil {
+		return err
 	}
 
 	// Make installer executable
-	if err := os.Chmod(i.config.Paths.InstallerPath, 0755); err != nil {
+	if err := i.runner.Chmod(i.config.Paths.InstallerPath, 0755); err != nil {
 		return fmt.Errorf("failed to make installer executable: %w", err)
 	}
 
@@ -190,7 +211,7 @@ func (i *Installer) CreateAgentImage(ctx context.Context) error {
 
 	i.logger.LogInfo("Running: %s", strings.Join(cmd.Args, " "))
 	
-	output, err := cmd.CombinedOutput()
+	output, err := i.runner.CombinedOutput(cmd)
 	if err != nil {
 		i.logger.LogError("Failed to create agent image: %s", string(output))
 		return fmt.Errorf("failed to create agent image: %w", err)
@@ -204,20 +225,17 @@ func (i *Installer) CreateAgentImage(ctx context.Context) error {
 func (i *Installer) WaitForInstallComplete(ctx context.Context) error {
 	i.logger.LogInfo("Waiting for installation to complete...")
 
-	// Set KUBECONFIG environment variable
+	// Run openshift-install agent wait-for install-complete. KUBECONFIG is
+	// set on the command only, installs of several clusters may run at once.
 	kubeconfigPath := filepath.Join(i.config.Paths.WorkDir, "auth", "kubeconfig")
-	if err := os.Setenv("KUBECONFIG", kubeconfigPath); err != nil {
-		return fmt.Errorf("failed to set KUBECONFIG: %w", err)
-	}
-
-	// Run openshift-install agent wait-for install-complete
 	cmd := exec.CommandContext(ctx, i.config.Paths.InstallerPath,
 		"agent", "wait-for", "install-complete",
 		"--dir", i.config.Paths.WorkDir)
+	cmd.Env = append(os.Environ(), "KUBECONFIG="+kubeconfigPath)
 
 	i.logger.LogInfo("Running: %s", strings.Join(cmd.Args, " "))
 	
-	output, err := cmd.CombinedOutput()
+	output, err := i.runner.CombinedOutput(cmd)
 	if err != nil {
 		i.logger.LogError("Installation wait failed: %s", string(output))
 		return fmt.Errorf("installation wait failed: %w", err)
@@ -227,6 +245,19 @@ func (i *Installer) WaitForInstallComplete(ctx context.Context) error {
 	return nil
 }
 
+// checkGenerated checks that a file produced by an earlier step exists. A dry
+// run produces nothing, so there a missing file is only noted.
+func (i *Installer) checkGenerated(path, what string) error {
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return nil
+	}
+	if !i.runner.DryRun() {
+		return fmt.Errorf("%s not found: %s", what, path)
+	}
+	i.runner.Plan("%s %s does not exist yet, an earlier step creates it", what, path)
+	return nil
+}
+
 // GetISOFilePath returns the path to the generated ISO file
 func (i *Installer) GetISOFilePath() string {
 	return i.config.GetISOFilePath()
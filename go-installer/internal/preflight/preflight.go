@@ -0,0 +1,260 @@
+package preflight
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"openshift-sno-hub-installer/internal/idrac"
+	"openshift-sno-hub-installer/internal/logger"
+	"openshift-sno-hub-installer/internal/openshift"
+)
+
+// Status is the outcome of a single pre-flight check
+type Status string
+
+const (
+	StatusPass Status = "PASS"
+	StatusWarn Status = "WARN"
+	StatusFail Status = "FAIL"
+)
+
+// Result is the outcome of a single pre-flight check
+type Result struct {
+	Check   string
+	Status  Status
+	Message string
+}
+
+// Report collects the results of every pre-flight check
+type Report struct {
+	Results []Result
+}
+
+// add records a check result
+func (r *Report) add(check string, status Status, format string, args ...interface{}) {
+	r.Results = append(r.Results, Result{Check: check, Status: status, Message: fmt.Sprintf(format, args...)})
+}
+
+// Failed reports whether any check failed
+func (r *Report) Failed() bool {
+	for _, result := range r.Results {
+		if result.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Log writes the report, one line per check
+func (r *Report) Log(log *logger.Logger) {
+	log.LogInfo("Pre-flight validation report:")
+	for _, result := range r.Results {
+		line := fmt.Sprintf("  [%s] %s: %s", result.Status, result.Check, result.Message)
+		switch result.Status {
+		case StatusFail:
+			log.LogError("%s", line)
+		case StatusWarn:
+			log.LogWarn("%s", line)
+		default:
+			log.LogInfo("%s", line)
+		}
+	}
+}
+
+// Validate cross-checks agent-config.yaml and install-config.yaml against the
+// hardware inventory reported by the BMC of the host being installed
+func Validate(agentConfig *openshift.AgentConfig, installConfig *openshift.InstallConfig, inventory *idrac.Inventory) *Report {
+	report := &Report{}
+
+	checkHostCount(report, agentConfig, installConfig)
+	checkRendezvousIP(report, agentConfig, installConfig)
+
+	host := SelectHost(agentConfig, inventory)
+	if host == nil {
+		report.add("hosts", StatusFail, "agent-config.yaml declares no hosts")
+		return report
+	}
+	checkMACAddresses(report, host, inventory)
+	checkRootDevice(report, host, inventory)
+
+	return report
+}
+
+// checkHostCount compares the hosts of agent-config.yaml with the replicas of install-config.yaml
+func checkHostCount(report *Report, agentConfig *openshift.AgentConfig, installConfig *openshift.InstallConfig) {
+	expected := installConfig.ExpectedHosts()
+	if len(agentConfig.Hosts) != expected {
+		report.add("host count", StatusFail, "agent-config.yaml declares %d host(s), install-config.yaml expects %d",
+			len(agentConfig.Hosts), expected)
+		return
+	}
+	report.add("host count", StatusPass, "%d host(s)", expected)
+}
+
+// checkRendezvousIP verifies that rendezvousIP lies inside a machineNetwork CIDR
+func checkRendezvousIP(report *Report, agentConfig *openshift.AgentConfig, installConfig *openshift.InstallConfig) {
+	ip := net.ParseIP(agentConfig.RendezvousIP)
+	if ip == nil {
+		report.add("rendezvousIP", StatusFail, "invalid rendezvousIP %q", agentConfig.RendezvousIP)
+		return
+	}
+
+	var cidrs []string
+	for _, network := range installConfig.Networking.MachineNetwork {
+		_, ipNet, err := net.ParseCIDR(network.CIDR)
+		if err != nil {
+			report.add("rendezvousIP", StatusFail, "invalid machineNetwork CIDR %q", network.CIDR)
+			return
+		}
+		if ipNet.Contains(ip) {
+			report.add("rendezvousIP", StatusPass, "%s is inside machineNetwork %s", ip, network.CIDR)
+			return
+		}
+		cidrs = append(cidrs, network.CIDR)
+	}
+
+	report.add("rendezvousIP", StatusFail, "%s is outside machineNetwork [%s]", ip, strings.Join(cidrs, ", "))
+}
+
+// SelectHost returns the agent-config host whose interfaces are found on this
+// BMC, or the first host when none matches
+func SelectHost(agentConfig *openshift.AgentConfig, inventory *idrac.Inventory) *openshift.AgentHost {
+	if len(agentConfig.Hosts) == 0 {
+		return nil
+	}
+
+	macs := inventory.MACAddresses()
+	for i, host := range agentConfig.Hosts {
+		for _, iface := range host.Interfaces {
+			if containsMAC(macs, iface.MACAddress) {
+				return &agentConfig.Hosts[i]
+			}
+		}
+	}
+	return &agentConfig.Hosts[0]
+}
+
+// checkMACAddresses verifies that every interface MAC of the host exists on the server
+func checkMACAddresses(report *Report, host *openshift.AgentHost, inventory *idrac.Inventory) {
+	macs := inventory.MACAddresses()
+	if len(macs) == 0 {
+		report.add("interfaces", StatusWarn, "the BMC reports no MAC addresses, cannot verify %s", host.Hostname)
+		return
+	}
+
+	for _, iface := range host.Interfaces {
+		check := fmt.Sprintf("interface %s", iface.Name)
+		if containsMAC(macs, iface.MACAddress) {
+			report.add(check, StatusPass, "MAC %s found on %s", iface.MACAddress, host.Hostname)
+		} else {
+			report.add(check, StatusFail, "MAC %s of %s not found, server reports [%s]",
+				iface.MACAddress, host.Hostname, strings.Join(macs, ", "))
+		}
+	}
+}
+
+// checkRootDevice verifies that a drive matches the rootDeviceHints of the host
+func checkRootDevice(report *Report, host *openshift.AgentHost, inventory *idrac.Inventory) {
+	hints := host.RootDeviceHints
+	drives := inventory.Drives()
+	if len(drives) == 0 {
+		report.add("root device", StatusWarn, "the BMC reports no drives, cannot verify rootDeviceHints of %s", host.Hostname)
+		return
+	}
+
+	if hints.DeviceName != "" && !checkDeviceName(report, hints.DeviceName, inventory) {
+		return
+	}
+
+	if hints.SerialNumber == "" && hints.Model == "" && hints.Vendor == "" && hints.WWN == "" && hints.MinSizeGigabytes == 0 {
+		if hints.DeviceName == "" {
+			report.add("root device", StatusPass, "no rootDeviceHints, %d drive(s) present", len(drives))
+		}
+		return
+	}
+
+	for _, drive := range drives {
+		if driveMatches(drive, hints) {
+			report.add("root device", StatusPass, "drive %s (%s %s) matches rootDeviceHints", drive.ID, drive.Model, drive.SerialNumber)
+			return
+		}
+	}
+	report.add("root device", StatusFail, "no drive matches rootDeviceHints of %s", host.Hostname)
+}
+
+// checkDeviceName verifies the deviceName hint against the by-path names
+// derived from the inventory. deviceName is a kernel path, so it can only be
+// checked for by-path names when the controller PCI address is known. It
+// returns false when the check failed.
+func checkDeviceName(report *Report, deviceName string, inventory *idrac.Inventory) bool {
+	paths := inventory.DiskPaths()
+	if !strings.HasPrefix(deviceName, "/dev/disk/by-path/") || len(paths) == 0 {
+		report.add("root device", StatusWarn, "deviceName %s cannot be verified through Redfish, %d drive(s) present",
+			deviceName, len(inventory.Drives()))
+		return true
+	}
+
+	if !containsString(paths, deviceName) {
+		report.add("root device", StatusFail, "deviceName %s not found, server reports [%s]",
+			deviceName, strings.Join(paths, ", "))
+		return false
+	}
+
+	report.add("root device", StatusPass, "deviceName %s found", deviceName)
+	return true
+}
+
+// driveMatches reports whether a drive satisfies every set root device hint
+func driveMatches(drive idrac.Drive, hints openshift.RootDeviceHints) bool {
+	if hints.SerialNumber != "" && !strings.EqualFold(drive.SerialNumber, hints.SerialNumber) {
+		return false
+	}
+	if hints.Model != "" && !strings.Contains(strings.ToLower(drive.Model), strings.ToLower(hints.Model)) {
+		return false
+	}
+	if hints.Vendor != "" && !strings.Contains(strings.ToLower(drive.Manufacturer), strings.ToLower(hints.Vendor)) {
+		return false
+	}
+	if hints.MinSizeGigabytes > 0 && drive.CapacityBytes < int64(hints.MinSizeGigabytes)*1000*1000*1000 {
+		return false
+	}
+	if hints.WWN != "" {
+		found := false
+		for _, id := range drive.Identifiers {
+			if normalizeWWN(id.DurableName) == normalizeWWN(hints.WWN) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeWWN strips the 0x prefix and case from a WWN
+func normalizeWWN(wwn string) string {
+	return strings.TrimPrefix(strings.ToLower(wwn), "0x")
+}
+
+// containsString reports whether list contains value
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// containsMAC reports whether macs (upper-case) contains mac
+func containsMAC(macs []string, mac string) bool {
+	mac = strings.ToUpper(mac)
+	for _, m := range macs {
+		if m == mac {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package preflight
+
+import (
+	"testing"
+
+	"openshift-sno-hub-installer/internal/idrac"
+	"openshift-sno-hub-installer/internal/openshift"
+)
+
+func TestValidate(t *testing.T) {
+	one, zero := 1, 0
+	installConfig := &openshift.InstallConfig{
+		ControlPlane: openshift.MachinePool{Name: "master", Replicas: &one},
+		Compute:      []openshift.MachinePool{{Name: "worker", Replicas: &zero}},
+	}
+	installConfig.Networking.MachineNetwork = []openshift.MachineNetwork{{CIDR: "192.168.1.0/24"}}
+
+	inventory := &idrac.Inventory{
+		EthernetInterfaces: []idrac.EthernetInterface{{ID: "NIC.1", MACAddress: "84:16:0C:2A:83:FE"}},
+		Storage: []idrac.Storage{{ID: "RAID.1", Drives: []idrac.Drive{
+			{ID: "Disk.0", Model: "MZ7L3480HCHQ", SerialNumber: "S4ENNF0N123456", CapacityBytes: 480103981056},
+		}}},
+	}
+
+	newAgentConfig := func() *openshift.AgentConfig {
+		return &openshift.AgentConfig{
+			RendezvousIP: "192.168.1.133",
+			Hosts: []openshift.AgentHost{{
+				Hostname:        "master-0",
+				RootDeviceHints: openshift.RootDeviceHints{SerialNumber: "S4ENNF0N123456"},
+				Interfaces:      []openshift.AgentInterface{{Name: "eno1np0", MACAddress: "84:16:0c:2a:83:fe"}},
+			}},
+		}
+	}
+
+	t.Run("Pass", func(t *testing.T) {
+		report := Validate(newAgentConfig(), installConfig, inventory)
+		if report.Failed() {
+			t.Errorf("Expected validation to pass, got %+v", report.Results)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		modify func(*openshift.AgentConfig)
+		check  string
+	}{
+		{"UnknownMAC", func(c *openshift.AgentConfig) { c.Hosts[0].Interfaces[0].MACAddress = "00:11:22:33:44:55" }, "interface eno1np0"},
+		{"UnknownDisk", func(c *openshift.AgentConfig) { c.Hosts[0].RootDeviceHints.SerialNumber = "OTHER" }, "root device"},
+		{"DiskTooSmall", func(c *openshift.AgentConfig) { c.Hosts[0].RootDeviceHints.MinSizeGigabytes = 960 }, "root device"},
+		{"RendezvousOutsideMachineNetwork", func(c *openshift.AgentConfig) { c.RendezvousIP = "10.0.0.5" }, "rendezvousIP"},
+		{"TooManyHosts", func(c *openshift.AgentConfig) { c.Hosts = append(c.Hosts, c.Hosts[0]) }, "host count"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agentConfig := newAgentConfig()
+			tt.modify(agentConfig)
+
+			report := Validate(agentConfig, installConfig, inventory)
+			if !report.Failed() {
+				t.Fatalf("Expected validation to fail")
+			}
+			for _, result := range report.Results {
+				if result.Status == StatusFail && result.Check != tt.check {
+					t.Errorf("Unexpected failed check %s: %s", result.Check, result.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateDeviceName(t *testing.T) {
+	one := 1
+	installConfig := &openshift.InstallConfig{
+		ControlPlane: openshift.MachinePool{Replicas: &one},
+		Compute:      []openshift.MachinePool{{Replicas: new(int)}},
+	}
+	installConfig.Networking.MachineNetwork = []openshift.MachineNetwork{{CIDR: "192.168.1.0/24"}}
+
+	inventory := &idrac.Inventory{
+		Storage: []idrac.Storage{{ID: "RAID.Integrated.1-1", Drives: []idrac.Drive{
+			{ID: "Disk.Bay.0:Enclosure.Internal.0-1:RAID.Integrated.1-1", DiskByPath: "/dev/disk/by-path/pci-0000:02:00.0-scsi-0:0:0:0"},
+		}}},
+	}
+
+	for deviceName, wantFail := range map[string]bool{
+		"/dev/disk/by-path/pci-0000:02:00.0-scsi-0:0:0:0": false,
+		"/dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:0:0": true,
+	} {
+		agentConfig := &openshift.AgentConfig{
+			RendezvousIP: "192.168.1.133",
+			Hosts:        []openshift.AgentHost{{Hostname: "master-0", RootDeviceHints: openshift.RootDeviceHints{DeviceName: deviceName}}},
+		}
+		if report := Validate(agentConfig, installConfig, inventory); report.Failed() != wantFail {
+			t.Errorf("deviceName %s: expected failed=%v, got %+v", deviceName, wantFail, report.Results)
+		}
+	}
+}
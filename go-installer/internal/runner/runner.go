@@ -0,0 +1,165 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// redacted replaces secret values in printed plans
+const redacted = "********"
+
+// Runner runs external commands and changes the filesystem. In dry-run mode
+// it prints what it would do instead.
+type Runner struct {
+	mu     sync.Mutex
+	out    io.Writer
+	dryRun bool
+	prefix string
+}
+
+// New creates a runner that prints its dry-run plan to out
+func New(out io.Writer) *Runner {
+	return &Runner{out: out}
+}
+
+// SetOutput sets where the dry-run plan is printed
+func (r *Runner) SetOutput(out io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.out = out
+}
+
+// SetPrefix labels the lines of the dry-run plan, e.g. with the site of a
+// fleet install
+func (r *Runner) SetPrefix(prefix string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prefix = prefix
+}
+
+// SetDryRun turns dry-run mode on or off
+func (r *Runner) SetDryRun(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dryRun = enabled
+}
+
+// DryRun reports whether the runner is in dry-run mode
+func (r *Runner) DryRun() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.dryRun
+}
+
+// Plan prints a line of the dry-run plan
+func (r *Runner) Plan(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	line := fmt.Sprintf(format, args...)
+	if r.prefix != "" {
+		line = r.prefix + ": " + line
+	}
+	fmt.Fprintf(r.out, "[dry-run] %s\n", line)
+}
+
+// CombinedOutput runs cmd and returns its combined stdout and stderr; in
+// dry-run mode it prints the command line and returns no output
+func (r *Runner) CombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	if r.DryRun() {
+		r.Plan("exec: %s", CommandLine(cmd))
+		return nil, nil
+	}
+	return cmd.CombinedOutput()
+}
+
+// MkdirAll creates a directory and its parents
+func (r *Runner) MkdirAll(path string, perm os.FileMode) error {
+	if r.DryRun() {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			r.Plan("mkdir -p %s (%#o)", path, perm)
+		}
+		return nil
+	}
+	return os.MkdirAll(path, perm)
+}
+
+// WriteFile writes data to a file
+func (r *Runner) WriteFile(path string, data []byte, perm os.FileMode) error {
+	if r.DryRun() {
+		r.Plan("write %s (%d bytes, %#o)", path, len(data), perm)
+		return nil
+	}
+	return os.WriteFile(path, data, perm)
+}
+
+// Rename renames a file or directory
+func (r *Runner) Rename(oldpath, newpath string) error {
+	if r.DryRun() {
+		r.Plan("mv %s %s", oldpath, newpath)
+		return nil
+	}
+	return os.Rename(oldpath, newpath)
+}
+
+// Chmod changes the mode of a file
+func (r *Runner) Chmod(path string, perm os.FileMode) error {
+	if r.DryRun() {
+		r.Plan("chmod %#o %s", perm, path)
+		return nil
+	}
+	return os.Chmod(path, perm)
+}
+
+// CommandLine renders cmd as a shell command line. Environment variables the
+// command adds are included, with the values of secrets masked.
+func CommandLine(cmd *exec.Cmd) string {
+	var parts []string
+	if cmd.Dir != "" {
+		parts = append(parts, "cd "+quote(cmd.Dir)+" &&")
+	}
+
+	inherited := make(map[string]bool)
+	for _, env := range os.Environ() {
+		inherited[env] = true
+	}
+	for _, env := range cmd.Env {
+		if inherited[env] {
+			continue
+		}
+		name, value, _ := strings.Cut(env, "=")
+		if isSecret(name) {
+			parts = append(parts, name+"="+redacted)
+			continue
+		}
+		parts = append(parts, name+"="+quote(value))
+	}
+
+	for _, arg := range cmd.Args {
+		parts = append(parts, quote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// isSecret reports whether an environment variable holds a credential
+func isSecret(name string) bool {
+	name = strings.ToUpper(name)
+	for _, marker := range []string{"PASS", "TOKEN", "SECRET"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// quote quotes a command line argument when the shell would split or expand it
+func quote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n\"'\\$`|&;<>()*?[]{}!#~") {
+		return arg
+	}
+	return strconv.Quote(arg)
+}
@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	var out bytes.Buffer
+	r := New(&out)
+	r.SetDryRun(true)
+
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "marker")
+
+	cmd := exec.Command("touch", marker)
+	cmd.Env = append(os.Environ(), "SSHPASS=calvin", "KUBECONFIG=/tmp/auth/kubeconfig")
+	if output, err := r.CombinedOutput(cmd); err != nil || output != nil {
+		t.Fatalf("Expected no output and no error, got %q, %v", output, err)
+	}
+	if err := r.WriteFile(filepath.Join(dir, "state.json"), []byte("{}"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := r.MkdirAll(filepath.Join(dir, "workdir"), 0755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := r.Rename(dir, dir+"-old"); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("Expected the dry run to leave %s untouched, found %d entries", dir, len(entries))
+	}
+
+	plan := out.String()
+	for _, want := range []string{
+		"[dry-run] exec: SSHPASS=******** KUBECONFIG=/tmp/auth/kubeconfig touch " + marker,
+		"[dry-run] write " + filepath.Join(dir, "state.json") + " (2 bytes, 0644)",
+		"[dry-run] mkdir -p " + filepath.Join(dir, "workdir") + " (0755)",
+		"[dry-run] mv " + dir + " " + dir + "-old",
+	} {
+		if !strings.Contains(plan, want) {
+			t.Errorf("Expected plan to contain %q, got:\n%s", want, plan)
+		}
+	}
+	if strings.Contains(plan, "calvin") {
+		t.Errorf("Expected the password to be masked, got:\n%s", plan)
+	}
+
+	// Without dry run the command runs
+	r.SetDryRun(false)
+	if _, err := r.CombinedOutput(exec.Command("touch", marker)); err != nil {
+		t.Fatalf("CombinedOutput failed: %v", err)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Errorf("Expected the command to run: %v", err)
+	}
+}
+
+func TestPlanPrefix(t *testing.T) {
+	var out bytes.Buffer
+	r := New(&out)
+	r.SetDryRun(true)
+	r.SetPrefix("edge-01")
+
+	if err := r.Chmod("openshift-install", 0755); err != nil {
+		t.Fatalf("Chmod failed: %v", err)
+	}
+	want := "[dry-run] edge-01: chmod 0755 openshift-install\n"
+	if got := out.String(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	cmd := exec.Command("ssh", "-o", "StrictHostKeyChecking=no", "rock@192.168.1.21", "echo 'SSH connection successful'")
+	want := `ssh -o StrictHostKeyChecking=no rock@192.168.1.21 "echo 'SSH connection successful'"`
+	if got := CommandLine(cmd); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
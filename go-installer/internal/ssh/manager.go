@@ -3,26 +3,32 @@ package ssh
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"openshift-sno-hub-installer/internal/config"
 	"openshift-sno-hub-installer/internal/logger"
+	"openshift-sno-hub-installer/internal/runner"
 )
 
 // Manager handles SSH operations
 type Manager struct {
 	config *config.Config
 	logger *logger.Logger
+	// runner runs ssh, scp and ssh-copy-id, or prints them in dry-run mode
+	runner *runner.Runner
 }
 
 // NewManager creates a new SSH manager
-func NewManager(cfg *config.Config, log *logger.Logger) *Manager {
+func NewManager(cfg *config.Config, log *logger.Logger, run *runner.Runner) *Manager {
 	return &Manager{
 		config: cfg,
 		logger: log,
+		runner: run,
 	}
 }
 
@@ -52,7 +58,7 @@ func (m *Manager) generateSSHKey(ctx context.Context) error {
 		"-N", "",
 		"-q")
 
-	output, err := cmd.CombinedOutput()
+	output, err := m.runner.CombinedOutput(cmd)
 	if err != nil {
 		m.logger.LogError("Failed to generate SSH key: %s", string(output))
 		return fmt.Errorf("failed to generate SSH key: %w", err)
@@ -68,20 +74,25 @@ func (m *Manager) SetupSSHKey(ctx context.Context) error {
 
 	sshKeyPath := m.config.Paths.SSHKeyPath
 	if _, err := os.Stat(sshKeyPath); os.IsNotExist(err) {
-		return fmt.Errorf("SSH public key not found: %s", sshKeyPath)
+		if !m.runner.DryRun() {
+			return fmt.Errorf("SSH public key not found: %s", sshKeyPath)
+		}
+		m.runner.Plan("SSH public key %s does not exist yet, ssh-keygen creates it", sshKeyPath)
 	}
 
-	// Use sshpass to copy the key
+	// Use sshpass to copy the key; the password is passed in the
+	// environment to keep it off the process list
 	cmd := exec.CommandContext(ctx, "sshpass",
-		"-p", m.config.IDRAC.Password,
+		"-e",
 		"ssh-copy-id",
 		"-i", sshKeyPath,
 		"-o", "StrictHostKeyChecking=no",
 		fmt.Sprintf("%s@%s", m.config.Remote.User, m.config.Remote.Host))
+	cmd.Env = append(os.Environ(), "SSHPASS="+m.config.IDRAC.Password)
 
 	m.logger.LogInfo("Copying SSH key to %s@%s...", m.config.Remote.User, m.config.Remote.Host)
 	
-	output, err := cmd.CombinedOutput()
+	output, err := m.runner.CombinedOutput(cmd)
 	if err != nil {
 		m.logger.LogError("Failed to copy SSH key: %s", string(output))
 		return fmt.Errorf("failed to copy SSH key: %w", err)
@@ -95,7 +106,7 @@ func (m *Manager) SetupSSHKey(ctx context.Context) error {
 func (m *Manager) CopyFileToRemote(ctx context.Context, localPath, remotePath string) error {
 	m.logger.LogInfo("Copying file to remote host...")
 
-	if _, err := os.Stat(localPath); os.IsNotExist(err) {
+	if _, err := os.Stat(localPath); os.IsNotExist(err) && !m.runner.DryRun() {
 		return fmt.Errorf("local file not found: %s", localPath)
 	}
 
@@ -107,7 +118,7 @@ func (m *Manager) CopyFileToRemote(ctx context.Context, localPath, remotePath st
 
 	m.logger.LogInfo("Copying %s to %s@%s:%s", localPath, m.config.Remote.User, m.config.Remote.Host, remotePath)
 	
-	output, err := cmd.CombinedOutput()
+	output, err := m.runner.CombinedOutput(cmd)
 	if err != nil {
 		m.logger.LogError("Failed to copy file: %s", string(output))
 		return fmt.Errorf("failed to copy file: %w", err)
@@ -121,15 +132,27 @@ func (m *Manager) CopyFileToRemote(ctx context.Context, localPath, remotePath st
 func (m *Manager) CopyISOToRemote(ctx context.Context, isoPath string) error {
 	m.logger.LogInfo("Copying ISO to remote host...")
 
-	if _, err := os.Stat(isoPath); os.IsNotExist(err) {
+	if _, err := os.Stat(isoPath); os.IsNotExist(err) && !m.runner.DryRun() {
 		m.logger.LogWarn("ISO file not found: %s", isoPath)
 		return fmt.Errorf("ISO file not found: %s", isoPath)
 	}
 
-	remotePath := filepath.Join(m.config.Remote.Path, filepath.Base(isoPath))
+	remotePath := filepath.Join(m.config.Remote.Path, m.remoteISOName(isoPath))
 	return m.CopyFileToRemote(ctx, isoPath, remotePath)
 }
 
+// remoteISOName returns the file name of the ISO on the remote host: the last
+// element of remote.iso_url, so the BMC boots the ISO that was copied even when
+// several clusters share the web server directory
+func (m *Manager) remoteISOName(isoPath string) string {
+	if u, err := url.Parse(m.config.Remote.ISOURL); err == nil {
+		if name := path.Base(u.Path); name != "." && name != "/" {
+			return name
+		}
+	}
+	return filepath.Base(isoPath)
+}
+
 // ExecuteRemoteCommand executes a command on the remote host
 func (m *Manager) ExecuteRemoteCommand(ctx context.Context, command string) error {
 	m.logger.LogInfo("Executing remote command: %s", command)
@@ -139,7 +162,7 @@ func (m *Manager) ExecuteRemoteCommand(ctx context.Context, command string) erro
 		fmt.Sprintf("%s@%s", m.config.Remote.User, m.config.Remote.Host),
 		command)
 
-	output, err := cmd.CombinedOutput()
+	output, err := m.runner.CombinedOutput(cmd)
 	if err != nil {
 		m.logger.LogError("Remote command failed: %s", string(output))
 		return fmt.Errorf("remote command failed: %w", err)
@@ -160,7 +183,7 @@ func (m *Manager) TestSSHConnection(ctx context.Context) error {
 		fmt.Sprintf("%s@%s", m.config.Remote.User, m.config.Remote.Host),
 		"echo 'SSH connection successful'")
 
-	output, err := cmd.CombinedOutput()
+	output, err := m.runner.CombinedOutput(cmd)
 	if err != nil {
 		m.logger.LogError("SSH connection test failed: %s", string(output))
 		return fmt.Errorf("SSH connection test failed: %w", err)
@@ -188,7 +188,9 @@ test_invalid_command() {
         return 1
     fi
     
-    if "$binary" invalid-command 2>&1 | grep -q "Usage:"; then
+    local status=0
+    "$binary" invalid-command >/dev/null 2>&1 || status=$?
+    if [ "$status" -eq 2 ]; then
         log_success "Invalid command handling works correctly"
     else
         log_error "Invalid command handling failed"